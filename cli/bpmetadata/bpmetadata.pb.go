@@ -1436,8 +1436,10 @@ type BlueprintQuotaDetail struct {
 	// values. Valid keys for quota_type can be:
 	// MACHINE_TYPE,
 	// CPUs,
-	// DISK_TYPE OR
-	// SIZE_GB.
+	// DISK_TYPE,
+	// SIZE_GB,
+	// GPU_TYPE OR
+	// GPUs.
 	// Gen: manually-authored
 	QuotaType map[string]string `protobuf:"bytes,3,rep,name=quota_type,json=quotaType,proto3" json:"quotaType" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3" yaml:"quotaType"` // @gotags: json:"quotaType" yaml:"quotaType"
 }
@@ -2037,6 +2039,16 @@ type BlueprintVariable struct {
 	// connected to this variable.
 	// Gen: manually-authored.
 	Connections []*BlueprintConnection `protobuf:"bytes,6,rep,name=connections,proto3" json:"connections,omitempty" yaml:"connections,omitempty"` // @gotags: json:"connections,omitempty" yaml:"connections,omitempty"
+	// Whether the variable value is sensitive and must be masked when shown.
+	// Gen: auto-generated - From the `sensitive` attribute of the variable.
+	Sensitive bool `protobuf:"varint,7,opt,name=sensitive,proto3" json:"sensitive,omitempty" yaml:"sensitive,omitempty"` // @gotags: json:"sensitive,omitempty" yaml:"sensitive,omitempty"
+	// Whether null is an accepted value of the variable. Unset if the variable
+	// does not declare it, in which case null is accepted.
+	// Gen: auto-generated - From the `nullable` attribute of the variable.
+	Nullable *bool `protobuf:"varint,8,opt,name=nullable,proto3,oneof" json:"nullable,omitempty" yaml:"nullable,omitempty"` // @gotags: json:"nullable,omitempty" yaml:"nullable,omitempty"
+	// Validation rules of the variable.
+	// Gen: auto-generated - From the `validation` blocks of the variable.
+	Validations []*BlueprintVariableValidation `protobuf:"bytes,9,rep,name=validations,proto3" json:"validations,omitempty" yaml:"validations,omitempty"` // @gotags: json:"validations,omitempty" yaml:"validations,omitempty"
 }
 
 func (x *BlueprintVariable) Reset() {
@@ -2113,6 +2125,85 @@ func (x *BlueprintVariable) GetConnections() []*BlueprintConnection {
 	return nil
 }
 
+func (x *BlueprintVariable) GetSensitive() bool {
+	if x != nil {
+		return x.Sensitive
+	}
+	return false
+}
+
+func (x *BlueprintVariable) GetNullable() bool {
+	if x != nil && x.Nullable != nil {
+		return *x.Nullable
+	}
+	return false
+}
+
+func (x *BlueprintVariable) GetValidations() []*BlueprintVariableValidation {
+	if x != nil {
+		return x.Validations
+	}
+	return nil
+}
+
+// BlueprintVariableValidation is a validation rule of a variable.
+type BlueprintVariableValidation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Condition expression as authored e.g. length(var.name) <= 30.
+	Condition string `protobuf:"bytes,1,opt,name=condition,proto3" json:"condition" yaml:"condition"` // @gotags: json:"condition" yaml:"condition"
+	// Error message shown if the condition is not met.
+	ErrorMessage string `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"errorMessage,omitempty" yaml:"errorMessage,omitempty"` // @gotags: json:"errorMessage,omitempty" yaml:"errorMessage,omitempty"
+}
+
+func (x *BlueprintVariableValidation) Reset() {
+	*x = BlueprintVariableValidation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bpmetadata_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlueprintVariableValidation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlueprintVariableValidation) ProtoMessage() {}
+
+func (x *BlueprintVariableValidation) ProtoReflect() protoreflect.Message {
+	mi := &file_bpmetadata_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlueprintVariableValidation.ProtoReflect.Descriptor instead.
+func (*BlueprintVariableValidation) Descriptor() ([]byte, []int) {
+	return file_bpmetadata_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BlueprintVariableValidation) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *BlueprintVariableValidation) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
 // Defines an incoming connection from a blueprint.
 type BlueprintConnection struct {
 	state         protoimpl.MessageState
@@ -2130,7 +2221,7 @@ type BlueprintConnection struct {
 func (x *BlueprintConnection) Reset() {
 	*x = BlueprintConnection{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bpmetadata_proto_msgTypes[26]
+		mi := &file_bpmetadata_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2143,7 +2234,7 @@ func (x *BlueprintConnection) String() string {
 func (*BlueprintConnection) ProtoMessage() {}
 
 func (x *BlueprintConnection) ProtoReflect() protoreflect.Message {
-	mi := &file_bpmetadata_proto_msgTypes[26]
+	mi := &file_bpmetadata_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2156,7 +2247,7 @@ func (x *BlueprintConnection) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlueprintConnection.ProtoReflect.Descriptor instead.
 func (*BlueprintConnection) Descriptor() ([]byte, []int) {
-	return file_bpmetadata_proto_rawDescGZIP(), []int{26}
+	return file_bpmetadata_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *BlueprintConnection) GetSource() *ConnectionSource {
@@ -2193,7 +2284,7 @@ type ConnectionSource struct {
 func (x *ConnectionSource) Reset() {
 	*x = ConnectionSource{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bpmetadata_proto_msgTypes[27]
+		mi := &file_bpmetadata_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2206,7 +2297,7 @@ func (x *ConnectionSource) String() string {
 func (*ConnectionSource) ProtoMessage() {}
 
 func (x *ConnectionSource) ProtoReflect() protoreflect.Message {
-	mi := &file_bpmetadata_proto_msgTypes[27]
+	mi := &file_bpmetadata_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2219,7 +2310,7 @@ func (x *ConnectionSource) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConnectionSource.ProtoReflect.Descriptor instead.
 func (*ConnectionSource) Descriptor() ([]byte, []int) {
-	return file_bpmetadata_proto_rawDescGZIP(), []int{27}
+	return file_bpmetadata_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *ConnectionSource) GetSource() string {
@@ -2254,7 +2345,7 @@ type ConnectionSpec struct {
 func (x *ConnectionSpec) Reset() {
 	*x = ConnectionSpec{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bpmetadata_proto_msgTypes[28]
+		mi := &file_bpmetadata_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2267,7 +2358,7 @@ func (x *ConnectionSpec) String() string {
 func (*ConnectionSpec) ProtoMessage() {}
 
 func (x *ConnectionSpec) ProtoReflect() protoreflect.Message {
-	mi := &file_bpmetadata_proto_msgTypes[28]
+	mi := &file_bpmetadata_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2280,7 +2371,7 @@ func (x *ConnectionSpec) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConnectionSpec.ProtoReflect.Descriptor instead.
 func (*ConnectionSpec) Descriptor() ([]byte, []int) {
-	return file_bpmetadata_proto_rawDescGZIP(), []int{28}
+	return file_bpmetadata_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *ConnectionSpec) GetOutputExpr() string {
@@ -2311,7 +2402,7 @@ type BlueprintVariableGroup struct {
 func (x *BlueprintVariableGroup) Reset() {
 	*x = BlueprintVariableGroup{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bpmetadata_proto_msgTypes[29]
+		mi := &file_bpmetadata_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2324,7 +2415,7 @@ func (x *BlueprintVariableGroup) String() string {
 func (*BlueprintVariableGroup) ProtoMessage() {}
 
 func (x *BlueprintVariableGroup) ProtoReflect() protoreflect.Message {
-	mi := &file_bpmetadata_proto_msgTypes[29]
+	mi := &file_bpmetadata_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2337,7 +2428,7 @@ func (x *BlueprintVariableGroup) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlueprintVariableGroup.ProtoReflect.Descriptor instead.
 func (*BlueprintVariableGroup) Descriptor() ([]byte, []int) {
-	return file_bpmetadata_proto_rawDescGZIP(), []int{29}
+	return file_bpmetadata_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *BlueprintVariableGroup) GetName() string {
@@ -2371,12 +2462,15 @@ type BlueprintOutput struct {
 	// Serialized type representation of the output value.
 	// Gen: manually-authored but will be automated in the future.
 	Type *structpb.Value `protobuf:"bytes,3,opt,name=type,proto3,oneof" json:"type,omitempty" yaml:"type,omitempty"` // @gotags: json:"type,omitempty" yaml:"type,omitempty"
+	// Whether the output value is sensitive and must be masked when shown.
+	// Gen: auto-generated - From the `sensitive` attribute of the output.
+	Sensitive bool `protobuf:"varint,4,opt,name=sensitive,proto3" json:"sensitive,omitempty" yaml:"sensitive,omitempty"` // @gotags: json:"sensitive,omitempty" yaml:"sensitive,omitempty"
 }
 
 func (x *BlueprintOutput) Reset() {
 	*x = BlueprintOutput{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bpmetadata_proto_msgTypes[30]
+		mi := &file_bpmetadata_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2389,7 +2483,7 @@ func (x *BlueprintOutput) String() string {
 func (*BlueprintOutput) ProtoMessage() {}
 
 func (x *BlueprintOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_bpmetadata_proto_msgTypes[30]
+	mi := &file_bpmetadata_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2402,7 +2496,7 @@ func (x *BlueprintOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlueprintOutput.ProtoReflect.Descriptor instead.
 func (*BlueprintOutput) Descriptor() ([]byte, []int) {
-	return file_bpmetadata_proto_rawDescGZIP(), []int{30}
+	return file_bpmetadata_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *BlueprintOutput) GetName() string {
@@ -2426,6 +2520,13 @@ func (x *BlueprintOutput) GetType() *structpb.Value {
 	return nil
 }
 
+func (x *BlueprintOutput) GetSensitive() bool {
+	if x != nil {
+		return x.Sensitive
+	}
+	return false
+}
+
 type BlueprintRoles struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -2438,7 +2539,7 @@ type BlueprintRoles struct {
 func (x *BlueprintRoles) Reset() {
 	*x = BlueprintRoles{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bpmetadata_proto_msgTypes[31]
+		mi := &file_bpmetadata_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2451,7 +2552,7 @@ func (x *BlueprintRoles) String() string {
 func (*BlueprintRoles) ProtoMessage() {}
 
 func (x *BlueprintRoles) ProtoReflect() protoreflect.Message {
-	mi := &file_bpmetadata_proto_msgTypes[31]
+	mi := &file_bpmetadata_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2464,7 +2565,7 @@ func (x *BlueprintRoles) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlueprintRoles.ProtoReflect.Descriptor instead.
 func (*BlueprintRoles) Descriptor() ([]byte, []int) {
-	return file_bpmetadata_proto_rawDescGZIP(), []int{31}
+	return file_bpmetadata_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *BlueprintRoles) GetLevel() string {
@@ -2819,7 +2920,7 @@ var file_bpmetadata_proto_rawDesc = []byte{
 	0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74,
 	0x69, 0x74, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c,
 	0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
-	0x75, 0x72, 0x6c, 0x22, 0x94, 0x02, 0x0a, 0x11, 0x42, 0x6c, 0x75, 0x65, 0x70, 0x72, 0x69, 0x6e,
+	0x75, 0x72, 0x6c, 0x22, 0xbf, 0x03, 0x0a, 0x11, 0x42, 0x6c, 0x75, 0x65, 0x70, 0x72, 0x69, 0x6e,
 	0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
 	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a,
 	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
@@ -2836,62 +2937,81 @@ var file_bpmetadata_proto_rawDesc = []byte{
 	0x65, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x62,
 	0x70, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x42, 0x6c, 0x75, 0x65, 0x70, 0x72,
 	0x69, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63,
-	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x13, 0x42,
-	0x6c, 0x75, 0x65, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x48, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x30, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x6c, 0x6f, 0x75,
-	0x64, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x62, 0x70, 0x6d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x42, 0x0a, 0x04,
-	0x73, 0x70, 0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x2e, 0x62, 0x70, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x43, 0x6f, 0x6e, 0x6e,
-	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x70, 0x65, 0x63, 0x52, 0x04, 0x73, 0x70, 0x65, 0x63,
-	0x22, 0x44, 0x0a, 0x10, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07,
-	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x64, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x70, 0x65, 0x63, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70,
-	0x75, 0x74, 0x5f, 0x65, 0x78, 0x70, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f,
-	0x75, 0x74, 0x70, 0x75, 0x74, 0x45, 0x78, 0x70, 0x72, 0x12, 0x22, 0x0a, 0x0a, 0x69, 0x6e, 0x70,
-	0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
-	0x09, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a,
-	0x0b, 0x5f, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x22, 0x6c, 0x0a, 0x16,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65,
+	0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73,
+	0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x12, 0x1f, 0x0a, 0x08, 0x6e, 0x75, 0x6c, 0x6c,
+	0x61, 0x62, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x08, 0x6e, 0x75,
+	0x6c, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x5d, 0x0a, 0x0b, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3b,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x62, 0x70, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e,
 	0x42, 0x6c, 0x75, 0x65, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c,
-	0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09,
-	0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x22, 0x81, 0x01, 0x0a, 0x0f, 0x42,
-	0x6c, 0x75, 0x65, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x12,
-	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x48, 0x00, 0x52, 0x04, 0x74, 0x79,
-	0x70, 0x65, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x22, 0x3c,
-	0x0a, 0x0e, 0x42, 0x6c, 0x75, 0x65, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x73,
-	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x2a, 0x6a, 0x0a, 0x11,
-	0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x11, 0x0a, 0x0d, 0x51, 0x52, 0x54, 0x5f, 0x55, 0x4e, 0x44, 0x45, 0x46, 0x49, 0x4e,
-	0x45, 0x44, 0x10, 0x00, 0x12, 0x22, 0x0a, 0x1e, 0x51, 0x52, 0x54, 0x5f, 0x52, 0x45, 0x53, 0x4f,
-	0x55, 0x52, 0x43, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x47, 0x43, 0x45, 0x5f, 0x49, 0x4e,
-	0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x51, 0x52, 0x54, 0x5f,
-	0x52, 0x45, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x47, 0x43,
-	0x45, 0x5f, 0x44, 0x49, 0x53, 0x4b, 0x10, 0x02, 0x2a, 0x32, 0x0a, 0x11, 0x53, 0x6f, 0x66, 0x74,
-	0x77, 0x61, 0x72, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a,
-	0x0e, 0x53, 0x47, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
-	0x00, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x47, 0x5f, 0x4f, 0x53, 0x10, 0x01, 0x42, 0x48, 0x5a, 0x46,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x47, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x2f, 0x63,
-	0x6c, 0x6f, 0x75, 0x64, 0x2d, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2d,
-	0x74, 0x6f, 0x6f, 0x6c, 0x6b, 0x69, 0x74, 0x2f, 0x63, 0x6c, 0x69, 0x2f, 0x62, 0x70, 0x6d, 0x65,
-	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6e, 0x75, 0x6c,
+	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x22, 0x60, 0x0a, 0x1b, 0x42, 0x6c, 0x75, 0x65, 0x70, 0x72, 0x69,
+	0x6e, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xa3, 0x01, 0x0a, 0x13, 0x42, 0x6c, 0x75, 0x65,
+	0x70, 0x72, 0x69, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x48, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x30, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x62, 0x70, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x42, 0x0a, 0x04, 0x73, 0x70, 0x65,
+	0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x62, 0x70,
+	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x70, 0x65, 0x63, 0x52, 0x04, 0x73, 0x70, 0x65, 0x63, 0x22, 0x44, 0x0a,
+	0x10, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x22, 0x64, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x53, 0x70, 0x65, 0x63, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f,
+	0x65, 0x78, 0x70, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x45, 0x78, 0x70, 0x72, 0x12, 0x22, 0x0a, 0x0a, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x5f,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x69, 0x6e,
+	0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x69,
+	0x6e, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x22, 0x6c, 0x0a, 0x16, 0x42, 0x6c, 0x75,
+	0x65, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x61, 0x72,
+	0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x76, 0x61,
+	0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x22, 0x9f, 0x01, 0x0a, 0x0f, 0x42, 0x6c, 0x75, 0x65,
+	0x70, 0x72, 0x69, 0x6e, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x2f, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x48, 0x00, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65,
+	0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x22, 0x3c, 0x0a, 0x0e, 0x42, 0x6c, 0x75,
+	0x65, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65,
+	0x6c, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x2a, 0x6a, 0x0a, 0x11, 0x51, 0x75, 0x6f, 0x74, 0x61,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d,
+	0x51, 0x52, 0x54, 0x5f, 0x55, 0x4e, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x22, 0x0a, 0x1e, 0x51, 0x52, 0x54, 0x5f, 0x52, 0x45, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x47, 0x43, 0x45, 0x5f, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43,
+	0x45, 0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x51, 0x52, 0x54, 0x5f, 0x52, 0x45, 0x53, 0x4f, 0x55,
+	0x52, 0x43, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x47, 0x43, 0x45, 0x5f, 0x44, 0x49, 0x53,
+	0x4b, 0x10, 0x02, 0x2a, 0x32, 0x0a, 0x11, 0x53, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x47, 0x5f, 0x55,
+	0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05,
+	0x53, 0x47, 0x5f, 0x4f, 0x53, 0x10, 0x01, 0x42, 0x48, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x47, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x43, 0x6c, 0x6f, 0x75,
+	0x64, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2d,
+	0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2d, 0x74, 0x6f, 0x6f, 0x6c, 0x6b,
+	0x69, 0x74, 0x2f, 0x63, 0x6c, 0x69, 0x2f, 0x62, 0x70, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2907,54 +3027,55 @@ func file_bpmetadata_proto_rawDescGZIP() []byte {
 }
 
 var file_bpmetadata_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_bpmetadata_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
+var file_bpmetadata_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_bpmetadata_proto_goTypes = []interface{}{
-	(QuotaResourceType)(0),          // 0: google.cloud.config.bpmetadata.QuotaResourceType
-	(SoftwareGroupType)(0),          // 1: google.cloud.config.bpmetadata.SoftwareGroupType
-	(*BlueprintMetadata)(nil),       // 2: google.cloud.config.bpmetadata.BlueprintMetadata
-	(*ResourceTypeMeta)(nil),        // 3: google.cloud.config.bpmetadata.ResourceTypeMeta
-	(*BlueprintMetadataSpec)(nil),   // 4: google.cloud.config.bpmetadata.BlueprintMetadataSpec
-	(*BlueprintInfo)(nil),           // 5: google.cloud.config.bpmetadata.BlueprintInfo
-	(*BlueprintContent)(nil),        // 6: google.cloud.config.bpmetadata.BlueprintContent
-	(*BlueprintInterface)(nil),      // 7: google.cloud.config.bpmetadata.BlueprintInterface
-	(*BlueprintRequirements)(nil),   // 8: google.cloud.config.bpmetadata.BlueprintRequirements
-	(*ProviderVersion)(nil),         // 9: google.cloud.config.bpmetadata.ProviderVersion
-	(*BlueprintUI)(nil),             // 10: google.cloud.config.bpmetadata.BlueprintUI
-	(*BlueprintRepoDetail)(nil),     // 11: google.cloud.config.bpmetadata.BlueprintRepoDetail
-	(*BlueprintActuationTool)(nil),  // 12: google.cloud.config.bpmetadata.BlueprintActuationTool
-	(*BlueprintDescription)(nil),    // 13: google.cloud.config.bpmetadata.BlueprintDescription
-	(*BlueprintTimeEstimate)(nil),   // 14: google.cloud.config.bpmetadata.BlueprintTimeEstimate
-	(*BlueprintCostEstimate)(nil),   // 15: google.cloud.config.bpmetadata.BlueprintCostEstimate
-	(*BlueprintCloudProduct)(nil),   // 16: google.cloud.config.bpmetadata.BlueprintCloudProduct
-	(*BlueprintOrgPolicyCheck)(nil), // 17: google.cloud.config.bpmetadata.BlueprintOrgPolicyCheck
-	(*BlueprintQuotaDetail)(nil),    // 18: google.cloud.config.bpmetadata.BlueprintQuotaDetail
-	(*BlueprintAuthor)(nil),         // 19: google.cloud.config.bpmetadata.BlueprintAuthor
-	(*BlueprintSoftwareGroup)(nil),  // 20: google.cloud.config.bpmetadata.BlueprintSoftwareGroup
-	(*BlueprintSoftware)(nil),       // 21: google.cloud.config.bpmetadata.BlueprintSoftware
-	(*BlueprintSupport)(nil),        // 22: google.cloud.config.bpmetadata.BlueprintSupport
-	(*BlueprintArchitecture)(nil),   // 23: google.cloud.config.bpmetadata.BlueprintArchitecture
-	(*BlueprintMiscContent)(nil),    // 24: google.cloud.config.bpmetadata.BlueprintMiscContent
-	(*BlueprintDiagram)(nil),        // 25: google.cloud.config.bpmetadata.BlueprintDiagram
-	(*BlueprintListContent)(nil),    // 26: google.cloud.config.bpmetadata.BlueprintListContent
-	(*BlueprintVariable)(nil),       // 27: google.cloud.config.bpmetadata.BlueprintVariable
-	(*BlueprintConnection)(nil),     // 28: google.cloud.config.bpmetadata.BlueprintConnection
-	(*ConnectionSource)(nil),        // 29: google.cloud.config.bpmetadata.ConnectionSource
-	(*ConnectionSpec)(nil),          // 30: google.cloud.config.bpmetadata.ConnectionSpec
-	(*BlueprintVariableGroup)(nil),  // 31: google.cloud.config.bpmetadata.BlueprintVariableGroup
-	(*BlueprintOutput)(nil),         // 32: google.cloud.config.bpmetadata.BlueprintOutput
-	(*BlueprintRoles)(nil),          // 33: google.cloud.config.bpmetadata.BlueprintRoles
-	nil,                             // 34: google.cloud.config.bpmetadata.ResourceTypeMeta.LabelsEntry
-	nil,                             // 35: google.cloud.config.bpmetadata.ResourceTypeMeta.AnnotationsEntry
-	nil,                             // 36: google.cloud.config.bpmetadata.BlueprintQuotaDetail.QuotaTypeEntry
-	(*BlueprintUIInput)(nil),        // 37: google.cloud.config.bpmetadata.BlueprintUIInput
-	(*BlueprintUIOutput)(nil),       // 38: google.cloud.config.bpmetadata.BlueprintUIOutput
-	(*structpb.Value)(nil),          // 39: google.protobuf.Value
+	(QuotaResourceType)(0),              // 0: google.cloud.config.bpmetadata.QuotaResourceType
+	(SoftwareGroupType)(0),              // 1: google.cloud.config.bpmetadata.SoftwareGroupType
+	(*BlueprintMetadata)(nil),           // 2: google.cloud.config.bpmetadata.BlueprintMetadata
+	(*ResourceTypeMeta)(nil),            // 3: google.cloud.config.bpmetadata.ResourceTypeMeta
+	(*BlueprintMetadataSpec)(nil),       // 4: google.cloud.config.bpmetadata.BlueprintMetadataSpec
+	(*BlueprintInfo)(nil),               // 5: google.cloud.config.bpmetadata.BlueprintInfo
+	(*BlueprintContent)(nil),            // 6: google.cloud.config.bpmetadata.BlueprintContent
+	(*BlueprintInterface)(nil),          // 7: google.cloud.config.bpmetadata.BlueprintInterface
+	(*BlueprintRequirements)(nil),       // 8: google.cloud.config.bpmetadata.BlueprintRequirements
+	(*ProviderVersion)(nil),             // 9: google.cloud.config.bpmetadata.ProviderVersion
+	(*BlueprintUI)(nil),                 // 10: google.cloud.config.bpmetadata.BlueprintUI
+	(*BlueprintRepoDetail)(nil),         // 11: google.cloud.config.bpmetadata.BlueprintRepoDetail
+	(*BlueprintActuationTool)(nil),      // 12: google.cloud.config.bpmetadata.BlueprintActuationTool
+	(*BlueprintDescription)(nil),        // 13: google.cloud.config.bpmetadata.BlueprintDescription
+	(*BlueprintTimeEstimate)(nil),       // 14: google.cloud.config.bpmetadata.BlueprintTimeEstimate
+	(*BlueprintCostEstimate)(nil),       // 15: google.cloud.config.bpmetadata.BlueprintCostEstimate
+	(*BlueprintCloudProduct)(nil),       // 16: google.cloud.config.bpmetadata.BlueprintCloudProduct
+	(*BlueprintOrgPolicyCheck)(nil),     // 17: google.cloud.config.bpmetadata.BlueprintOrgPolicyCheck
+	(*BlueprintQuotaDetail)(nil),        // 18: google.cloud.config.bpmetadata.BlueprintQuotaDetail
+	(*BlueprintAuthor)(nil),             // 19: google.cloud.config.bpmetadata.BlueprintAuthor
+	(*BlueprintSoftwareGroup)(nil),      // 20: google.cloud.config.bpmetadata.BlueprintSoftwareGroup
+	(*BlueprintSoftware)(nil),           // 21: google.cloud.config.bpmetadata.BlueprintSoftware
+	(*BlueprintSupport)(nil),            // 22: google.cloud.config.bpmetadata.BlueprintSupport
+	(*BlueprintArchitecture)(nil),       // 23: google.cloud.config.bpmetadata.BlueprintArchitecture
+	(*BlueprintMiscContent)(nil),        // 24: google.cloud.config.bpmetadata.BlueprintMiscContent
+	(*BlueprintDiagram)(nil),            // 25: google.cloud.config.bpmetadata.BlueprintDiagram
+	(*BlueprintListContent)(nil),        // 26: google.cloud.config.bpmetadata.BlueprintListContent
+	(*BlueprintVariable)(nil),           // 27: google.cloud.config.bpmetadata.BlueprintVariable
+	(*BlueprintVariableValidation)(nil), // 28: google.cloud.config.bpmetadata.BlueprintVariableValidation
+	(*BlueprintConnection)(nil),         // 29: google.cloud.config.bpmetadata.BlueprintConnection
+	(*ConnectionSource)(nil),            // 30: google.cloud.config.bpmetadata.ConnectionSource
+	(*ConnectionSpec)(nil),              // 31: google.cloud.config.bpmetadata.ConnectionSpec
+	(*BlueprintVariableGroup)(nil),      // 32: google.cloud.config.bpmetadata.BlueprintVariableGroup
+	(*BlueprintOutput)(nil),             // 33: google.cloud.config.bpmetadata.BlueprintOutput
+	(*BlueprintRoles)(nil),              // 34: google.cloud.config.bpmetadata.BlueprintRoles
+	nil,                                 // 35: google.cloud.config.bpmetadata.ResourceTypeMeta.LabelsEntry
+	nil,                                 // 36: google.cloud.config.bpmetadata.ResourceTypeMeta.AnnotationsEntry
+	nil,                                 // 37: google.cloud.config.bpmetadata.BlueprintQuotaDetail.QuotaTypeEntry
+	(*BlueprintUIInput)(nil),            // 38: google.cloud.config.bpmetadata.BlueprintUIInput
+	(*BlueprintUIOutput)(nil),           // 39: google.cloud.config.bpmetadata.BlueprintUIOutput
+	(*structpb.Value)(nil),              // 40: google.protobuf.Value
 }
 var file_bpmetadata_proto_depIdxs = []int32{
 	3,  // 0: google.cloud.config.bpmetadata.BlueprintMetadata.metadata:type_name -> google.cloud.config.bpmetadata.ResourceTypeMeta
 	4,  // 1: google.cloud.config.bpmetadata.BlueprintMetadata.spec:type_name -> google.cloud.config.bpmetadata.BlueprintMetadataSpec
-	34, // 2: google.cloud.config.bpmetadata.ResourceTypeMeta.labels:type_name -> google.cloud.config.bpmetadata.ResourceTypeMeta.LabelsEntry
-	35, // 3: google.cloud.config.bpmetadata.ResourceTypeMeta.annotations:type_name -> google.cloud.config.bpmetadata.ResourceTypeMeta.AnnotationsEntry
+	35, // 2: google.cloud.config.bpmetadata.ResourceTypeMeta.labels:type_name -> google.cloud.config.bpmetadata.ResourceTypeMeta.LabelsEntry
+	36, // 3: google.cloud.config.bpmetadata.ResourceTypeMeta.annotations:type_name -> google.cloud.config.bpmetadata.ResourceTypeMeta.AnnotationsEntry
 	5,  // 4: google.cloud.config.bpmetadata.BlueprintMetadataSpec.info:type_name -> google.cloud.config.bpmetadata.BlueprintInfo
 	6,  // 5: google.cloud.config.bpmetadata.BlueprintMetadataSpec.content:type_name -> google.cloud.config.bpmetadata.BlueprintContent
 	7,  // 6: google.cloud.config.bpmetadata.BlueprintMetadataSpec.interfaces:type_name -> google.cloud.config.bpmetadata.BlueprintInterface
@@ -2977,26 +3098,27 @@ var file_bpmetadata_proto_depIdxs = []int32{
 	24, // 23: google.cloud.config.bpmetadata.BlueprintContent.sub_blueprints:type_name -> google.cloud.config.bpmetadata.BlueprintMiscContent
 	24, // 24: google.cloud.config.bpmetadata.BlueprintContent.examples:type_name -> google.cloud.config.bpmetadata.BlueprintMiscContent
 	27, // 25: google.cloud.config.bpmetadata.BlueprintInterface.variables:type_name -> google.cloud.config.bpmetadata.BlueprintVariable
-	31, // 26: google.cloud.config.bpmetadata.BlueprintInterface.variable_groups:type_name -> google.cloud.config.bpmetadata.BlueprintVariableGroup
-	32, // 27: google.cloud.config.bpmetadata.BlueprintInterface.outputs:type_name -> google.cloud.config.bpmetadata.BlueprintOutput
-	33, // 28: google.cloud.config.bpmetadata.BlueprintRequirements.roles:type_name -> google.cloud.config.bpmetadata.BlueprintRoles
+	32, // 26: google.cloud.config.bpmetadata.BlueprintInterface.variable_groups:type_name -> google.cloud.config.bpmetadata.BlueprintVariableGroup
+	33, // 27: google.cloud.config.bpmetadata.BlueprintInterface.outputs:type_name -> google.cloud.config.bpmetadata.BlueprintOutput
+	34, // 28: google.cloud.config.bpmetadata.BlueprintRequirements.roles:type_name -> google.cloud.config.bpmetadata.BlueprintRoles
 	9,  // 29: google.cloud.config.bpmetadata.BlueprintRequirements.provider_versions:type_name -> google.cloud.config.bpmetadata.ProviderVersion
-	37, // 30: google.cloud.config.bpmetadata.BlueprintUI.input:type_name -> google.cloud.config.bpmetadata.BlueprintUIInput
-	38, // 31: google.cloud.config.bpmetadata.BlueprintUI.runtime:type_name -> google.cloud.config.bpmetadata.BlueprintUIOutput
+	38, // 30: google.cloud.config.bpmetadata.BlueprintUI.input:type_name -> google.cloud.config.bpmetadata.BlueprintUIInput
+	39, // 31: google.cloud.config.bpmetadata.BlueprintUI.runtime:type_name -> google.cloud.config.bpmetadata.BlueprintUIOutput
 	0,  // 32: google.cloud.config.bpmetadata.BlueprintQuotaDetail.resource_type:type_name -> google.cloud.config.bpmetadata.QuotaResourceType
-	36, // 33: google.cloud.config.bpmetadata.BlueprintQuotaDetail.quota_type:type_name -> google.cloud.config.bpmetadata.BlueprintQuotaDetail.QuotaTypeEntry
+	37, // 33: google.cloud.config.bpmetadata.BlueprintQuotaDetail.quota_type:type_name -> google.cloud.config.bpmetadata.BlueprintQuotaDetail.QuotaTypeEntry
 	1,  // 34: google.cloud.config.bpmetadata.BlueprintSoftwareGroup.type:type_name -> google.cloud.config.bpmetadata.SoftwareGroupType
 	21, // 35: google.cloud.config.bpmetadata.BlueprintSoftwareGroup.software:type_name -> google.cloud.config.bpmetadata.BlueprintSoftware
-	39, // 36: google.cloud.config.bpmetadata.BlueprintVariable.default_value:type_name -> google.protobuf.Value
-	28, // 37: google.cloud.config.bpmetadata.BlueprintVariable.connections:type_name -> google.cloud.config.bpmetadata.BlueprintConnection
-	29, // 38: google.cloud.config.bpmetadata.BlueprintConnection.source:type_name -> google.cloud.config.bpmetadata.ConnectionSource
-	30, // 39: google.cloud.config.bpmetadata.BlueprintConnection.spec:type_name -> google.cloud.config.bpmetadata.ConnectionSpec
-	39, // 40: google.cloud.config.bpmetadata.BlueprintOutput.type:type_name -> google.protobuf.Value
-	41, // [41:41] is the sub-list for method output_type
-	41, // [41:41] is the sub-list for method input_type
-	41, // [41:41] is the sub-list for extension type_name
-	41, // [41:41] is the sub-list for extension extendee
-	0,  // [0:41] is the sub-list for field type_name
+	40, // 36: google.cloud.config.bpmetadata.BlueprintVariable.default_value:type_name -> google.protobuf.Value
+	29, // 37: google.cloud.config.bpmetadata.BlueprintVariable.connections:type_name -> google.cloud.config.bpmetadata.BlueprintConnection
+	28, // 38: google.cloud.config.bpmetadata.BlueprintVariable.validations:type_name -> google.cloud.config.bpmetadata.BlueprintVariableValidation
+	30, // 39: google.cloud.config.bpmetadata.BlueprintConnection.source:type_name -> google.cloud.config.bpmetadata.ConnectionSource
+	31, // 40: google.cloud.config.bpmetadata.BlueprintConnection.spec:type_name -> google.cloud.config.bpmetadata.ConnectionSpec
+	40, // 41: google.cloud.config.bpmetadata.BlueprintOutput.type:type_name -> google.protobuf.Value
+	42, // [42:42] is the sub-list for method output_type
+	42, // [42:42] is the sub-list for method input_type
+	42, // [42:42] is the sub-list for extension type_name
+	42, // [42:42] is the sub-list for extension extendee
+	0,  // [0:42] is the sub-list for field type_name
 }
 
 func init() { file_bpmetadata_proto_init() }
@@ -3319,7 +3441,7 @@ func file_bpmetadata_proto_init() {
 			}
 		}
 		file_bpmetadata_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlueprintConnection); i {
+			switch v := v.(*BlueprintVariableValidation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3331,7 +3453,7 @@ func file_bpmetadata_proto_init() {
 			}
 		}
 		file_bpmetadata_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConnectionSource); i {
+			switch v := v.(*BlueprintConnection); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3343,7 +3465,7 @@ func file_bpmetadata_proto_init() {
 			}
 		}
 		file_bpmetadata_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConnectionSpec); i {
+			switch v := v.(*ConnectionSource); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3355,7 +3477,7 @@ func file_bpmetadata_proto_init() {
 			}
 		}
 		file_bpmetadata_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlueprintVariableGroup); i {
+			switch v := v.(*ConnectionSpec); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3367,7 +3489,7 @@ func file_bpmetadata_proto_init() {
 			}
 		}
 		file_bpmetadata_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlueprintOutput); i {
+			switch v := v.(*BlueprintVariableGroup); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3379,6 +3501,18 @@ func file_bpmetadata_proto_init() {
 			}
 		}
 		file_bpmetadata_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlueprintOutput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bpmetadata_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BlueprintRoles); i {
 			case 0:
 				return &v.state
@@ -3391,15 +3525,16 @@ func file_bpmetadata_proto_init() {
 			}
 		}
 	}
-	file_bpmetadata_proto_msgTypes[28].OneofWrappers = []interface{}{}
-	file_bpmetadata_proto_msgTypes[30].OneofWrappers = []interface{}{}
+	file_bpmetadata_proto_msgTypes[25].OneofWrappers = []interface{}{}
+	file_bpmetadata_proto_msgTypes[29].OneofWrappers = []interface{}{}
+	file_bpmetadata_proto_msgTypes[31].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_bpmetadata_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   35,
+			NumMessages:   36,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
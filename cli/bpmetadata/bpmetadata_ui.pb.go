@@ -275,25 +275,25 @@ type DisplayVariable struct {
 	// Gen: manually-authored
 	Placeholder string `protobuf:"bytes,5,opt,name=placeholder,proto3" json:"placeholder,omitempty" yaml:"placeholder,omitempty"` // @gotags: json:"placeholder,omitempty" yaml:"placeholder,omitempty"
 	// Regex based validation rules for the variable.
-	// Gen: manually-authored
+	// Gen: auto-generated - derived from Terraform variable validation blocks
 	RegexValidation string `protobuf:"bytes,6,opt,name=regex_validation,json=regexValidation,proto3" json:"regexValidation,omitempty" yaml:"regexValidation,omitempty"` // @gotags: json:"regexValidation,omitempty" yaml:"regexValidation,omitempty"
 	// Minimum no. of inputs for the input variable.
-	// Gen: manually-authored
+	// Gen: auto-generated - derived from Terraform variable validation blocks
 	MinItems int32 `protobuf:"varint,7,opt,name=min_items,json=minItems,proto3" json:"minItems,omitempty" yaml:"minItems,omitempty"` // @gotags: json:"minItems,omitempty" yaml:"minItems,omitempty"
 	// Max no. of inputs for the input variable.
-	// Gen: manually-authored
+	// Gen: auto-generated - derived from Terraform variable validation blocks
 	MaxItems int32 `protobuf:"varint,8,opt,name=max_items,json=maxItems,proto3" json:"maxItems,omitempty" yaml:"maxItems,omitempty"` // @gotags: json:"maxItems,omitempty" yaml:"maxItems,omitempty"
 	// Minimum length for string values.
-	// Gen: manually-authored
+	// Gen: auto-generated - derived from Terraform variable validation blocks
 	MinLength int32 `protobuf:"varint,9,opt,name=min_length,json=minLength,proto3" json:"minLength,omitempty" yaml:"minLength,omitempty"` // @gotags: json:"minLength,omitempty" yaml:"minLength,omitempty"
 	// Max length for string values.
-	// Gen: manually-authored
+	// Gen: auto-generated - derived from Terraform variable validation blocks
 	MaxLength int32 `protobuf:"varint,10,opt,name=max_length,json=maxLength,proto3" json:"maxLength,omitempty" yaml:"maxLength,omitempty"` // @gotags: json:"maxLength,omitempty" yaml:"maxLength,omitempty"
 	// Minimum value for numeric types.
-	// Gen: manually-authored
+	// Gen: auto-generated - derived from Terraform variable validation blocks
 	Min float32 `protobuf:"fixed32,11,opt,name=min,proto3" json:"min,omitempty" yaml:"min,omitempty"` // @gotags: json:"min,omitempty" yaml:"min,omitempty"
 	// Max value for numeric types.
-	// Gen: manually-authored
+	// Gen: auto-generated - derived from Terraform variable validation blocks
 	Max float32 `protobuf:"fixed32,12,opt,name=max,proto3" json:"max,omitempty" yaml:"max,omitempty"` // @gotags: json:"max,omitempty" yaml:"max,omitempty"
 	// The name of a section to which this variable belongs.
 	// variables belong to the root section if this field is
@@ -316,14 +316,14 @@ type DisplayVariable struct {
 	// Text describing the validation rules for the property. Typically shown
 	// after an invalid input.
 	// Optional. UTF-8 text. No markup. At most 128 characters.
-	// Gen: manually-authored
+	// Gen: auto-generated - derived from Terraform variable validation blocks
 	Validation string `protobuf:"bytes,15,opt,name=validation,proto3" json:"validation,omitempty" yaml:"validation,omitempty"` // @gotags: json:"validation,omitempty" yaml:"validation,omitempty"
 	// Property subtext, displayed below the title.
 	// Gen: manually-authored
 	Subtext string `protobuf:"bytes,16,opt,name=subtext,proto3" json:"subtext,omitempty" yaml:"subtext,omitempty"` // @gotags: json:"subtext,omitempty" yaml:"subtext,omitempty"
 	// Labels for enum values.
 	// Values must be UTF-8 text with no markup, and at most 64 characters.
-	// Gen: manually-authored
+	// Gen: auto-generated - derived from Terraform variable validation blocks
 	EnumValueLabels []*ValueLabel `protobuf:"bytes,17,rep,name=enum_value_labels,json=enumValueLabels,proto3" json:"enumValueLabels,omitempty" yaml:"enumValueLabels,omitempty"` // @gotags: json:"enumValueLabels,omitempty" yaml:"enumValueLabels,omitempty"
 	// Indicates the "advanced" level of the input property. Level 0 (default)
 	// will always be shown. Level 1 corresponds to one expansion (user clicks
@@ -0,0 +1,69 @@
+package bpmetadata
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"google.golang.org/protobuf/proto"
+)
+
+// checkMetadata compares generated metadata against the metadata file on disk
+// and writes a unified diff to w if they differ. Files are considered up to date
+// if they are semantically equal even if their formatting differs.
+// Returns true if the file on disk is stale.
+func checkMetadata(generated *BlueprintMetadata, bpPath, fileName string, w io.Writer) (bool, error) {
+	want, err := marshalMetadata(generated)
+	if err != nil {
+		return false, err
+	}
+
+	filePath := path.Join(bpPath, fileName)
+	got, err := os.ReadFile(filePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("unable to read metadata from the existing file: %w", err)
+	}
+
+	if err == nil {
+		existing, err := UnmarshalMetadata(bpPath, fileName)
+		if err == nil && proto.Equal(existing, generated) {
+			return false, nil
+		}
+	}
+
+	fromFile := "a/" + displayPath(filePath)
+	if got == nil {
+		fromFile = os.DevNull
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(got)),
+		B:        difflib.SplitLines(string(want)),
+		FromFile: fromFile,
+		ToFile:   "b/" + displayPath(filePath),
+		Context:  3,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error creating diff for %s: %w", filePath, err)
+	}
+	if _, err := io.WriteString(w, diff); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// displayPath returns p relative to the working directory if possible.
+func displayPath(p string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return p
+	}
+	rel, err := filepath.Rel(wd, p)
+	if err != nil {
+		return p
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,96 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMetadata(t *testing.T) {
+	newMetadata := func(title string) *BlueprintMetadata {
+		return &BlueprintMetadata{
+			ApiVersion: metadataApiVersion,
+			Kind:       metadataKind,
+			Metadata:   &ResourceTypeMeta{Name: "terraform-google-foo"},
+			Spec: &BlueprintMetadataSpec{
+				Info: &BlueprintInfo{Title: title},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		existing  string
+		generated *BlueprintMetadata
+		wantStale bool
+		wantDiff  []string
+	}{
+		{
+			name:      "up to date",
+			generated: newMetadata("Foo"),
+		},
+		{
+			name: "semantically equal with different formatting",
+			existing: `apiVersion: blueprints.cloud.google.com/v1alpha1
+kind: BlueprintMetadata
+metadata: {name: terraform-google-foo}
+spec:
+    info:
+        title: "Foo"
+`,
+			generated: newMetadata("Foo"),
+		},
+		{
+			name:      "stale",
+			generated: newMetadata("Bar"),
+			wantStale: true,
+			wantDiff:  []string{"--- a/", "+++ b/", "-    title: Foo", "+    title: Bar"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bpPath := t.TempDir()
+			if tt.existing != "" {
+				if err := os.WriteFile(path.Join(bpPath, metadataFileName), []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			} else if err := WriteMetadata(newMetadata("Foo"), bpPath, metadataFileName); err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			stale, err := checkMetadata(tt.generated, bpPath, metadataFileName, &out)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStale, stale)
+			if len(tt.wantDiff) == 0 {
+				assert.Empty(t, out.String())
+			}
+			for _, d := range tt.wantDiff {
+				assert.Contains(t, out.String(), d)
+			}
+
+			// check mode must not modify metadata on disk
+			content, err := os.ReadFile(path.Join(bpPath, metadataFileName))
+			assert.NoError(t, err)
+			if tt.existing != "" {
+				assert.Equal(t, tt.existing, string(content))
+			}
+		})
+	}
+}
+
+func TestCheckMetadataMissingFile(t *testing.T) {
+	bpPath := t.TempDir()
+	var out bytes.Buffer
+	stale, err := checkMetadata(&BlueprintMetadata{ApiVersion: metadataApiVersion, Kind: metadataKind}, bpPath, metadataDisplayFileName, &out)
+	assert.NoError(t, err)
+	assert.True(t, stale)
+	assert.Contains(t, out.String(), "--- "+os.DevNull)
+	assert.Contains(t, out.String(), "+apiVersion: "+metadataApiVersion)
+	_, err = os.Stat(path.Join(bpPath, metadataDisplayFileName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -7,7 +7,6 @@ import (
 	"path"
 	"strings"
 
-	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
 	"github.com/itchyny/json2yaml"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -22,9 +21,46 @@ var mdFlags struct {
 	force                 bool
 	display               bool
 	validate              bool
+	validateFormat        string
 	quiet                 bool
 	genOutputType         bool
 	perModuleRequirements bool
+	check                 bool
+	inferWidgets          bool
+	inferQuotas           bool
+	outputTypeSource      string
+	providerSchema        string
+	requirementsSource    string
+	recursive             string
+	workers               int
+	diff                  struct {
+		path   string
+		format string
+	}
+	graph struct {
+		format string
+	}
+	inputs struct {
+		path     string
+		varsFile string
+	}
+	migrate struct {
+		path string
+		to   string
+	}
+	preview struct {
+		path string
+		addr string
+	}
+	readme struct {
+		path         string
+		requirements bool
+		check        bool
+	}
+	example struct {
+		path string
+		out  string
+	}
 }
 
 const (
@@ -50,9 +86,54 @@ func init() {
 	Cmd.Flags().StringVarP(&mdFlags.path, "path", "p", ".", "Path to the blueprint for generating metadata.")
 	Cmd.Flags().BoolVar(&mdFlags.nested, "nested", true, "Flag for generating metadata for nested blueprint, if any.")
 	Cmd.Flags().BoolVarP(&mdFlags.validate, "validate", "v", false, "Validate metadata against the schema definition.")
+	Cmd.Flags().StringVar(&mdFlags.validateFormat, "format", validateFormatText, "Output format of validation errors with --validate - one of text or json.")
 	Cmd.Flags().BoolVarP(&mdFlags.quiet, "quiet", "q", false, "Run in quiet mode suppressing all prompts.")
 	Cmd.Flags().BoolVarP(&mdFlags.genOutputType, "generate-output-type", "g", false, "Automatically generate type field for outputs.")
 	Cmd.Flags().BoolVarP(&mdFlags.perModuleRequirements, "per-module-requirements", "m", false, "Generate per module requirements for root and sub modules.")
+	Cmd.Flags().StringVar(&mdFlags.outputTypeSource, "output-type-source", outputTypeSourceState, "Source used to generate output types with --generate-output-type. One of state (deploys the blueprint) or static (infers types from the TF config without deploying).")
+	Cmd.Flags().StringVar(&mdFlags.providerSchema, "provider-schema", "", "Path to the provider schema JSON `file` generated by \"terraform providers schema -json\" used to resolve resource attribute types with --output-type-source=static.")
+	Cmd.Flags().StringVar(&mdFlags.requirementsSource, "requirements-source", requirementsSourceSetup, "Source used to generate blueprint requirements. One of setup (parses roles and services from test/setup) or resources (infers roles and services from the resources used and reports discrepancies with test/setup, if present).")
+	Cmd.Flags().BoolVar(&mdFlags.inferWidgets, "infer-widgets", false, "Infer UI widgets for display variables from variable names, types, descriptions and the resource attributes they are used in.")
+	Cmd.Flags().BoolVar(&mdFlags.inferQuotas, "infer-quotas-and-policies", false, "Propose quota details and org policy checks from the compute, storage and IAM resources used, unless already authored.")
+	Cmd.Flags().BoolVar(&mdFlags.check, "check", false, "Check if metadata is up to date without writing it. Prints a diff and fails if metadata would change.")
+	Cmd.Flags().StringVar(&mdFlags.recursive, "recursive", "", "Generate or validate metadata for all blueprint repos found under the provided directory instead of --path.")
+	Cmd.Flags().IntVar(&mdFlags.workers, "workers", 4, "Number of blueprint repos processed concurrently with --recursive.")
+
+	Cmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVarP(&mdFlags.diff.path, "path", "p", ".", "Path to the blueprint whose metadata is read for git refs.")
+	diffCmd.Flags().StringVar(&mdFlags.diff.format, "format", diffFormatText, "Output format of the diff - one of text or json.")
+
+	Cmd.AddCommand(graphCmd)
+	graphCmd.Flags().StringVar(&mdFlags.graph.format, "format", graphFormatDOT, "Output format of the graph - one of dot or mermaid.")
+
+	Cmd.AddCommand(varsSchemaCmd)
+	Cmd.AddCommand(validateInputsCmd)
+	varsSchemaCmd.Flags().StringVarP(&mdFlags.inputs.path, "path", "p", ".", "Path to the blueprint to generate the tfvars JSON Schema for.")
+	validateInputsCmd.Flags().StringVarP(&mdFlags.inputs.path, "path", "p", ".", "Path to the blueprint to validate inputs for.")
+	validateInputsCmd.Flags().StringVar(&mdFlags.inputs.varsFile, "vars-file", "", "Path to the .tfvars or .tfvars.json file to validate.")
+	if err := validateInputsCmd.MarkFlagRequired("vars-file"); err != nil {
+		panic(err)
+	}
+
+	Cmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().StringVarP(&mdFlags.migrate.path, "path", "p", ".", "Path to the blueprint to migrate metadata for, including sub modules.")
+	migrateCmd.Flags().StringVar(&mdFlags.migrate.to, "to", metadataApiVersion, "API version to migrate metadata to.")
+
+	Cmd.AddCommand(previewCmd)
+	previewCmd.Flags().StringVarP(&mdFlags.preview.path, "path", "p", ".", "Path to the blueprint to preview display metadata for.")
+	previewCmd.Flags().StringVar(&mdFlags.preview.addr, "addr", "localhost:8080", "Address to serve the preview on.")
+
+	Cmd.AddCommand(readmeCmd)
+	readmeCmd.Flags().StringVarP(&mdFlags.readme.path, "path", "p", ".", "Path to the blueprint to update the README for, including sub modules.")
+	readmeCmd.Flags().BoolVar(&mdFlags.readme.requirements, "requirements", false, "Render the roles and services required by the blueprint as well.")
+	readmeCmd.Flags().BoolVar(&mdFlags.readme.check, "check", false, "Check if the README is up to date without writing it. Prints a diff and fails if the README would change.")
+
+	Cmd.AddCommand(exampleCmd)
+	exampleCmd.Flags().StringVarP(&mdFlags.example.path, "path", "p", ".", "Path to the blueprint to generate an example for.")
+	exampleCmd.Flags().StringVar(&mdFlags.example.out, "out", "", "Directory to write the example to e.g. examples/<name>. Must not exist or be empty.")
+	if err := exampleCmd.MarkFlagRequired("out"); err != nil {
+		panic(err)
+	}
 }
 
 var Cmd = &cobra.Command{
@@ -63,7 +144,104 @@ var Cmd = &cobra.Command{
 	RunE:  generate,
 }
 
-var repoDetails repoDetail
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Compares blueprint interfaces between two versions",
+	Long:  `Compares variables, outputs and requirements between two versions of blueprint metadata and recommends a semver bump. Versions are paths to metadata files, blueprint directories or git refs.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		oldMd, err := loadMetadataRevision(args[0], mdFlags.diff.path)
+		if err != nil {
+			return fmt.Errorf("error loading old metadata: %w", err)
+		}
+		newMd, err := loadMetadataRevision(args[1], mdFlags.diff.path)
+		if err != nil {
+			return fmt.Errorf("error loading new metadata: %w", err)
+		}
+		return writeDiff(diffMetadata(oldMd, newMd), mdFlags.diff.format, cmd.OutOrStdout())
+	},
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [dirs...]",
+	Short: "Renders blueprint connections across a workspace",
+	Long:  `Loads blueprint metadata within the provided directories (default is the current directory), resolves connections against the outputs of their source blueprints and renders the connection graph. Relative connection sources are resolved against the blueprint declaring the connection. Dangling connections to remote sources not found in the directories are reported for information. Invalid connections and dangling connections to local sources are reported and result in a non-zero exit code.`,
+	Args:  cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+		return generateGraph(args, mdFlags.graph.format, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	},
+}
+
+var varsSchemaCmd = &cobra.Command{
+	Use:   "vars-schema",
+	Short: "Generates a JSON Schema for blueprint inputs",
+	Long:  `Generates a JSON Schema for tfvars of a blueprint from its variables and display metadata constraints.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return writeVarsSchema(mdFlags.inputs.path, cmd.OutOrStdout())
+	},
+}
+
+var validateInputsCmd = &cobra.Command{
+	Use:   "validate-inputs",
+	Short: "Validates a tfvars file against blueprint metadata",
+	Long:  `Validates a .tfvars or .tfvars.json file against the JSON Schema generated from the blueprint variables and display metadata constraints, reporting all violations.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return validateInputs(mdFlags.inputs.path, mdFlags.inputs.varsFile, cmd.OutOrStdout())
+	},
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrates metadata to another API version",
+	Long:  `Migrates metadata.yaml and metadata.display.yaml files of a blueprint and its sub modules to another API version, rewriting them in place formatted like generated metadata. Files with comments are not migrated since comments can not be preserved.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return migrateMetadata(mdFlags.migrate.path, mdFlags.migrate.to, cmd.OutOrStdout())
+	},
+}
+
+var previewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Serves a local preview of display metadata",
+	Long:  `Serves an HTML rendering of the input form and runtime outputs described by metadata.display.yaml of a blueprint with sections, boolean groups, toggles, alternate defaults and validations evaluated as values are edited. Metadata is read from local files on each page load.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return servePreview(mdFlags.preview.path, mdFlags.preview.addr, cmd.OutOrStdout())
+	},
+}
+
+var readmeCmd = &cobra.Command{
+	Use:   "readme",
+	Short: "Generates README inputs and outputs from metadata",
+	Long:  `Renders the Inputs and Outputs tables, and optionally the requirements, of a blueprint and its sub modules from metadata.yaml into README.md between the "<!-- BEGINNING OF PRE-COMMIT-TERRAFORM DOCS HOOK -->" and "<!-- END OF PRE-COMMIT-TERRAFORM DOCS HOOK -->" markers. Sub module READMEs without these markers are skipped.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return generateReadmes(mdFlags.readme.path, mdFlags.readme.requirements, mdFlags.readme.check, cmd.OutOrStdout())
+	},
+}
+
+var exampleCmd = &cobra.Command{
+	Use:   "example",
+	Short: "Generates an example configuration from metadata",
+	Long:  `Generates a root module calling a blueprint with all required variables set to alternate defaults from metadata.display.yaml or placeholders of their type, the providers required by the blueprint and its outputs forwarded, e.g. to scaffold tests with "cft test init".`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return generateExample(mdFlags.example.path, mdFlags.example.out, cmd.OutOrStdout())
+	},
+}
 
 // The top-level command function that generates metadata based on the provided flags
 func generate(cmd *cobra.Command, args []string) error {
@@ -74,125 +252,109 @@ func generate(cmd *cobra.Command, args []string) error {
 
 	// validate metadata if there is an argument passed into the command
 	if mdFlags.validate {
-		if err := validateMetadata(mdFlags.path, wdPath); err != nil {
+		cmd.SilenceUsage = true
+		if mdFlags.recursive != "" {
+			return validateRecursive(mdFlags.recursive, mdFlags.workers, mdFlags.validateFormat, cmd.OutOrStdout())
+		}
+		if err := validateMetadata(mdFlags.path, wdPath, mdFlags.validateFormat, cmd.OutOrStdout()); err != nil {
 			return err
 		}
 
 		return nil
 	}
 
-	currBpPath := mdFlags.path
-	if !path.IsAbs(mdFlags.path) {
-		currBpPath = path.Join(wdPath, mdFlags.path)
+	opts := Options{
+		Path:                  mdFlags.path,
+		Nested:                mdFlags.nested,
+		Force:                 mdFlags.force,
+		Display:               mdFlags.display,
+		Check:                 mdFlags.check,
+		Quiet:                 mdFlags.quiet,
+		Prompt:                scanPrompt,
+		PerModuleRequirements: mdFlags.perModuleRequirements,
+		RequirementsSource:    mdFlags.requirementsSource,
+		GenerateOutputType:    mdFlags.genOutputType,
+		OutputTypeSource:      mdFlags.outputTypeSource,
+		ProviderSchema:        mdFlags.providerSchema,
+		InferWidgets:          mdFlags.inferWidgets,
+		InferQuotas:           mdFlags.inferQuotas,
+	}
+
+	if mdFlags.recursive != "" {
+		cmd.SilenceUsage = true
+		if err := generateRecursive(mdFlags.recursive, mdFlags.workers, opts, cmd.OutOrStdout()); err != nil {
+			return err
+		}
+		if mdFlags.check {
+			Log.Info("metadata is up to date")
+		} else {
+			Log.Info("metadata generated successfully")
+		}
+		return nil
 	}
 
-	var allBpPaths []string
-	_, err = os.Stat(path.Join(currBpPath, readmeFileName))
-
-	// throw an error and exit if root level readme.md doesn't exist
+	results, err := Generate(opts)
 	if err != nil {
-		return fmt.Errorf("top-level module does not have a readme: %w", err)
+		return err
 	}
 
-	allBpPaths = append(allBpPaths, currBpPath)
 	var errors []string
-
-	// if nested, check if modules/ exists and create paths
-	// for submodules
-	if mdFlags.nested {
-		modulesPathforBp := path.Join(currBpPath, modulesPath)
-		_, err = os.Stat(modulesPathforBp)
-		if os.IsNotExist(err) {
-			Log.Info("sub-modules do not exist for this blueprint")
-		} else {
-			moduleDirs, err := util.WalkTerraformDirs(modulesPathforBp)
-			if err != nil {
-				errors = append(errors, err.Error())
-			} else {
-				allBpPaths = append(allBpPaths, moduleDirs...)
-			}
-		}
-	}
-
-	for _, modPath := range allBpPaths {
-		// check if module path has readme.md
-		_, err := os.Stat(path.Join(modPath, readmeFileName))
-
-		// log info if a sub-module doesn't have a readme.md and continue
-		if err != nil {
-			Log.Info("skipping metadata for sub-module identified as an internal module", "Path:", modPath)
-			continue
+	var stalePaths []string
+	for _, r := range results {
+		for _, w := range r.Warnings {
+			Log.Warn(w, "path", r.Path)
 		}
-
-		err = generateMetadataForBpPath(modPath)
-		if err != nil {
-			e := fmt.Sprintf("path: %s\n %s", modPath, err.Error())
+		fmt.Fprint(cmd.OutOrStdout(), r.Diff)
+		if r.Err != nil {
+			e := fmt.Sprintf("path: %s\n %s", r.Path, r.Err.Error())
 			errors = append(errors, e)
 		}
+		if r.Stale {
+			stalePaths = append(stalePaths, r.Path)
+		}
 	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf("%s", strings.Join(errors, "\n"))
 	}
 
-	Log.Info("metadata generated successfully")
-	return nil
-}
-
-func generateMetadataForBpPath(bpPath string) error {
-	//try to read existing metadata.yaml
-	bpObj, err := UnmarshalMetadata(bpPath, metadataFileName)
-	if err != nil && !errors.Is(err, os.ErrNotExist) && !mdFlags.force {
-		return err
-	}
-
-	// create core metadata
-	bpMetaObj, err := CreateBlueprintMetadata(bpPath, bpObj)
-	if err != nil {
-		return fmt.Errorf("error creating metadata for blueprint at path: %s. Details: %w", bpPath, err)
-	}
-
-	// If the flag is set, update output types
-	if mdFlags.genOutputType {
-		err = updateOutputTypes(bpPath, bpMetaObj.Spec.Interfaces)
-		if err != nil {
-			return fmt.Errorf("error updating output types: %w", err)
+	if mdFlags.check {
+		if len(stalePaths) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("metadata is out of date for blueprint path(s):\n %s", strings.Join(stalePaths, "\n "))
 		}
-	}
-
-	// write core metadata to disk
-	err = WriteMetadata(bpMetaObj, bpPath, metadataFileName)
-	if err != nil {
-		return fmt.Errorf("error writing metadata to disk for blueprint at path: %s. Details: %w", bpPath, err)
-	}
-
-	// continue with creating display metadata if the flag is set,
-	// else let the command exit
-	if !mdFlags.display {
+		Log.Info("metadata is up to date")
 		return nil
 	}
 
-	bpDpObj, err := UnmarshalMetadata(bpPath, metadataDisplayFileName)
-	if err != nil && !errors.Is(err, os.ErrNotExist) && !mdFlags.force {
-		return err
-	}
+	Log.Info("metadata generated successfully")
+	return nil
+}
 
-	// create display metadata
-	bpMetaDpObj, err := CreateBlueprintDisplayMetadata(bpPath, bpDpObj, bpMetaObj)
-	if err != nil {
-		return fmt.Errorf("error creating display metadata for blueprint at path: %s. Details: %w", bpPath, err)
-	}
+// scanPrompt prompts for a value on stdin.
+func scanPrompt(question string) (string, error) {
+	fmt.Printf("%s: ", question)
+	var v string
+	_, err := fmt.Scan(&v)
+	return v, err
+}
 
-	// write display metadata to disk
-	err = WriteMetadata(bpMetaDpObj, bpPath, metadataDisplayFileName)
-	if err != nil {
-		return fmt.Errorf("error writing display metadata to disk for blueprint at path: %s. Details: %w", bpPath, err)
-	}
+// CreateBlueprintMetadata creates metadata for the blueprint at bpPath, updating
+// bpMetadataObj with generated fields. Use Generate to configure generation.
+func CreateBlueprintMetadata(bpPath string, bpMetadataObj *BlueprintMetadata) (*BlueprintMetadata, error) {
+	g := &generator{opts: Options{Quiet: true}}
+	return g.createBlueprintMetadata(bpPath, bpMetadataObj)
+}
 
-	return nil
+// CreateBlueprintDisplayMetadata creates display metadata for the blueprint at bpPath
+// from its core metadata, updating bpDisp with generated fields. Use Generate to
+// configure generation.
+func CreateBlueprintDisplayMetadata(bpPath string, bpDisp, bpCore *BlueprintMetadata) (*BlueprintMetadata, error) {
+	g := &generator{opts: Options{Quiet: true}}
+	return g.createBlueprintDisplayMetadata(bpPath, bpDisp, bpCore)
 }
 
-func CreateBlueprintMetadata(bpPath string, bpMetadataObj *BlueprintMetadata) (*BlueprintMetadata, error) {
+func (g *generator) createBlueprintMetadata(bpPath string, bpMetadataObj *BlueprintMetadata) (*BlueprintMetadata, error) {
 	// Verify that readme is present.
 	readmeContent, err := os.ReadFile(path.Join(bpPath, readmeFileName))
 	if err != nil {
@@ -200,22 +362,8 @@ func CreateBlueprintMetadata(bpPath string, bpMetadataObj *BlueprintMetadata) (*
 	}
 
 	// verify that the blueprint path is valid & get repo details
-	getRepoDetailsByPath(bpPath, &repoDetails, readmeContent)
-	if repoDetails.ModuleName == "" && !mdFlags.quiet {
-		fmt.Printf("Provide a name for the blueprint at path [%s]: ", bpPath)
-		_, err := fmt.Scan(&repoDetails.ModuleName)
-		if err != nil {
-			fmt.Println("Unable to scan the name for the blueprint.")
-		}
-	}
-
-	if repoDetails.Source.URL == "" && !mdFlags.quiet {
-		fmt.Printf("Provide a URL for the blueprint source at path [%s]: ", bpPath)
-		_, err := fmt.Scan(&repoDetails.Source.URL)
-		if err != nil {
-			fmt.Println("Unable to scan the URL for the blueprint.")
-		}
-	}
+	g.resolveRepoDetails(bpPath, readmeContent)
+	repoDetails := g.repoDetails
 
 	// start creating blueprint metadata
 	bpMetadataObj.ApiVersion = metadataApiVersion
@@ -242,6 +390,18 @@ func CreateBlueprintMetadata(bpPath string, bpMetadataObj *BlueprintMetadata) (*
 		return nil, fmt.Errorf("error creating blueprint info: %w", err)
 	}
 
+	// KRM blueprints are kpt packages without TF config
+	if isKptPackage(bpPath) {
+		if bpMetadataObj.Spec.Content == nil {
+			bpMetadataObj.Spec.Content = &BlueprintContent{}
+		}
+		bpMetadataObj.Spec.Content.create(bpPath, repoDetails.Source.BlueprintRootPath, readmeContent)
+		if err := bpMetadataObj.Spec.createKRM(bpPath, repoDetails.Source.BlueprintRootPath); err != nil {
+			return nil, fmt.Errorf("error creating KRM blueprint metadata: %w", err)
+		}
+		return bpMetadataObj, nil
+	}
+
 	var existingInterfaces *BlueprintInterface
 	if bpMetadataObj.Spec.Interfaces == nil {
 		bpMetadataObj.Spec.Interfaces = &BlueprintInterface{}
@@ -261,13 +421,30 @@ func CreateBlueprintMetadata(bpPath string, bpMetadataObj *BlueprintMetadata) (*
 	// Merge existing output types (if any) into the newly generated interfaces
 	mergeExistingOutputTypes(bpMetadataObj.Spec.Interfaces, existingInterfaces)
 
+	if g.opts.InferQuotas {
+		if err := bpMetadataObj.Spec.Info.inferQuotasAndPolicies(bpPath, bpMetadataObj.Spec.Interfaces.Variables); err != nil {
+			return nil, fmt.Errorf("error inferring quota details and org policy checks: %w", err)
+		}
+	}
+
 	// get blueprint requirements
 	rolesCfgPath := path.Join(repoDetails.Source.BlueprintRootPath, tfRolesFileName)
 	svcsCfgPath := path.Join(repoDetails.Source.BlueprintRootPath, tfServicesFileName)
 	versionsCfgPath := path.Join(bpPath, tfVersionsFileName)
 	moduleName := parseBpModuleName(bpPath, repoDetails.Source.BlueprintRootPath)
-	requirements, err := getBlueprintRequirements(rolesCfgPath, svcsCfgPath, versionsCfgPath, mdFlags.perModuleRequirements, moduleName)
-	if err != nil {
+	requirements, err := getBlueprintRequirements(rolesCfgPath, svcsCfgPath, versionsCfgPath, g.opts.PerModuleRequirements, moduleName)
+	if g.opts.RequirementsSource == requirementsSourceResources {
+		inferred, inferErr := inferBlueprintRequirements(bpPath, versionsCfgPath)
+		if inferErr != nil {
+			return nil, fmt.Errorf("error inferring blueprint requirements: %w", inferErr)
+		}
+		if err == nil {
+			for _, d := range compareRequirements(inferred, requirements) {
+				g.warn("blueprint requirements discrepancy: %s", d)
+			}
+		}
+		bpMetadataObj.Spec.Requirements = inferred
+	} else if err != nil {
 		Log.Info("skipping blueprint requirements since roles and/or services configurations were not found as per https://tinyurl.com/tf-iam and https://tinyurl.com/tf-services", "error:", err)
 	} else {
 		bpMetadataObj.Spec.Requirements = requirements
@@ -282,7 +459,7 @@ func CreateBlueprintMetadata(bpPath string, bpMetadataObj *BlueprintMetadata) (*
 	return bpMetadataObj, nil
 }
 
-func CreateBlueprintDisplayMetadata(bpPath string, bpDisp, bpCore *BlueprintMetadata) (*BlueprintMetadata, error) {
+func (g *generator) createBlueprintDisplayMetadata(bpPath string, bpDisp, bpCore *BlueprintMetadata) (*BlueprintMetadata, error) {
 	// start creating blueprint metadata
 	bpDisp.ApiVersion = bpCore.ApiVersion
 	bpDisp.Kind = bpCore.Kind
@@ -311,6 +488,19 @@ func CreateBlueprintDisplayMetadata(bpPath string, bpDisp, bpCore *BlueprintMeta
 	bpDisp.Spec.Info.Source = bpCore.Spec.Info.Source
 	buildUIInputFromVariables(bpCore.Spec.Interfaces.Variables, bpDisp.Spec.Ui.Input)
 
+	// derive UI validation from TF variable validation blocks
+	validations, err := getVariableValidations(bpPath)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing variable validations: %w", err)
+	}
+	deriveUIInputValidations(bpCore.Spec.Interfaces.Variables, validations, bpDisp.Spec.Ui.Input)
+
+	if g.opts.InferWidgets {
+		if err := inferWidgets(bpPath, bpCore.Spec.Interfaces.Variables, bpDisp.Spec.Ui.Input); err != nil {
+			return nil, fmt.Errorf("error inferring widgets: %w", err)
+		}
+	}
+
 	existingInput := func() *BlueprintUIInput {
 		if bpCore.Spec.Ui != nil && bpCore.Spec.Ui.Input != nil {
 			return proto.Clone(bpCore.Spec.Ui.Input).(*BlueprintUIInput)
@@ -448,18 +638,28 @@ func (c *BlueprintContent) create(bpPath string, rootPath string, readmeContent
 }
 
 func WriteMetadata(obj *BlueprintMetadata, bpPath, fileName string) error {
-	jBytes, err := protojson.Marshal(obj)
+	b, err := marshalMetadata(obj)
 	if err != nil {
 		return err
 	}
 
+	return os.WriteFile(path.Join(bpPath, fileName), b, 0644)
+}
+
+// marshalMetadata converts metadata to the YAML written to disk.
+func marshalMetadata(obj proto.Message) ([]byte, error) {
+	jBytes, err := protojson.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
 	input := strings.NewReader(string(jBytes))
 	var output strings.Builder
 	if err := json2yaml.Convert(&output, input); err != nil {
-		return err
+		return nil, err
 	}
 
-	return os.WriteFile(path.Join(bpPath, fileName), []byte(output.String()), 0644)
+	return []byte(output.String()), nil
 }
 
 func UnmarshalMetadata(bpPath, fileName string) (*BlueprintMetadata, error) {
@@ -476,15 +676,31 @@ func UnmarshalMetadata(bpPath, fileName string) (*BlueprintMetadata, error) {
 		return &bpObj, fmt.Errorf("unable to read metadata from the existing file: %w", err)
 	}
 
+	if err := unmarshalMetadataBytes(f, &bpObj); err != nil {
+		return &bpObj, err
+	}
+
+	return &bpObj, nil
+}
+
+// unmarshalMetadataBytes unmarshals YAML metadata content into bpObj and
+// validates its GVK.
+func unmarshalMetadataBytes(f []byte, bpObj *BlueprintMetadata) error {
+	// upgrade metadata of older versions with a registered migration
+	f, err := upgradeMetadataContent(f)
+	if err != nil {
+		return err
+	}
+
 	// convert yaml bytes to json bytes for unmarshaling metadata
 	// content to proto definition
 	j, err := yaml.YAMLToJSON(f)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if err := protojson.Unmarshal(j, &bpObj); err != nil {
-		return &bpObj, err
+	if err := protojson.Unmarshal(j, bpObj); err != nil {
+		return err
 	}
 
 	currVersion := bpObj.ApiVersion
@@ -492,12 +708,12 @@ func UnmarshalMetadata(bpPath, fileName string) (*BlueprintMetadata, error) {
 
 	//validate GVK for current metadata
 	if currVersion != metadataApiVersion {
-		return &bpObj, fmt.Errorf("found incorrect version for the metadata: %s. Supported version is: %s", currVersion, metadataApiVersion)
+		return fmt.Errorf("found incorrect version for the metadata: %s. Supported version is: %s", currVersion, metadataApiVersion)
 	}
 
 	if currKind != metadataKind {
-		return &bpObj, fmt.Errorf("found incorrect kind for the metadata: %s. Supported kind is %s", currKind, metadataKind)
+		return fmt.Errorf("found incorrect kind for the metadata: %s. Supported kind is %s", currKind, metadataKind)
 	}
 
-	return &bpObj, nil
+	return nil
 }
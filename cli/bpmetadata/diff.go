@@ -0,0 +1,298 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	diffFormatText = "text"
+	diffFormatJSON = "json"
+)
+
+// semverBump is the semantic version increment required by a change.
+type semverBump string
+
+const (
+	bumpNone  semverBump = "none"
+	bumpPatch semverBump = "patch"
+	bumpMinor semverBump = "minor"
+	bumpMajor semverBump = "major"
+)
+
+var bumpRanks = map[semverBump]int{bumpNone: 0, bumpPatch: 1, bumpMinor: 2, bumpMajor: 3}
+
+// interfaceChange is a single change between two versions of blueprint metadata.
+type interfaceChange struct {
+	// Kind of the changed item i.e. variable, output, service, role or providerVersion
+	Kind     string     `json:"kind"`
+	Name     string     `json:"name"`
+	Message  string     `json:"message"`
+	Breaking bool       `json:"breaking"`
+	Bump     semverBump `json:"bump"`
+}
+
+// interfaceDiff is the set of changes between two versions of blueprint metadata
+// along with the recommended semver bump.
+type interfaceDiff struct {
+	Bump    semverBump        `json:"bump"`
+	Changes []interfaceChange `json:"changes"`
+}
+
+// itemChanges collects the changes of a single variable or output so that they are
+// reported as one change with the largest bump.
+type itemChanges struct {
+	bump  semverBump
+	parts []string
+}
+
+func (c *itemChanges) add(bump semverBump, format string, a ...any) {
+	c.parts = append(c.parts, fmt.Sprintf(format, a...))
+	if c.bump == "" || bumpRanks[bump] > bumpRanks[c.bump] {
+		c.bump = bump
+	}
+}
+
+// addItem adds the collected changes of item name, if any, as a single change.
+func (d *interfaceDiff) addItem(kind, name string, c *itemChanges) {
+	if len(c.parts) > 0 {
+		d.add(kind, name, c.bump, "%s %s %s", kind, name, strings.Join(c.parts, ", "))
+	}
+}
+
+func (d *interfaceDiff) add(kind, name string, bump semverBump, format string, a ...any) {
+	d.Changes = append(d.Changes, interfaceChange{
+		Kind:     kind,
+		Name:     name,
+		Message:  fmt.Sprintf(format, a...),
+		Breaking: bump == bumpMajor,
+		Bump:     bump,
+	})
+	if bumpRanks[bump] > bumpRanks[d.Bump] {
+		d.Bump = bump
+	}
+}
+
+// diffMetadata compares the interfaces and requirements of two versions of blueprint metadata.
+func diffMetadata(oldMd, newMd *BlueprintMetadata) *interfaceDiff {
+	d := &interfaceDiff{Bump: bumpNone, Changes: []interfaceChange{}}
+	d.diffVariables(oldMd.GetSpec().GetInterfaces().GetVariables(), newMd.GetSpec().GetInterfaces().GetVariables())
+	d.diffOutputs(oldMd.GetSpec().GetInterfaces().GetOutputs(), newMd.GetSpec().GetInterfaces().GetOutputs())
+	d.diffRequirements(oldMd.GetSpec().GetRequirements(), newMd.GetSpec().GetRequirements())
+	return d
+}
+
+func (d *interfaceDiff) diffVariables(oldVars, newVars []*BlueprintVariable) {
+	newByName := make(map[string]*BlueprintVariable)
+	for _, v := range newVars {
+		newByName[v.Name] = v
+	}
+	oldByName := make(map[string]*BlueprintVariable)
+	for _, oldVar := range oldVars {
+		oldByName[oldVar.Name] = oldVar
+		newVar, ok := newByName[oldVar.Name]
+		if !ok {
+			d.add("variable", oldVar.Name, bumpMajor, "variable %s was removed", oldVar.Name)
+			continue
+		}
+		c := &itemChanges{}
+		if !oldVar.Required && newVar.Required {
+			c.add(bumpMajor, "is now required")
+		} else if oldVar.Required && !newVar.Required {
+			c.add(bumpMinor, "is now optional")
+		}
+		if normalizeVarType(oldVar.VarType) != normalizeVarType(newVar.VarType) {
+			c.add(bumpMajor, "type changed from %s to %s", oldVar.VarType, newVar.VarType)
+		}
+		if !proto.Equal(oldVar.DefaultValue, newVar.DefaultValue) {
+			// Defaults of required variables are never used, so only a default change
+			// on a variable that stays optional affects callers.
+			bump := bumpMinor
+			if oldVar.Required || newVar.Required {
+				bump = bumpNone
+			}
+			c.add(bump, "default changed from %s to %s", valueString(oldVar.DefaultValue), valueString(newVar.DefaultValue))
+		}
+		if oldVar.Description != newVar.Description {
+			c.add(bumpPatch, "description changed")
+		}
+		d.addItem("variable", oldVar.Name, c)
+	}
+	for _, newVar := range newVars {
+		if _, ok := oldByName[newVar.Name]; ok {
+			continue
+		}
+		if newVar.Required {
+			d.add("variable", newVar.Name, bumpMajor, "required variable %s was added", newVar.Name)
+		} else {
+			d.add("variable", newVar.Name, bumpMinor, "optional variable %s was added", newVar.Name)
+		}
+	}
+}
+
+func (d *interfaceDiff) diffOutputs(oldOutputs, newOutputs []*BlueprintOutput) {
+	newByName := make(map[string]*BlueprintOutput)
+	for _, o := range newOutputs {
+		newByName[o.Name] = o
+	}
+	oldByName := make(map[string]*BlueprintOutput)
+	for _, oldOut := range oldOutputs {
+		oldByName[oldOut.Name] = oldOut
+		newOut, ok := newByName[oldOut.Name]
+		if !ok {
+			d.add("output", oldOut.Name, bumpMajor, "output %s was removed", oldOut.Name)
+			continue
+		}
+		c := &itemChanges{}
+		switch {
+		case proto.Equal(oldOut.Type, newOut.Type):
+		case oldOut.Type == nil:
+			// types are generated for existing outputs
+			c.add(bumpPatch, "type set to %s", valueString(newOut.Type))
+		case newOut.Type == nil:
+			c.add(bumpPatch, "type unset")
+		default:
+			c.add(bumpMajor, "type changed from %s to %s", valueString(oldOut.Type), valueString(newOut.Type))
+		}
+		if oldOut.Description != newOut.Description {
+			c.add(bumpPatch, "description changed")
+		}
+		d.addItem("output", oldOut.Name, c)
+	}
+	for _, newOut := range newOutputs {
+		if _, ok := oldByName[newOut.Name]; !ok {
+			d.add("output", newOut.Name, bumpMinor, "output %s was added", newOut.Name)
+		}
+	}
+}
+
+func (d *interfaceDiff) diffRequirements(oldReqs, newReqs *BlueprintRequirements) {
+	oldServices, newServices := setOf(oldReqs.GetServices()), setOf(newReqs.GetServices())
+	for _, s := range sortedUnique(newReqs.GetServices()) {
+		if !oldServices[s] {
+			d.add("service", s, bumpMinor, "service %s is now required", s)
+		}
+	}
+	for _, s := range sortedUnique(oldReqs.GetServices()) {
+		if !newServices[s] {
+			d.add("service", s, bumpPatch, "service %s is no longer required", s)
+		}
+	}
+
+	oldRoles, newRoles := leveledRoles(oldReqs.GetRoles()), leveledRoles(newReqs.GetRoles())
+	oldRoleSet, newRoleSet := setOf(oldRoles), setOf(newRoles)
+	for _, r := range newRoles {
+		if !oldRoleSet[r] {
+			d.add("role", r, bumpMinor, "role %s is now required", r)
+		}
+	}
+	for _, r := range oldRoles {
+		if !newRoleSet[r] {
+			d.add("role", r, bumpPatch, "role %s is no longer required", r)
+		}
+	}
+
+	oldVersions := make(map[string]string)
+	for _, pv := range oldReqs.GetProviderVersions() {
+		oldVersions[pv.Source] = pv.Version
+	}
+	newVersions := make(map[string]string)
+	for _, pv := range newReqs.GetProviderVersions() {
+		newVersions[pv.Source] = pv.Version
+		oldVersion, ok := oldVersions[pv.Source]
+		switch {
+		case !ok:
+			d.add("providerVersion", pv.Source, bumpMinor, "provider %s is now required with version %s", pv.Source, pv.Version)
+		case oldVersion != pv.Version:
+			d.add("providerVersion", pv.Source, bumpMinor, "provider %s version changed from %s to %s", pv.Source, oldVersion, pv.Version)
+		}
+	}
+	for _, pv := range oldReqs.GetProviderVersions() {
+		if _, ok := newVersions[pv.Source]; !ok {
+			d.add("providerVersion", pv.Source, bumpPatch, "provider %s is no longer required", pv.Source)
+		}
+	}
+}
+
+// leveledRoles returns sorted roles qualified by their level e.g. Project/roles/owner.
+func leveledRoles(roles []*BlueprintRoles) []string {
+	var r []string
+	for _, br := range roles {
+		for _, role := range br.Roles {
+			r = append(r, br.Level+"/"+role)
+		}
+	}
+	return sortedUnique(r)
+}
+
+// valueString returns a compact JSON representation of a value for display.
+func valueString(v *structpb.Value) string {
+	if v == nil {
+		return "null"
+	}
+	b, err := json.Marshal(v.AsInterface())
+	if err != nil {
+		return v.String()
+	}
+	return string(b)
+}
+
+// writeDiff writes the interface diff to w in the provided format.
+func writeDiff(d *interfaceDiff, format string, w io.Writer) error {
+	switch format {
+	case diffFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	case diffFormatText:
+		if len(d.Changes) == 0 {
+			fmt.Fprintln(w, "No interface changes found.")
+		}
+		for _, c := range d.Changes {
+			label := "non-breaking"
+			if c.Breaking {
+				label = "BREAKING"
+			}
+			fmt.Fprintf(w, "%-12s %s\n", label, c.Message)
+		}
+		fmt.Fprintf(w, "Recommended version bump: %s\n", d.Bump)
+		return nil
+	}
+	return fmt.Errorf("invalid format %s - one of %+q expected", format, []string{diffFormatText, diffFormatJSON})
+}
+
+// loadMetadataRevision loads metadata from rev which is either a path to a metadata
+// file, a path to a blueprint directory or a git ref. Metadata for git refs is read
+// from the metadata file of the blueprint at bpPath as of that ref.
+func loadMetadataRevision(rev, bpPath string) (*BlueprintMetadata, error) {
+	if info, err := os.Stat(rev); err == nil {
+		if info.IsDir() {
+			return UnmarshalMetadata(rev, metadataFileName)
+		}
+		return UnmarshalMetadata(path.Dir(rev), path.Base(rev))
+	}
+
+	cmd := exec.Command("git", "show", fmt.Sprintf("%s:./%s", rev, metadataFileName))
+	cmd.Dir = bpPath
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	b, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a metadata path nor a git ref with %s: %w: %s", rev, path.Join(bpPath, metadataFileName), err, strings.TrimSpace(stderr.String()))
+	}
+
+	bpObj := &BlueprintMetadata{}
+	if err := unmarshalMetadataBytes(b, bpObj); err != nil {
+		return nil, fmt.Errorf("error parsing metadata at %s: %w", rev, err)
+	}
+	return bpObj, nil
+}
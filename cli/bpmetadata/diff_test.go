@@ -0,0 +1,270 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestDiffMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldFile  string
+		newFile  string
+		wantBump semverBump
+		want     []string
+	}{
+		{
+			name:     "no changes",
+			oldFile:  "diff_old_metadata.yaml",
+			newFile:  "diff_old_metadata.yaml",
+			wantBump: bumpNone,
+		},
+		{
+			name:     "breaking and non-breaking changes",
+			oldFile:  "diff_old_metadata.yaml",
+			newFile:  "diff_new_metadata.yaml",
+			wantBump: bumpMajor,
+			want: []string{
+				"patch: variable project_id description changed",
+				`minor: variable region default changed from "us-central1" to "us-east1"`,
+				"major: variable labels is now required, default changed from {} to null",
+				"major: variable legacy was removed",
+				"major: required variable name was added",
+				"minor: optional variable versioning was added",
+				`major: output bucket_name type changed from "string" to ["object",{"name":"string"}]`,
+				"major: output legacy_id was removed",
+				"minor: output bucket_url was added",
+				"minor: service iam.googleapis.com is now required",
+				"minor: role Project/roles/iam.serviceAccountUser is now required",
+				"minor: provider hashicorp/google version changed from >= 4.0, < 6 to >= 5.0, < 7",
+			},
+		},
+		{
+			name:     "reverse changes",
+			oldFile:  "diff_new_metadata.yaml",
+			newFile:  "diff_old_metadata.yaml",
+			wantBump: bumpMajor,
+			want: []string{
+				"patch: variable project_id description changed",
+				`minor: variable region default changed from "us-east1" to "us-central1"`,
+				"minor: variable labels is now optional, default changed from null to {}",
+				"major: variable name was removed",
+				"major: variable versioning was removed",
+				"minor: optional variable legacy was added",
+				`major: output bucket_name type changed from ["object",{"name":"string"}] to "string"`,
+				"major: output bucket_url was removed",
+				"minor: output legacy_id was added",
+				"patch: service iam.googleapis.com is no longer required",
+				"patch: role Project/roles/iam.serviceAccountUser is no longer required",
+				"minor: provider hashicorp/google version changed from >= 5.0, < 7 to >= 4.0, < 6",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldMd, err := UnmarshalMetadata(metadataTestdataPath, tt.oldFile)
+			require.NoError(t, err)
+			newMd, err := UnmarshalMetadata(metadataTestdataPath, tt.newFile)
+			require.NoError(t, err)
+
+			d := diffMetadata(oldMd, newMd)
+			assert.Equal(t, tt.wantBump, d.Bump)
+			var got []string
+			for _, c := range d.Changes {
+				assert.Equal(t, c.Bump == bumpMajor, c.Breaking)
+				got = append(got, string(c.Bump)+": "+c.Message)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiffMetadataPatchOnly(t *testing.T) {
+	oldMd := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Interfaces: &BlueprintInterface{
+		Outputs: []*BlueprintOutput{{Name: "id", Description: "ID"}},
+	}}}
+	newMd := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Interfaces: &BlueprintInterface{
+		Outputs: []*BlueprintOutput{{Name: "id", Description: "The ID"}},
+	}}}
+
+	d := diffMetadata(oldMd, newMd)
+	assert.Equal(t, bumpPatch, d.Bump)
+	assert.Len(t, d.Changes, 1)
+	assert.Equal(t, "output", d.Changes[0].Kind)
+	assert.False(t, d.Changes[0].Breaking)
+}
+
+func TestDiffMetadataOutputTypes(t *testing.T) {
+	oldMd := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Interfaces: &BlueprintInterface{
+		Outputs: []*BlueprintOutput{
+			{Name: "id"},
+			{Name: "name", Type: structpb.NewStringValue("string")},
+			{Name: "labels", Type: structpb.NewStringValue("string")},
+		},
+	}}}
+	newMd := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Interfaces: &BlueprintInterface{
+		Outputs: []*BlueprintOutput{
+			{Name: "id", Type: structpb.NewStringValue("string"), Description: "ID"},
+			{Name: "name"},
+			{Name: "labels", Type: structpb.NewStringValue("number")},
+		},
+	}}}
+
+	d := diffMetadata(oldMd, newMd)
+	var got []string
+	for _, c := range d.Changes {
+		got = append(got, string(c.Bump)+": "+c.Message)
+	}
+	assert.Equal(t, []string{
+		`patch: output id type set to "string", description changed`,
+		"patch: output name type unset",
+		`major: output labels type changed from "string" to "number"`,
+	}, got)
+	assert.Equal(t, bumpMajor, d.Bump)
+}
+
+func TestWriteDiff(t *testing.T) {
+	d := &interfaceDiff{Bump: bumpNone, Changes: []interfaceChange{}}
+	d.add("variable", "foo", bumpMajor, "variable %s was removed", "foo")
+	d.add("output", "bar", bumpMinor, "output %s was added", "bar")
+
+	tests := []struct {
+		name    string
+		diff    *interfaceDiff
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "text",
+			diff:   d,
+			format: diffFormatText,
+			want:   "BREAKING     variable foo was removed\nnon-breaking output bar was added\nRecommended version bump: major\n",
+		},
+		{
+			name:   "text without changes",
+			diff:   &interfaceDiff{Bump: bumpNone},
+			format: diffFormatText,
+			want:   "No interface changes found.\nRecommended version bump: none\n",
+		},
+		{
+			name:   "json",
+			diff:   d,
+			format: diffFormatJSON,
+			want: `{
+  "bump": "major",
+  "changes": [
+    {
+      "kind": "variable",
+      "name": "foo",
+      "message": "variable foo was removed",
+      "breaking": true,
+      "bump": "major"
+    },
+    {
+      "kind": "output",
+      "name": "bar",
+      "message": "output bar was added",
+      "breaking": false,
+      "bump": "minor"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:    "invalid format",
+			diff:    d,
+			format:  "yaml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := writeDiff(tt.diff, tt.format, &b)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestLoadMetadataRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	oldContent, err := os.ReadFile(path.Join(metadataTestdataPath, "diff_old_metadata.yaml"))
+	require.NoError(t, err)
+	newContent, err := os.ReadFile(path.Join(metadataTestdataPath, "diff_new_metadata.yaml"))
+	require.NoError(t, err)
+
+	repo := t.TempDir()
+	bpPath := path.Join(repo, "modules", "bucket")
+	require.NoError(t, os.MkdirAll(bpPath, 0755))
+	require.NoError(t, os.WriteFile(path.Join(bpPath, metadataFileName), oldContent, 0644))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	require.NoError(t, os.WriteFile(path.Join(bpPath, metadataFileName), newContent, 0644))
+
+	tests := []struct {
+		name     string
+		rev      string
+		wantVars int
+		wantErr  bool
+	}{
+		{
+			name:     "metadata file",
+			rev:      path.Join(metadataTestdataPath, "diff_old_metadata.yaml"),
+			wantVars: 4,
+		},
+		{
+			name:     "blueprint directory",
+			rev:      bpPath,
+			wantVars: 5,
+		},
+		{
+			name:     "git ref",
+			rev:      "HEAD",
+			wantVars: 4,
+		},
+		{
+			name:    "unknown ref",
+			rev:     "does-not-exist",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadMetadataRevision(tt.rev, bpPath)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, got.Spec.Interfaces.Variables, tt.wantVars)
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package bpmetadata
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// displayValidator checks references within display metadata and against the
+// variables of the blueprint that JSON schema validation can not catch.
+type displayValidator struct {
+	input *BlueprintUIInput
+	// vars are the blueprint variables by name, nil if core metadata is not available
+	vars   map[string]*BlueprintVariable
+	errors []DisplayReferenceError
+}
+
+// DisplayReferenceError is a reference in display metadata to a missing or
+// incompatible section or variable.
+type DisplayReferenceError struct {
+	// Field is the path of the referencing field, e.g. spec.ui.input.variables.name.section.
+	Field   string
+	Message string
+}
+
+// validateDisplayMetadataFile validates references within the display metadata file at m
+// and against the variables in the core metadata next to it, if any.
+// Violations are returned as a *metadataValidationError.
+func validateDisplayMetadataFile(m string) error {
+	content, err := os.ReadFile(m)
+	if err != nil {
+		return fmt.Errorf("unable to read metadata at path %s. error: %w", m, err)
+	}
+	display := &BlueprintMetadata{}
+	if err := unmarshalMetadataBytes(content, display); err != nil {
+		return fmt.Errorf("unable to parse display metadata at path %s. error: %w", m, err)
+	}
+
+	core, err := UnmarshalMetadata(path.Dir(m), metadataFileName)
+	if errors.Is(err, os.ErrNotExist) {
+		core = nil
+	} else if err != nil {
+		return fmt.Errorf("unable to load metadata for display metadata at path %s. error: %w", m, err)
+	}
+
+	refErrs := ValidateDisplayMetadata(core, display)
+	if len(refErrs) == 0 {
+		return nil
+	}
+	sErrs := make([]schemaError, 0, len(refErrs))
+	for _, e := range refErrs {
+		se := schemaError{Field: e.Field, Message: e.Message}
+		if line, column, err := util.FindYAMLPosition(content, e.Field); err == nil {
+			se.Line, se.Column = line, column
+		}
+		sErrs = append(sErrs, se)
+	}
+	return &metadataValidationError{File: m, Errors: sErrs}
+}
+
+// ValidateDisplayMetadata checks that sections, boolean groups, toggles, alternate
+// defaults and zone properties of display metadata reference existing and compatible
+// sections and variables. Checks against blueprint variables are skipped if core is nil.
+func ValidateDisplayMetadata(core, display *BlueprintMetadata) []DisplayReferenceError {
+	input := display.GetSpec().GetUi().GetInput()
+	if input == nil {
+		return nil
+	}
+
+	v := &displayValidator{input: input}
+	if core != nil {
+		v.vars = make(map[string]*BlueprintVariable)
+		for _, bv := range core.GetSpec().GetInterfaces().GetVariables() {
+			v.vars[bv.Name] = bv
+		}
+	}
+
+	v.validateSections()
+	for _, name := range sortedKeys(input.Variables) {
+		v.validateVariable(name, input.Variables[name])
+	}
+	return v.errors
+}
+
+func (v *displayValidator) addError(field, format string, a ...any) {
+	v.errors = append(v.errors, DisplayReferenceError{Field: field, Message: fmt.Sprintf(format, a...)})
+}
+
+// validateSections checks that section names are unique and that section parents
+// exist and do not form cycles.
+func (v *displayValidator) validateSections() {
+	parents := make(map[string]string)
+	for i, s := range v.input.Sections {
+		if _, ok := parents[s.Name]; ok {
+			v.addError(fmt.Sprintf("spec.ui.input.sections[%d].name", i), "section %s is defined more than once", s.Name)
+		}
+		parents[s.Name] = s.Parent
+	}
+
+	for i, s := range v.input.Sections {
+		if s.Parent == "" {
+			continue
+		}
+		field := fmt.Sprintf("spec.ui.input.sections[%d].parent", i)
+		if _, ok := parents[s.Parent]; !ok {
+			v.addError(field, "section %s references unknown parent section %s", s.Name, s.Parent)
+			continue
+		}
+
+		// follow parents until the root or a section visited before
+		chain := []string{s.Name}
+		for p := s.Parent; p != ""; p = parents[p] {
+			if p == s.Name {
+				v.addError(field, "section %s is part of a cycle of parent sections: %s", s.Name, strings.Join(append(chain, p), " -> "))
+				break
+			}
+			if slices.Contains(chain, p) {
+				// cycle not including this section, reported for its own sections
+				break
+			}
+			chain = append(chain, p)
+		}
+	}
+}
+
+// validateVariable checks the references of display variable dv.
+func (v *displayValidator) validateVariable(name string, dv *DisplayVariable) {
+	field := "spec.ui.input.variables." + name
+	if _, ok := v.vars[name]; v.vars != nil && !ok {
+		v.addError(field, "variable %s is not defined in metadata", name)
+	}
+	if dv.Name != "" && dv.Name != name {
+		v.addError(field+".name", "variable %s has mismatched name %s", name, dv.Name)
+	}
+	if dv.Section != "" && !slices.ContainsFunc(v.input.Sections, func(s *DisplaySection) bool { return s.Name == dv.Section }) {
+		v.addError(field+".section", "variable %s references unknown section %s", name, dv.Section)
+	}
+
+	if dv.BooleanGroup != "" {
+		if !slices.ContainsFunc(v.input.BooleanGroups, func(g *BooleanGroup) bool { return g.Name == dv.BooleanGroup }) {
+			v.addError(field+".booleanGroup", "variable %s references unknown boolean group %s", name, dv.BooleanGroup)
+		} else if bv, ok := v.vars[name]; ok && normalizeVarType(bv.VarType) != "bool" {
+			v.addError(field+".booleanGroup", "variable %s of type %s can not be in boolean group %s", name, bv.VarType, dv.BooleanGroup)
+		}
+	}
+
+	if dv.ToggleUsingVariable != nil {
+		v.validateToggle(field+".toggleUsingVariable", name, dv.ToggleUsingVariable)
+	}
+	for i, t := range dv.ToggleUsingVariables {
+		v.validateToggle(fmt.Sprintf("%s.toggleUsingVariables[%d]", field, i), name, t)
+	}
+
+	for i, ad := range dv.AltDefaults {
+		v.validateAltDefault(fmt.Sprintf("%s.altDefaults[%d].value", field, i), name, ad)
+	}
+
+	if zone := dv.GetXGoogleProperty().GetZoneProperty(); zone != "" {
+		v.validateZoneProperty(field+".xGoogleProperty.zoneProperty", name, zone)
+	}
+}
+
+// validateToggle checks that toggle t of variable name references a boolean or enum
+// variable and values it can take.
+func (v *displayValidator) validateToggle(field, name string, t *DisplayVariableToggle) {
+	if t.VariableName == name {
+		v.addError(field+".variableName", "variable %s can not be toggled using itself", name)
+		return
+	}
+
+	dv, hasDisplay := v.input.Variables[t.VariableName]
+	bv, hasVar := v.vars[t.VariableName]
+	if !hasDisplay && !hasVar {
+		v.addError(field+".variableName", "variable %s is toggled using unknown variable %s", name, t.VariableName)
+		return
+	}
+
+	values := slices.Clone(t.VariableValues)
+	if t.VariableValue != "" {
+		values = append(values, t.VariableValue)
+	}
+
+	switch {
+	case len(dv.GetEnumValueLabels()) > 0:
+		for _, val := range values {
+			if !slices.ContainsFunc(dv.EnumValueLabels, func(l *ValueLabel) bool { return l.Value == val }) {
+				v.addError(field, "variable %s is toggled using value %q which is not an enum value of variable %s", name, val, t.VariableName)
+			}
+		}
+	case hasVar && normalizeVarType(bv.VarType) == "bool",
+		!hasVar && t.Type == DisplayVariableToggle_DISPLAY_VARIABLE_TOGGLE_TYPE_BOOLEAN:
+		for _, val := range values {
+			if val != "true" && val != "false" {
+				v.addError(field, "variable %s is toggled using value %q which is not a boolean value of variable %s", name, val, t.VariableName)
+			}
+		}
+	case hasVar:
+		v.addError(field+".variableName", "variable %s is toggled using variable %s of type %s which is neither a boolean nor an enum", name, t.VariableName, bv.VarType)
+	}
+}
+
+// validateAltDefault checks that the value of an alternate default of variable name
+// conforms to the type of the variable.
+func (v *displayValidator) validateAltDefault(field, name string, ad *DisplayVariable_AlternateDefault) {
+	bv, ok := v.vars[name]
+	if !ok || ad.Value == nil {
+		return
+	}
+	t, ok := variableCtyType(bv)
+	if !ok || t.HasDynamicTypes() {
+		return
+	}
+
+	b, err := protojson.Marshal(ad.Value)
+	if err != nil {
+		v.addError(field, "unable to read alternate default of variable %s: %v", name, err)
+		return
+	}
+	if _, err := ctyjson.Unmarshal(b, t); err != nil {
+		v.addError(field, "alternate default of variable %s does not conform to type %s: %v", name, bv.VarType, err)
+	}
+}
+
+// validateZoneProperty checks that the zone property of variable name references a
+// variable which is a zone, if its widget is set.
+func (v *displayValidator) validateZoneProperty(field, name, zone string) {
+	dv, hasDisplay := v.input.Variables[zone]
+	_, hasVar := v.vars[zone]
+	if !hasDisplay && !hasVar {
+		v.addError(field, "variable %s references unknown zone property variable %s", name, zone)
+		return
+	}
+	if t := dv.GetXGoogleProperty().GetType(); t != ExtensionType_ET_UNDEFINED && t != ExtensionType_ET_GCE_ZONE {
+		v.addError(field, "variable %s references zone property variable %s of type %s instead of %s", name, zone, t, ExtensionType_ET_GCE_ZONE)
+	}
+}
@@ -0,0 +1,202 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestValidateDisplayMetadata(t *testing.T) {
+	core := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Interfaces: &BlueprintInterface{Variables: []*BlueprintVariable{
+		{Name: "enabled", VarType: "bool"},
+		{Name: "tier", VarType: "string"},
+		{Name: "name", VarType: "string"},
+		{Name: "replicas", VarType: "number"},
+		{Name: "zones", VarType: "list(string)"},
+		{Name: "zone", VarType: "string"},
+	}}}}
+
+	tests := []struct {
+		name  string
+		input *BlueprintUIInput
+		core  *BlueprintMetadata
+		want  []DisplayReferenceError
+	}{
+		{
+			name: "valid",
+			input: &BlueprintUIInput{
+				Sections:      []*DisplaySection{{Name: "main"}, {Name: "advanced", Parent: "main"}},
+				BooleanGroups: []*BooleanGroup{{Name: "features"}},
+				Variables: map[string]*DisplayVariable{
+					"enabled": {Name: "enabled", Section: "main", BooleanGroup: "features"},
+					"tier":    {Name: "tier", EnumValueLabels: []*ValueLabel{{Label: "Basic", Value: "BASIC"}, {Label: "HA", Value: "HA"}}},
+					"name": {
+						Name:                 "name",
+						Section:              "advanced",
+						ToggleUsingVariable:  &DisplayVariableToggle{VariableName: "enabled", Type: DisplayVariableToggle_DISPLAY_VARIABLE_TOGGLE_TYPE_BOOLEAN},
+						ToggleUsingVariables: []*DisplayVariableToggle{{VariableName: "tier", VariableValues: []string{"HA"}}},
+					},
+					"replicas": {Name: "replicas", AltDefaults: []*DisplayVariable_AlternateDefault{{Value: structpb.NewNumberValue(3)}}},
+					"zones":    {Name: "zones", AltDefaults: []*DisplayVariable_AlternateDefault{{Value: structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{structpb.NewStringValue("us-east1-b")}})}}},
+					"zone":     {Name: "zone", XGoogleProperty: &GooglePropertyExtension{Type: ExtensionType_ET_GCE_ZONE}},
+				},
+			},
+			core: core,
+		},
+		{
+			name: "unknown references",
+			input: &BlueprintUIInput{
+				Sections: []*DisplaySection{{Name: "main", Parent: "missing"}, {Name: "main"}},
+				Variables: map[string]*DisplayVariable{
+					"enabled": {Name: "enable", Section: "other", BooleanGroup: "features"},
+					"extra":   {Name: "extra"},
+					"name": {
+						Name:                "name",
+						ToggleUsingVariable: &DisplayVariableToggle{VariableName: "unknown"},
+						XGoogleProperty:     &GooglePropertyExtension{Type: ExtensionType_ET_GCE_MACHINE_TYPE, ZoneProperty: "unknown_zone"},
+					},
+				},
+			},
+			core: core,
+			want: []DisplayReferenceError{
+				{Field: "spec.ui.input.sections[1].name", Message: "section main is defined more than once"},
+				{Field: "spec.ui.input.sections[0].parent", Message: "section main references unknown parent section missing"},
+				{Field: "spec.ui.input.variables.enabled.name", Message: "variable enabled has mismatched name enable"},
+				{Field: "spec.ui.input.variables.enabled.section", Message: "variable enabled references unknown section other"},
+				{Field: "spec.ui.input.variables.enabled.booleanGroup", Message: "variable enabled references unknown boolean group features"},
+				{Field: "spec.ui.input.variables.extra", Message: "variable extra is not defined in metadata"},
+				{Field: "spec.ui.input.variables.name.toggleUsingVariable.variableName", Message: "variable name is toggled using unknown variable unknown"},
+				{Field: "spec.ui.input.variables.name.xGoogleProperty.zoneProperty", Message: "variable name references unknown zone property variable unknown_zone"},
+			},
+		},
+		{
+			name: "section cycles",
+			input: &BlueprintUIInput{
+				Sections: []*DisplaySection{{Name: "a", Parent: "b"}, {Name: "b", Parent: "a"}, {Name: "c", Parent: "a"}, {Name: "d", Parent: "d"}},
+			},
+			want: []DisplayReferenceError{
+				{Field: "spec.ui.input.sections[0].parent", Message: "section a is part of a cycle of parent sections: a -> b -> a"},
+				{Field: "spec.ui.input.sections[1].parent", Message: "section b is part of a cycle of parent sections: b -> a -> b"},
+				{Field: "spec.ui.input.sections[3].parent", Message: "section d is part of a cycle of parent sections: d -> d"},
+			},
+		},
+		{
+			name: "incompatible toggles",
+			input: &BlueprintUIInput{
+				Variables: map[string]*DisplayVariable{
+					"tier": {Name: "tier", EnumValueLabels: []*ValueLabel{{Label: "Basic", Value: "BASIC"}}},
+					"name": {
+						Name:                "name",
+						ToggleUsingVariable: &DisplayVariableToggle{VariableName: "enabled", VariableValue: "yes"},
+						ToggleUsingVariables: []*DisplayVariableToggle{
+							{VariableName: "tier", VariableValues: []string{"BASIC", "PREMIUM"}},
+							{VariableName: "zone"},
+							{VariableName: "name"},
+						},
+					},
+				},
+			},
+			core: core,
+			want: []DisplayReferenceError{
+				{Field: "spec.ui.input.variables.name.toggleUsingVariable", Message: `variable name is toggled using value "yes" which is not a boolean value of variable enabled`},
+				{Field: "spec.ui.input.variables.name.toggleUsingVariables[0]", Message: `variable name is toggled using value "PREMIUM" which is not an enum value of variable tier`},
+				{Field: "spec.ui.input.variables.name.toggleUsingVariables[1].variableName", Message: "variable name is toggled using variable zone of type string which is neither a boolean nor an enum"},
+				{Field: "spec.ui.input.variables.name.toggleUsingVariables[2].variableName", Message: "variable name can not be toggled using itself"},
+			},
+		},
+		{
+			name: "mistyped values",
+			input: &BlueprintUIInput{
+				BooleanGroups: []*BooleanGroup{{Name: "features"}},
+				Variables: map[string]*DisplayVariable{
+					"replicas": {Name: "replicas", AltDefaults: []*DisplayVariable_AlternateDefault{{Value: structpb.NewStringValue("many")}}},
+					"zones":    {Name: "zones", BooleanGroup: "features", AltDefaults: []*DisplayVariable_AlternateDefault{{Value: structpb.NewStringValue("us-east1-b")}}},
+				},
+			},
+			core: core,
+			want: []DisplayReferenceError{
+				{Field: "spec.ui.input.variables.replicas.altDefaults[0].value", Message: "alternate default of variable replicas does not conform to type number: a number is required"},
+				{Field: "spec.ui.input.variables.zones.booleanGroup", Message: "variable zones of type list(string) can not be in boolean group features"},
+				{Field: "spec.ui.input.variables.zones.altDefaults[0].value", Message: "alternate default of variable zones does not conform to type list(string): missing expected ["},
+			},
+		},
+		{
+			name: "zone property of wrong widget",
+			input: &BlueprintUIInput{
+				Variables: map[string]*DisplayVariable{
+					"name": {Name: "name", XGoogleProperty: &GooglePropertyExtension{Type: ExtensionType_ET_GCE_MACHINE_TYPE, ZoneProperty: "zone"}},
+					"zone": {Name: "zone", XGoogleProperty: &GooglePropertyExtension{Type: ExtensionType_ET_GCE_REGION}},
+				},
+			},
+			want: []DisplayReferenceError{
+				{Field: "spec.ui.input.variables.name.xGoogleProperty.zoneProperty", Message: "variable name references zone property variable zone of type ET_GCE_REGION instead of ET_GCE_ZONE"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			display := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Ui: &BlueprintUI{Input: tt.input}}}
+			assert.Equal(t, tt.want, ValidateDisplayMetadata(tt.core, display))
+		})
+	}
+}
+
+func TestValidateDisplayMetadataFile(t *testing.T) {
+	bpPath := t.TempDir()
+	core := `apiVersion: blueprints.cloud.google.com/v1alpha1
+kind: BlueprintMetadata
+metadata:
+  name: sample
+spec:
+  interfaces:
+    variables:
+      - name: enabled
+        varType: bool
+`
+	display := `apiVersion: blueprints.cloud.google.com/v1alpha1
+kind: BlueprintMetadata
+metadata:
+  name: sample-display
+spec:
+  ui:
+    input:
+      variables:
+        enabled:
+          name: enabled
+          title: Enabled
+          section: missing
+`
+	require.NoError(t, os.WriteFile(path.Join(bpPath, metadataFileName), []byte(core), 0644))
+	require.NoError(t, os.WriteFile(path.Join(bpPath, metadataDisplayFileName), []byte(display), 0644))
+
+	var out bytes.Buffer
+	err := validateMetadata(bpPath, "", validateFormatText, &out)
+	assert.ErrorContains(t, err, "metadata validation failed for 1 file(s)")
+	assert.Contains(t, out.String(), metadataDisplayFileName+":12:11: spec.ui.input.variables.enabled.section: variable enabled references unknown section missing")
+}
+
+func TestValidateDisplayMetadataFileWithoutCore(t *testing.T) {
+	bpPath := t.TempDir()
+	display := `apiVersion: blueprints.cloud.google.com/v1alpha1
+kind: BlueprintMetadata
+metadata:
+  name: sample-display
+spec:
+  ui:
+    input:
+      variables:
+        enabled:
+          name: enabled
+          title: Enabled
+`
+	require.NoError(t, os.WriteFile(path.Join(bpPath, metadataDisplayFileName), []byte(display), 0644))
+
+	// variables are only checked against the blueprint if its metadata exists
+	assert.NoError(t, validateDisplayMetadataFile(path.Join(bpPath, metadataDisplayFileName)))
+}
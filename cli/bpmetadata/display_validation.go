@@ -0,0 +1,285 @@
+package bpmetadata
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// maxValidationMessageLength is the max length of DisplayVariable.Validation text.
+const maxValidationMessageLength = 128
+
+// variableValidation is a validation block of a TF variable.
+type variableValidation struct {
+	condition hclsyntax.Expression
+	// source is the condition as authored in the TF config
+	source       string
+	errorMessage string
+}
+
+// getVariableValidations returns validation blocks for all variables in the TF config at bpPath.
+func getVariableValidations(bpPath string) (map[string][]variableValidation, error) {
+	validations := make(map[string][]variableValidation)
+	err := forEachBlock(bpPath, "variable", 1, func(block *hclsyntax.Block, src []byte) {
+		for _, vBlock := range block.Body.Blocks {
+			if vBlock.Type != "validation" {
+				continue
+			}
+			condition, ok := vBlock.Body.Attributes["condition"]
+			if !ok {
+				continue
+			}
+			v := variableValidation{
+				condition: condition.Expr,
+				source:    string(condition.Expr.Range().SliceBytes(src)),
+			}
+			if msg, ok := vBlock.Body.Attributes["error_message"]; ok {
+				if val, diags := msg.Expr.Value(nil); !diags.HasErrors() && val.Type() == cty.String && val.IsKnown() {
+					v.errorMessage = val.AsString()
+				}
+			}
+			validations[block.Labels[0]] = append(validations[block.Labels[0]], v)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return validations, nil
+}
+
+// deriveUIInputValidations translates recognizable variable validation conditions into
+// display variable validation fields. Fields which can not be derived are cleared so that
+// validations removed from the TF config are removed on regeneration. Hand authored values
+// are preserved by the merge with the existing display metadata.
+func deriveUIInputValidations(vars []*BlueprintVariable, validations map[string][]variableValidation, input *BlueprintUIInput) {
+	for _, v := range vars {
+		dv, ok := input.Variables[v.Name]
+		if !ok {
+			continue
+		}
+		derived := &DisplayVariable{}
+		var messages []string
+		for _, validation := range validations[v.Name] {
+			if derived.applyCondition(validation.condition, v.Name, isCollectionVarType(v.VarType)) && validation.errorMessage != "" {
+				messages = append(messages, validation.errorMessage)
+			}
+		}
+		dv.setDerivedValidation(derived, strings.Join(messages, " "))
+	}
+}
+
+// setDerivedValidation sets validation fields to those of derived and the validation
+// text to message, unless it is too long.
+func (dv *DisplayVariable) setDerivedValidation(derived *DisplayVariable, message string) {
+	dv.RegexValidation = derived.RegexValidation
+	dv.MinLength = derived.MinLength
+	dv.MaxLength = derived.MaxLength
+	dv.MinItems = derived.MinItems
+	dv.MaxItems = derived.MaxItems
+	dv.Min = derived.Min
+	dv.Max = derived.Max
+	dv.EnumValueLabels = derived.EnumValueLabels
+	dv.Validation = ""
+	if len(message) <= maxValidationMessageLength {
+		dv.Validation = message
+	}
+}
+
+// applyCondition sets validation fields for recognizable parts of a validation condition
+// on variable name. Returns true if any part of the condition was recognized.
+func (dv *DisplayVariable) applyCondition(expr hclsyntax.Expression, name string, isCollection bool) bool {
+	switch e := expr.(type) {
+	case *hclsyntax.ParenthesesExpr:
+		return dv.applyCondition(e.Expression, name, isCollection)
+	case *hclsyntax.BinaryOpExpr:
+		switch e.Op {
+		case hclsyntax.OpLogicalAnd:
+			lhs := dv.applyCondition(e.LHS, name, isCollection)
+			rhs := dv.applyCondition(e.RHS, name, isCollection)
+			return lhs || rhs
+		case hclsyntax.OpLogicalOr:
+			// only conditions allowing null values are recognized e.g. var.foo == null || ...
+			switch {
+			case isNullCheck(e.LHS, name):
+				return dv.applyCondition(e.RHS, name, isCollection)
+			case isNullCheck(e.RHS, name):
+				return dv.applyCondition(e.LHS, name, isCollection)
+			}
+			return false
+		}
+		return dv.applyComparison(e, name, isCollection)
+	case *hclsyntax.FunctionCallExpr:
+		return dv.applyFunction(e, name)
+	}
+	return false
+}
+
+// applyFunction recognizes regex and enum conditions such as
+// can(regex("^[a-z]+$", var.foo)) and contains(["a", "b"], var.foo).
+func (dv *DisplayVariable) applyFunction(e *hclsyntax.FunctionCallExpr, name string) bool {
+	switch e.Name {
+	case "can":
+		if len(e.Args) == 1 {
+			if inner, ok := e.Args[0].(*hclsyntax.FunctionCallExpr); ok && inner.Name == "regex" {
+				return dv.applyFunction(inner, name)
+			}
+		}
+	case "regex":
+		if len(e.Args) == 2 && isVarRef(e.Args[1], name) {
+			if pattern, ok := stringLiteral(e.Args[0]); ok {
+				dv.RegexValidation = pattern
+				return true
+			}
+		}
+	case "contains":
+		if len(e.Args) != 2 || !isVarRef(e.Args[1], name) {
+			return false
+		}
+		tuple, ok := e.Args[0].(*hclsyntax.TupleConsExpr)
+		if !ok {
+			return false
+		}
+		var labels []*ValueLabel
+		for _, item := range tuple.Exprs {
+			v, ok := stringLiteral(item)
+			if !ok {
+				return false
+			}
+			labels = append(labels, &ValueLabel{Label: v, Value: v})
+		}
+		dv.EnumValueLabels = labels
+		return true
+	}
+	return false
+}
+
+// applyComparison recognizes length and range comparisons such as
+// length(var.foo) <= 10 and var.foo >= 1.
+func (dv *DisplayVariable) applyComparison(e *hclsyntax.BinaryOpExpr, name string, isCollection bool) bool {
+	op := e.Op
+	subject, limitExpr := e.LHS, e.RHS
+	if _, ok := numberLiteral(subject); ok {
+		// normalize comparisons with the number on the left e.g. 1 <= var.foo
+		subject, limitExpr = e.RHS, e.LHS
+		op = reverseComparison(op)
+	}
+	limit, ok := numberLiteral(limitExpr)
+	if !ok {
+		return false
+	}
+
+	var lower, upper *float64
+	switch op {
+	case hclsyntax.OpGreaterThanOrEqual:
+		lower = &limit
+	case hclsyntax.OpGreaterThan:
+		l := limit + 1
+		lower = &l
+	case hclsyntax.OpLessThanOrEqual:
+		upper = &limit
+	case hclsyntax.OpLessThan:
+		u := limit - 1
+		upper = &u
+	case hclsyntax.OpEqual:
+		lower, upper = &limit, &limit
+	default:
+		return false
+	}
+
+	if call, ok := subject.(*hclsyntax.FunctionCallExpr); ok && call.Name == "length" && len(call.Args) == 1 && isVarRef(call.Args[0], name) {
+		if isCollection {
+			setInt32(&dv.MinItems, lower)
+			setInt32(&dv.MaxItems, upper)
+		} else {
+			setInt32(&dv.MinLength, lower)
+			setInt32(&dv.MaxLength, upper)
+		}
+		return true
+	}
+
+	if isVarRef(subject, name) {
+		// strict comparisons of numbers are not limited to integers
+		if op == hclsyntax.OpGreaterThan || op == hclsyntax.OpLessThan {
+			return false
+		}
+		if lower != nil {
+			dv.Min = float32(*lower)
+		}
+		if upper != nil {
+			dv.Max = float32(*upper)
+		}
+		return true
+	}
+	return false
+}
+
+// reverseComparison returns the comparison operator with swapped operands.
+func reverseComparison(op *hclsyntax.Operation) *hclsyntax.Operation {
+	switch op {
+	case hclsyntax.OpGreaterThan:
+		return hclsyntax.OpLessThan
+	case hclsyntax.OpGreaterThanOrEqual:
+		return hclsyntax.OpLessThanOrEqual
+	case hclsyntax.OpLessThan:
+		return hclsyntax.OpGreaterThan
+	case hclsyntax.OpLessThanOrEqual:
+		return hclsyntax.OpGreaterThanOrEqual
+	}
+	return op
+}
+
+func setInt32(field *int32, v *float64) {
+	if v != nil && *v >= 0 {
+		*field = int32(*v)
+	}
+}
+
+// isVarRef returns whether expr is a reference to var.<name>.
+func isVarRef(expr hclsyntax.Expression, name string) bool {
+	e, ok := expr.(*hclsyntax.ScopeTraversalExpr)
+	if !ok || len(e.Traversal) != 2 || e.Traversal.RootName() != "var" {
+		return false
+	}
+	attr, ok := e.Traversal[1].(hcl.TraverseAttr)
+	return ok && attr.Name == name
+}
+
+// isNullCheck returns whether expr is var.<name> == null.
+func isNullCheck(expr hclsyntax.Expression, name string) bool {
+	e, ok := expr.(*hclsyntax.BinaryOpExpr)
+	if !ok || e.Op != hclsyntax.OpEqual {
+		return false
+	}
+	return isVarRef(e.LHS, name) && isNullLiteral(e.RHS) || isVarRef(e.RHS, name) && isNullLiteral(e.LHS)
+}
+
+// stringLiteral returns the value of a static string expression.
+func stringLiteral(expr hclsyntax.Expression) (string, bool) {
+	v, diags := expr.Value(nil)
+	if diags.HasErrors() || !v.IsKnown() || v.IsNull() || v.Type() != cty.String {
+		return "", false
+	}
+	return v.AsString(), true
+}
+
+// numberLiteral returns the value of a static number expression.
+func numberLiteral(expr hclsyntax.Expression) (float64, bool) {
+	v, diags := expr.Value(nil)
+	if diags.HasErrors() || !v.IsKnown() || v.IsNull() || v.Type() != cty.Number {
+		return 0, false
+	}
+	f, _ := v.AsBigFloat().Float64()
+	return f, true
+}
+
+// isCollectionVarType returns whether a TF type constraint is a collection type.
+func isCollectionVarType(varType string) bool {
+	for _, prefix := range []string{"list", "set", "map", "tuple"} {
+		if strings.HasPrefix(strings.TrimSpace(varType), prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package bpmetadata
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDeriveUIInputValidations(t *testing.T) {
+	vars := []*BlueprintVariable{
+		{Name: "name", VarType: "string"},
+		{Name: "size", VarType: "number"},
+		{Name: "tier", VarType: "string"},
+		{Name: "zones", VarType: "list(string)"},
+		{Name: "labels", VarType: "map(string)"},
+		{Name: "description", VarType: "string"},
+	}
+
+	tests := []struct {
+		name     string
+		existing map[string]*DisplayVariable
+		want     map[string]*DisplayVariable
+	}{
+		{
+			name: "derive validations",
+			want: map[string]*DisplayVariable{
+				"name": {
+					Name:            "name",
+					Title:           "Name",
+					RegexValidation: "^[a-z][a-z0-9-]+$",
+					MinLength:       4,
+					MaxLength:       30,
+					Validation:      "Name must start with a letter. Name must be 4 to 30 characters.",
+				},
+				"size":        {Name: "size", Title: "Size", Min: 10, Max: 100, Validation: "Size must be between 10 and 100."},
+				"tier":        {Name: "tier", Title: "Tier", EnumValueLabels: []*ValueLabel{{Label: "STANDARD", Value: "STANDARD"}, {Label: "PREMIUM", Value: "PREMIUM"}}, Validation: "Tier must be STANDARD or PREMIUM."},
+				"zones":       {Name: "zones", Title: "Zones", MinItems: 1, MaxItems: 3, Validation: "Between 1 and 3 zones are allowed."},
+				"labels":      {Name: "labels", Title: "Labels"},
+				"description": {Name: "description", Title: "Description", MaxLength: 256, Validation: "Description must be at most 256 characters."},
+			},
+		},
+		{
+			name: "replace previously set values",
+			existing: map[string]*DisplayVariable{
+				"name":   {Name: "name", Title: "Instance Name", RegexValidation: "^[a-z]+$", Validation: "Lowercase letters only."},
+				"size":   {Name: "size", Title: "Size", Max: 50},
+				"tier":   {Name: "tier", Title: "Tier", EnumValueLabels: []*ValueLabel{{Label: "Standard", Value: "STANDARD"}}},
+				"labels": {Name: "labels", Title: "Labels", MinItems: 2, Validation: "At least two labels."},
+			},
+			want: map[string]*DisplayVariable{
+				"name": {
+					Name:            "name",
+					Title:           "Instance Name",
+					RegexValidation: "^[a-z][a-z0-9-]+$",
+					MinLength:       4,
+					MaxLength:       30,
+					Validation:      "Name must start with a letter. Name must be 4 to 30 characters.",
+				},
+				"size":   {Name: "size", Title: "Size", Min: 10, Max: 100, Validation: "Size must be between 10 and 100."},
+				"tier":   {Name: "tier", Title: "Tier", EnumValueLabels: []*ValueLabel{{Label: "STANDARD", Value: "STANDARD"}, {Label: "PREMIUM", Value: "PREMIUM"}}, Validation: "Tier must be STANDARD or PREMIUM."},
+				"labels": {Name: "labels", Title: "Labels"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validations, err := getVariableValidations(path.Join(tfTestdataPath, "variable-validations"))
+			assert.NoError(t, err)
+
+			input := &BlueprintUIInput{Variables: tt.existing}
+			buildUIInputFromVariables(vars, input)
+			deriveUIInputValidations(vars, validations, input)
+			for name, want := range tt.want {
+				assert.Truef(t, proto.Equal(want, input.Variables[name]), "variable %s: want %v, got %v", name, want, input.Variables[name])
+			}
+		})
+	}
+}
+
+func TestDeriveUIInputValidationsRegeneration(t *testing.T) {
+	vars := []*BlueprintVariable{
+		{Name: "name", VarType: "string"},
+		{Name: "size", VarType: "number"},
+		{Name: "tier", VarType: "string"},
+	}
+	validations, err := getVariableValidations(path.Join(tfTestdataPath, "variable-validations"))
+	require.NoError(t, err)
+
+	generate := func(existing *BlueprintMetadata, validations map[string][]variableValidation) *BlueprintMetadata {
+		display := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Ui: &BlueprintUI{Input: &BlueprintUIInput{}}}}
+		if existing != nil {
+			display = proto.Clone(existing).(*BlueprintMetadata)
+		}
+		buildUIInputFromVariables(vars, display.Spec.Ui.Input)
+		deriveUIInputValidations(vars, validations, display.Spec.Ui.Input)
+		return display
+	}
+
+	previous := generate(nil, validations)
+	base, _ := mergeGeneratedMetadata(nil, previous, nil)
+
+	// hand edit a derived value and remove a TF validation
+	existing := proto.Clone(previous).(*BlueprintMetadata)
+	existing.Spec.Ui.Input.Variables["name"].Validation = "Lowercase letters only."
+	existing.Spec.Ui.Input.Variables["tier"].MinLength = 1
+	changed := map[string][]variableValidation{"name": validations["name"], "tier": validations["tier"]}
+
+	generated := generate(existing, changed)
+	_, conflicts := mergeGeneratedMetadata(existing, generated, base)
+	assert.Empty(t, conflicts)
+	got := generated.Spec.Ui.Input.Variables
+	assert.Equal(t, "Lowercase letters only.", got["name"].Validation)
+	assert.Equal(t, int32(4), got["name"].MinLength)
+	assert.Equal(t, int32(1), got["tier"].MinLength)
+	assert.True(t, proto.Equal(&DisplayVariable{Name: "size", Title: "Size"}, got["size"]), "got %v", got["size"])
+}
@@ -0,0 +1,275 @@
+package bpmetadata
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// widgetRule proposes an extension type for variables of a TF type.
+type widgetRule struct {
+	extType ExtensionType
+	// varType is the TF type the variable must have e.g. string or number
+	varType string
+}
+
+var (
+	// resource attributes mapped to widgets. Keys are either resource type qualified
+	// attribute paths e.g. google_compute_instance.machine_type or attribute paths
+	// prefixed with * to match any resource type e.g. *.zone.
+	attrWidgets = map[string]widgetRule{
+		"*.region":                                             {ExtensionType_ET_GCE_REGION, "string"},
+		"*.zone":                                               {ExtensionType_ET_GCE_ZONE, "string"},
+		"*.machine_type":                                       {ExtensionType_ET_GCE_MACHINE_TYPE, "string"},
+		"*.node_config.machine_type":                           {ExtensionType_ET_GCE_MACHINE_TYPE, "string"},
+		"*.network":                                            {ExtensionType_ET_GCE_NETWORK, "string"},
+		"*.network_interface.network":                          {ExtensionType_ET_GCE_NETWORK, "string"},
+		"*.subnetwork":                                         {ExtensionType_ET_GCE_SUBNETWORK, "string"},
+		"*.network_interface.subnetwork":                       {ExtensionType_ET_GCE_SUBNETWORK, "string"},
+		"*.bucket":                                             {ExtensionType_ET_GCS_BUCKET, "string"},
+		"*.service_account_email":                              {ExtensionType_ET_IAM_SERVICE_ACCOUNT, "string"},
+		"*.service_account.email":                              {ExtensionType_ET_IAM_SERVICE_ACCOUNT, "string"},
+		"*.node_config.service_account":                        {ExtensionType_ET_IAM_SERVICE_ACCOUNT, "string"},
+		"*.guest_accelerator.type":                             {ExtensionType_ET_GCE_GPU_TYPE, "string"},
+		"*.guest_accelerator.count":                            {ExtensionType_ET_GCE_GPU_COUNT, "number"},
+		"*.boot_disk.initialize_params.image":                  {ExtensionType_ET_GCE_DISK_IMAGE, "string"},
+		"*.boot_disk.initialize_params.type":                   {ExtensionType_ET_GCE_DISK_TYPE, "string"},
+		"*.boot_disk.initialize_params.size":                   {ExtensionType_ET_GCE_DISK_SIZE, "number"},
+		"*.disk.source_image":                                  {ExtensionType_ET_GCE_DISK_IMAGE, "string"},
+		"*.disk.disk_type":                                     {ExtensionType_ET_GCE_DISK_TYPE, "string"},
+		"*.disk.disk_size_gb":                                  {ExtensionType_ET_GCE_DISK_SIZE, "number"},
+		"*.node_config.disk_type":                              {ExtensionType_ET_GCE_DISK_TYPE, "string"},
+		"*.node_config.disk_size_gb":                           {ExtensionType_ET_GCE_DISK_SIZE, "number"},
+		"*.node_config.image_type":                             {ExtensionType_ET_GCE_DISK_IMAGE, "string"},
+		"google_compute_disk.image":                            {ExtensionType_ET_GCE_DISK_IMAGE, "string"},
+		"google_compute_disk.type":                             {ExtensionType_ET_GCE_DISK_TYPE, "string"},
+		"google_compute_disk.size":                             {ExtensionType_ET_GCE_DISK_SIZE, "number"},
+		"google_compute_instance.can_ip_forward":               {ExtensionType_ET_GCE_IP_FORWARDING, "bool"},
+		"google_compute_instance_template.can_ip_forward":      {ExtensionType_ET_GCE_IP_FORWARDING, "bool"},
+		"google_compute_firewall.source_ranges":                {ExtensionType_ET_GCE_FIREWALL_RANGE, "list(string)"},
+		"google_compute_instance_template.machine_type":        {ExtensionType_ET_GCE_MACHINE_TYPE, "string"},
+		"google_cloud_run_v2_service.template.service_account": {ExtensionType_ET_IAM_SERVICE_ACCOUNT, "string"},
+		"google_cloudfunctions2_function.service_config.service_account_email": {ExtensionType_ET_IAM_SERVICE_ACCOUNT, "string"},
+	}
+
+	// variable name patterns mapped to widgets, checked in order.
+	nameWidgets = []struct {
+		pattern *regexp.Regexp
+		rule    widgetRule
+	}{
+		{regexp.MustCompile(`(^|_)region$`), widgetRule{ExtensionType_ET_GCE_REGION, "string"}},
+		{regexp.MustCompile(`(^|_)zone$`), widgetRule{ExtensionType_ET_GCE_ZONE, "string"}},
+		{regexp.MustCompile(`(^|_)machine_type$`), widgetRule{ExtensionType_ET_GCE_MACHINE_TYPE, "string"}},
+		{regexp.MustCompile(`(^|_)subnet(work)?(_name|_self_link|_id)?$`), widgetRule{ExtensionType_ET_GCE_SUBNETWORK, "string"}},
+		{regexp.MustCompile(`(^|_)(network|vpc)(_name|_self_link|_id)?$`), widgetRule{ExtensionType_ET_GCE_NETWORK, "string"}},
+		{regexp.MustCompile(`(^|_)service_account(_email)?$|(^|_)sa_email$`), widgetRule{ExtensionType_ET_IAM_SERVICE_ACCOUNT, "string"}},
+		{regexp.MustCompile(`(^|_)bucket(_name)?$`), widgetRule{ExtensionType_ET_GCS_BUCKET, "string"}},
+		{regexp.MustCompile(`(^|_)disk_type$`), widgetRule{ExtensionType_ET_GCE_DISK_TYPE, "string"}},
+		{regexp.MustCompile(`(^|_)disk_size(_gb)?$`), widgetRule{ExtensionType_ET_GCE_DISK_SIZE, "number"}},
+		{regexp.MustCompile(`(^|_)(source|disk|boot)_image$`), widgetRule{ExtensionType_ET_GCE_DISK_IMAGE, "string"}},
+		{regexp.MustCompile(`(^|_)(gpu|accelerator)_type$`), widgetRule{ExtensionType_ET_GCE_GPU_TYPE, "string"}},
+		{regexp.MustCompile(`(^|_)(gpu|accelerator)_count$`), widgetRule{ExtensionType_ET_GCE_GPU_COUNT, "number"}},
+	}
+
+	// description phrases mapped to widgets, checked in order.
+	descriptionWidgets = []struct {
+		phrase string
+		rule   widgetRule
+	}{
+		{"machine type", widgetRule{ExtensionType_ET_GCE_MACHINE_TYPE, "string"}},
+		{"service account email", widgetRule{ExtensionType_ET_IAM_SERVICE_ACCOUNT, "string"}},
+		{"subnetwork", widgetRule{ExtensionType_ET_GCE_SUBNETWORK, "string"}},
+		{"gcs bucket", widgetRule{ExtensionType_ET_GCS_BUCKET, "string"}},
+	}
+
+	// extension types that require a zone context
+	zonalExtensionTypes = []ExtensionType{ExtensionType_ET_GCE_MACHINE_TYPE, ExtensionType_ET_GCE_DISK_TYPE, ExtensionType_ET_GCE_GPU_TYPE}
+)
+
+// variableFlows maps variable names to the resource attributes they are used in.
+type variableFlows map[string][]variableFlow
+
+// variableFlow is a resource attribute a variable flows into.
+type variableFlow struct {
+	// resource is the resource address e.g. google_compute_instance.vm
+	resource string
+	// attr is the resource type qualified attribute path e.g. google_compute_instance.machine_type
+	attr string
+}
+
+// inferWidgets proposes GooglePropertyExtension widgets for display variables based on
+// variable names, types, descriptions and the resource attributes they flow into.
+// Display variables with an existing extension are not modified.
+func inferWidgets(bpPath string, vars []*BlueprintVariable, input *BlueprintUIInput) error {
+	flows, err := getVariableFlows(bpPath)
+	if err != nil {
+		return err
+	}
+
+	inferred := make(map[string]ExtensionType)
+	for _, v := range vars {
+		dv, ok := input.Variables[v.Name]
+		if !ok || dv.XGoogleProperty != nil {
+			continue
+		}
+		if t, ok := inferWidgetType(v, flows[v.Name]); ok {
+			inferred[v.Name] = t
+		}
+	}
+
+	for _, name := range sortedKeys(inferred) {
+		extType := inferred[name]
+		ext := &GooglePropertyExtension{Type: extType}
+		if containsExtType(zonalExtensionTypes, extType) {
+			ext.ZoneProperty = linkedVariable(name, ExtensionType_ET_GCE_ZONE, inferred, flows)
+		}
+		switch extType {
+		case ExtensionType_ET_GCE_SUBNETWORK:
+			if network := linkedVariable(name, ExtensionType_ET_GCE_NETWORK, inferred, flows); network != "" {
+				ext.GceSubnetwork = &GCESubnetworkExtension{NetworkVariable: network}
+			}
+		case ExtensionType_ET_GCE_DISK_SIZE:
+			if diskType := linkedVariable(name, ExtensionType_ET_GCE_DISK_TYPE, inferred, flows); diskType != "" {
+				ext.GceDiskSize = &GCEDiskSizeExtension{DiskTypeVariable: diskType}
+			}
+		case ExtensionType_ET_GCE_DISK_TYPE:
+			if machineType := linkedVariable(name, ExtensionType_ET_GCE_MACHINE_TYPE, inferred, flows); machineType != "" {
+				ext.GceDiskType = &GCEDiskTypeExtension{MachineTypeVariable: machineType}
+			}
+		case ExtensionType_ET_GCE_GPU_COUNT:
+			if machineType := linkedVariable(name, ExtensionType_ET_GCE_MACHINE_TYPE, inferred, flows); machineType != "" {
+				ext.GceGpuCount = &GCEGPUCountExtension{MachineTypeVariable: machineType}
+			}
+		}
+		input.Variables[name].XGoogleProperty = ext
+		Log.Info("inferred widget for variable", "variable", name, "type", extType.String())
+	}
+	return nil
+}
+
+// inferWidgetType returns the extension type for a variable. Resource attributes the
+// variable flows into take precedence over its name which takes precedence over its description.
+func inferWidgetType(v *BlueprintVariable, flows []variableFlow) (ExtensionType, bool) {
+	varType := normalizeVarType(v.VarType)
+	for _, f := range flows {
+		rule, ok := attrWidgets[f.attr]
+		if !ok {
+			_, attrPath, _ := strings.Cut(f.attr, ".")
+			rule, ok = attrWidgets["*."+attrPath]
+		}
+		if ok && rule.varType == varType {
+			return rule.extType, true
+		}
+	}
+	for _, nw := range nameWidgets {
+		if nw.pattern.MatchString(v.Name) && nw.rule.varType == varType {
+			return nw.rule.extType, true
+		}
+	}
+	description := strings.ToLower(v.Description)
+	for _, dw := range descriptionWidgets {
+		if strings.Contains(description, dw.phrase) && dw.rule.varType == varType {
+			return dw.rule.extType, true
+		}
+	}
+	return ExtensionType_ET_UNDEFINED, false
+}
+
+// linkedVariable returns the variable of extType that name should be linked to.
+// Variables flowing into the same resource are preferred, otherwise the only
+// variable of extType is used if there is exactly one.
+func linkedVariable(name string, extType ExtensionType, inferred map[string]ExtensionType, flows variableFlows) string {
+	var candidates []string
+	for _, n := range sortedKeys(inferred) {
+		if inferred[n] == extType && n != name {
+			candidates = append(candidates, n)
+		}
+	}
+	resources := make(map[string]bool)
+	for _, f := range flows[name] {
+		resources[f.resource] = true
+	}
+	for _, c := range candidates {
+		for _, f := range flows[c] {
+			if resources[f.resource] {
+				return c
+			}
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return ""
+}
+
+// getVariableFlows returns the resource attributes each variable is directly referenced in
+// for the TF config at bpPath.
+func getVariableFlows(bpPath string) (variableFlows, error) {
+	flows := make(variableFlows)
+	err := forEachBlock(bpPath, "resource", 2, func(block *hclsyntax.Block, _ []byte) {
+		address := block.Labels[0] + "." + block.Labels[1]
+		flows.addBody(block.Body, address, block.Labels[0])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return flows, nil
+}
+
+// addBody records variables referenced by attributes within body and any nested blocks.
+func (flows variableFlows) addBody(body *hclsyntax.Body, address, attrPath string) {
+	for name, attr := range body.Attributes {
+		for _, traversal := range attr.Expr.Variables() {
+			if traversal.RootName() != "var" || len(traversal) < 2 {
+				continue
+			}
+			varAttr, ok := traversal[1].(hcl.TraverseAttr)
+			if !ok {
+				continue
+			}
+			flows[varAttr.Name] = append(flows[varAttr.Name], variableFlow{resource: address, attr: attrPath + "." + name})
+		}
+	}
+	for _, nested := range body.Blocks {
+		if nested.Type == "dynamic" && len(nested.Labels) == 1 {
+			// attributes of dynamic blocks are within a content block
+			for _, content := range nested.Body.Blocks {
+				if content.Type == "content" {
+					flows.addBody(content.Body, address, attrPath+"."+nested.Labels[0])
+				}
+			}
+			continue
+		}
+		flows.addBody(nested.Body, address, attrPath+"."+nested.Type)
+	}
+}
+
+// normalizeVarType normalizes a TF type constraint for comparison, treating untyped variables as strings.
+func normalizeVarType(varType string) string {
+	t := strings.Join(strings.Fields(varType), "")
+	if t == "" {
+		return "string"
+	}
+	return t
+}
+
+func containsExtType(types []ExtensionType, t ExtensionType) bool {
+	for _, et := range types {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,151 @@
+package bpmetadata
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestInferWidgets(t *testing.T) {
+	vars := []*BlueprintVariable{
+		{Name: "vm_zone", VarType: "string"},
+		{Name: "instance_size", VarType: "string"},
+		{Name: "boot_disk_type", VarType: "string"},
+		{Name: "boot_disk_size", VarType: "number"},
+		{Name: "network", VarType: "string"},
+		{Name: "subnet", VarType: "string"},
+		{Name: "gpus", VarType: "number"},
+		{Name: "region", VarType: "string"},
+		{Name: "sa", VarType: "string"},
+		{Name: "state_bucket", VarType: "string"},
+		{Name: "labels", VarType: "map(string)"},
+	}
+
+	tests := []struct {
+		name     string
+		existing map[string]*GooglePropertyExtension
+		want     map[string]*GooglePropertyExtension
+	}{
+		{
+			name: "infer widgets",
+			want: map[string]*GooglePropertyExtension{
+				"vm_zone":       {Type: ExtensionType_ET_GCE_ZONE},
+				"instance_size": {Type: ExtensionType_ET_GCE_MACHINE_TYPE, ZoneProperty: "vm_zone"},
+				"boot_disk_type": {
+					Type:         ExtensionType_ET_GCE_DISK_TYPE,
+					ZoneProperty: "vm_zone",
+					GceDiskType:  &GCEDiskTypeExtension{MachineTypeVariable: "instance_size"},
+				},
+				"boot_disk_size": {Type: ExtensionType_ET_GCE_DISK_SIZE, GceDiskSize: &GCEDiskSizeExtension{DiskTypeVariable: "boot_disk_type"}},
+				"network":        {Type: ExtensionType_ET_GCE_NETWORK},
+				"subnet":         {Type: ExtensionType_ET_GCE_SUBNETWORK, GceSubnetwork: &GCESubnetworkExtension{NetworkVariable: "network"}},
+				"gpus":           {Type: ExtensionType_ET_GCE_GPU_COUNT, GceGpuCount: &GCEGPUCountExtension{MachineTypeVariable: "instance_size"}},
+				"region":         {Type: ExtensionType_ET_GCE_REGION},
+				"sa":             {Type: ExtensionType_ET_IAM_SERVICE_ACCOUNT},
+				"state_bucket":   {Type: ExtensionType_ET_GCS_BUCKET},
+			},
+		},
+		{
+			name: "preserve hand authored widgets",
+			existing: map[string]*GooglePropertyExtension{
+				"network": {Type: ExtensionType_ET_GCE_GENERIC_RESOURCE},
+				"vm_zone": {Type: ExtensionType_ET_GCE_LOCATION},
+			},
+			want: map[string]*GooglePropertyExtension{
+				"vm_zone":       {Type: ExtensionType_ET_GCE_LOCATION},
+				"instance_size": {Type: ExtensionType_ET_GCE_MACHINE_TYPE},
+				"boot_disk_type": {
+					Type:        ExtensionType_ET_GCE_DISK_TYPE,
+					GceDiskType: &GCEDiskTypeExtension{MachineTypeVariable: "instance_size"},
+				},
+				"boot_disk_size": {Type: ExtensionType_ET_GCE_DISK_SIZE, GceDiskSize: &GCEDiskSizeExtension{DiskTypeVariable: "boot_disk_type"}},
+				"network":        {Type: ExtensionType_ET_GCE_GENERIC_RESOURCE},
+				"subnet":         {Type: ExtensionType_ET_GCE_SUBNETWORK},
+				"gpus":           {Type: ExtensionType_ET_GCE_GPU_COUNT, GceGpuCount: &GCEGPUCountExtension{MachineTypeVariable: "instance_size"}},
+				"region":         {Type: ExtensionType_ET_GCE_REGION},
+				"sa":             {Type: ExtensionType_ET_IAM_SERVICE_ACCOUNT},
+				"state_bucket":   {Type: ExtensionType_ET_GCS_BUCKET},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &BlueprintUIInput{Variables: make(map[string]*DisplayVariable)}
+			for _, v := range vars {
+				input.Variables[v.Name] = &DisplayVariable{Name: v.Name, XGoogleProperty: tt.existing[v.Name]}
+			}
+
+			err := inferWidgets(path.Join(tfTestdataPath, "infer-widgets"), vars, input)
+			assert.NoError(t, err)
+			for _, v := range vars {
+				got := input.Variables[v.Name].XGoogleProperty
+				assert.True(t, proto.Equal(tt.want[v.Name], got), "variable %s: want %v got %v", v.Name, tt.want[v.Name], got)
+			}
+		})
+	}
+}
+
+func TestInferWidgetType(t *testing.T) {
+	tests := []struct {
+		name   string
+		v      *BlueprintVariable
+		flows  []variableFlow
+		want   ExtensionType
+		wantOK bool
+	}{
+		{
+			name:   "resource attribute",
+			v:      &BlueprintVariable{Name: "size", VarType: "string"},
+			flows:  []variableFlow{{resource: "google_compute_instance.vm", attr: "google_compute_instance.machine_type"}},
+			want:   ExtensionType_ET_GCE_MACHINE_TYPE,
+			wantOK: true,
+		},
+		{
+			name:   "resource specific attribute",
+			v:      &BlueprintVariable{Name: "size", VarType: "number"},
+			flows:  []variableFlow{{resource: "google_compute_disk.d", attr: "google_compute_disk.size"}},
+			want:   ExtensionType_ET_GCE_DISK_SIZE,
+			wantOK: true,
+		},
+		{
+			name:   "attribute takes precedence over name",
+			v:      &BlueprintVariable{Name: "region", VarType: "string"},
+			flows:  []variableFlow{{resource: "google_compute_instance.vm", attr: "google_compute_instance.zone"}},
+			want:   ExtensionType_ET_GCE_ZONE,
+			wantOK: true,
+		},
+		{
+			name:   "untyped variable name",
+			v:      &BlueprintVariable{Name: "subnetwork_self_link"},
+			want:   ExtensionType_ET_GCE_SUBNETWORK,
+			wantOK: true,
+		},
+		{
+			name:   "description",
+			v:      &BlueprintVariable{Name: "vm_size", VarType: "string", Description: "The Machine Type of the VM"},
+			want:   ExtensionType_ET_GCE_MACHINE_TYPE,
+			wantOK: true,
+		},
+		{
+			name: "type mismatch",
+			v:    &BlueprintVariable{Name: "zone", VarType: "list(string)"},
+			want: ExtensionType_ET_UNDEFINED,
+		},
+		{
+			name: "no match",
+			v:    &BlueprintVariable{Name: "project_id", VarType: "string", Description: "The project ID"},
+			want: ExtensionType_ET_UNDEFINED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := inferWidgetType(tt.v, tt.flows)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -47,6 +47,114 @@
 //
 //	cft blueprint metadata -h
 //
+// Quota details and org policy checks are hand-authored but can be proposed from the resources
+// of the package with "--infer-quotas-and-policies" while they are empty:
+//   - Compute instances and disks are quota details with their machine types, CPUs, GPUs, disk
+//     types and sizes. Values set from a variable are its default and the variable is recorded
+//     as the dynamic variable of the quota detail.
+//   - Instances with external IPs or IP forwarding, buckets granting access to allUsers or
+//     allAuthenticatedUsers and service account keys are org policy checks for the policies
+//     they require exceptions for.
+//
+// # Generating metadata for a KRM package
+//
+// Metadata for KRM blueprints, i.e. kpt packages with a "Kptfile" and a readme, is generated
+// the same way as for Terraform packages with the actuation tool flavor set to "KRM":
+//   - Setters of "apply-setters" functions in the Kptfile pipeline are variables with the
+//     setter values as defaults.
+//   - Config Connector resource kinds are documentation linking to their reference docs.
+//   - Services used by Config Connector resources are requirements.
+//   - Nested kpt packages are sub-blueprints and kpt packages under examples/ are examples.
+//
+// # Generating metadata for many blueprints
+//
+// Generate or validate metadata for all blueprint repos checked out under a directory as:
+//
+//	cft blueprint metadata --recursive <DIR> -d [--workers <N>]
+//	cft blueprint metadata --recursive <DIR> -v
+//
+// Blueprint repos are git repo roots with a readme and Terraform config or a Kptfile. Repos are processed
+// concurrently by a pool of workers without prompting for input and a summary table of the
+// created, updated, unchanged and failed (or valid and invalid) metadata files is printed
+// per repo.
+//
+// # Generating metadata from Go
+//
+// Metadata can also be generated by other tools with [Generate], which is configured with
+// [Options] instead of CLI flags and returns a [Result] with the generated metadata and
+// warnings per blueprint and sub-module:
+//
+//	results, err := bpmetadata.Generate(bpmetadata.Options{Path: bpPath, Nested: true, Quiet: true})
+//
+// Generate does not prompt for input unless a Prompt function is provided and may be called
+// concurrently for different blueprints. Output types are generated from Terraform state for
+// one blueprint at a time since this applies the blueprint with the blueprint test framework;
+// set OutputTypeSource to "static" to infer them without Terraform.
+//
+// # Regenerating metadata
+//
+// Each metadata field is either generated (e.g. variables, outputs, requirements and info read
+// from the README) or user-owned (e.g. variable connections and any field not populated by the
+// CLI). Regeneration performs a three-way merge of the existing metadata, the newly generated
+// metadata and the metadata of the last generation. Fingerprints of the values of the last
+// generation are recorded next to each metadata file in ".metadata.yaml.generated" and
+// ".metadata.display.yaml.generated" which should be committed along with the metadata:
+//   - Generated fields that were not edited are updated.
+//   - Generated fields edited by hand are kept as long as their generated value is unchanged.
+//   - Generated fields edited by hand whose generated value changed are kept and reported as
+//     conflicts until the edit is reverted, the field is claimed as user-owned or metadata is
+//     regenerated with "--force".
+//   - Variables, outputs and other elements added by hand are kept while elements which are
+//     no longer generated are removed unless they were edited by hand.
+//
+// Without fingerprints of the last generation, e.g. for metadata generated by earlier versions of
+// the CLI, existing values and elements that differ from the generated ones are kept and reported
+// as conflicts.
+//
+// Generated fields can be claimed as user-owned by listing comma separated field paths in the
+// "blueprints.cloud.google.com/user-owned-fields" annotation e.g.
+//
+//	metadata:
+//	  annotations:
+//	    blueprints.cloud.google.com/user-owned-fields: spec.info.description.tagline, spec.interfaces.variables[project_id].description
+//
+// # Previewing display metadata
+//
+// Preview the UI rendering of "metadata.display.yaml" locally with the CFT CLI as:
+//
+//	cft blueprint metadata preview -p <SOLUTION_ROOT_PATH> [--addr localhost:8080]
+//
+// This serves the input form with sections nested by their parent, boolean groups, advanced
+// levels and alternate defaults along with the runtime outputs. Toggles and validations are
+// evaluated as values are edited. Metadata files are read on each page load, so edits are
+// shown on reload.
+//
+// # Generating README inputs and outputs
+//
+// Render the Inputs and Outputs tables of the README for your root and sub modules from
+// "metadata.yaml" with the CFT CLI as:
+//
+//	cft blueprint metadata readme -p <SOLUTION_ROOT_PATH> [--requirements] [--check]
+//
+// Tables are rendered in the format of terraform-docs between the
+// "<!-- BEGINNING OF PRE-COMMIT-TERRAFORM DOCS HOOK -->" and
+// "<!-- END OF PRE-COMMIT-TERRAFORM DOCS HOOK -->" markers of each README. Sub module READMEs
+// without these markers are skipped. With "--requirements" the roles and services required
+// are rendered as well. With "--check" no README is written, a diff of outdated READMEs is
+// printed and the command fails, e.g. in CI.
+//
+// # Generating examples
+//
+// Generate an example root module calling your blueprint from "metadata.yaml" with the CFT CLI as:
+//
+//	cft blueprint metadata example -p <SOLUTION_ROOT_PATH> --out examples/<NAME>
+//
+// Required variables are set to their first alternate default or enum value from
+// "metadata.display.yaml", or else to a placeholder of their type, while "project_id" is
+// passed through as a variable of the example. Providers are required at the versions of the
+// blueprint requirements and all outputs are forwarded, so tests can be scaffolded for the
+// example with "cft test init <NAME>".
+//
 // # Validating metadata for schema consistencies
 //
 // Validate metadata for your root and sub modules with the CFT CLI as:
@@ -55,7 +163,24 @@
 //
 // This will output a success message i.e. "metadata is valid" if all fields in all metadata files
 // are consistent with the [BlueprintMetadata] schema. Otherwise, error messages for invalid field
-// names, types or values will be shown.
+// names, types or values will be shown along with their file:line:column location, grouped per
+// file. Use "--format json" for machine readable validation errors.
+//
+// Display metadata is additionally checked for references the schema can not express i.e.
+// sections, parent sections and boolean groups must exist without parent cycles, toggles must
+// reference boolean or enum variables and their values, alternate defaults must conform to the
+// variable type and zone properties must reference a zone variable.
+//
+// # Migrating metadata to a new API version
+//
+// Migrate metadata for your root and sub modules to another API version with the CFT CLI as:
+//
+//	cft blueprint metadata migrate --to <API_VERSION>
+//
+// This rewrites metadata files in place, formatted the same way as generated metadata.
+// Hand-authored content is kept. Files with comments are not migrated since comments can not
+// be preserved; remove or move them first. Metadata of older API versions with a migration
+// is also read by all other commands.
 //
 // [BlueprintMetadata]: https://pkg.go.dev/github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata#BlueprintMetadata
 // [metadata.yaml]: https://github.com/g-awmalik/terraform-google-canonical-mp/blob/main/metadata.yaml
@@ -0,0 +1,245 @@
+package bpmetadata
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/iancoleman/strcase"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// exampleProjectVar is the variable passed through from examples instead of a placeholder
+// as the project is provided by the test setup.
+const exampleProjectVar = "project_id"
+
+// exampleFile is a file of a generated example.
+type exampleFile struct {
+	name string
+	file *hclwrite.File
+}
+
+// generateExample writes a root module to outPath calling the blueprint at bpPath with all
+// required variables, the providers it requires and its outputs forwarded.
+func generateExample(bpPath, outPath string, w io.Writer) error {
+	core, err := UnmarshalMetadata(bpPath, metadataFileName)
+	if err != nil {
+		return fmt.Errorf("unable to read metadata for blueprint at path %s: %w", bpPath, err)
+	}
+	if flavor := core.GetSpec().GetInfo().GetActuationTool().GetFlavor(); flavor != "" && flavor != "Terraform" {
+		return fmt.Errorf("examples can only be generated for Terraform blueprints, blueprint at path %s is %s", bpPath, flavor)
+	}
+	display, err := UnmarshalMetadata(bpPath, metadataDisplayFileName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to read display metadata for blueprint at path %s: %w", bpPath, err)
+	}
+
+	if entries, err := os.ReadDir(outPath); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s already exists and is not empty", outPath)
+	}
+	source, err := exampleModuleSource(bpPath, outPath)
+	if err != nil {
+		return err
+	}
+
+	files := buildExample(core, display, source)
+	if err := os.MkdirAll(outPath, 0755); err != nil {
+		return fmt.Errorf("error creating example dir: %w", err)
+	}
+	for _, f := range files {
+		p := path.Join(outPath, f.name)
+		if err := os.WriteFile(p, hclwrite.Format(f.file.Bytes()), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "created %s\n", displayPath(p))
+	}
+	return nil
+}
+
+// exampleModuleSource returns the path to the blueprint relative to the example.
+func exampleModuleSource(bpPath, outPath string) (string, error) {
+	bpAbs, err := filepath.Abs(bpPath)
+	if err != nil {
+		return "", err
+	}
+	outAbs, err := filepath.Abs(outPath)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(outAbs, bpAbs)
+	if err != nil {
+		return "", fmt.Errorf("error resolving blueprint path relative to %s: %w", outPath, err)
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, ".") {
+		rel = "./" + rel
+	}
+	return rel, nil
+}
+
+// buildExample returns the files of an example calling the blueprint described by core
+// from source. Required variables are set to the first alternate default or enum value
+// of display, if any, or to placeholders of their type. Files without content are omitted.
+func buildExample(core, display *BlueprintMetadata, source string) []exampleFile {
+	moduleName := exampleModuleName(core.GetMetadata().GetName())
+	displayVars := display.GetSpec().GetUi().GetInput().GetVariables()
+
+	main := hclwrite.NewEmptyFile()
+	module := main.Body().AppendNewBlock("module", []string{moduleName}).Body()
+	module.SetAttributeValue("source", cty.StringVal(source))
+
+	variables := hclwrite.NewEmptyFile()
+	for _, v := range core.GetSpec().GetInterfaces().GetVariables() {
+		if !v.Required {
+			continue
+		}
+		if v.Name == exampleProjectVar {
+			module.SetAttributeTraversal(v.Name, hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: v.Name}})
+			vb := variables.Body().AppendNewBlock("variable", []string{v.Name}).Body()
+			if v.Description != "" {
+				vb.SetAttributeValue("description", cty.StringVal(v.Description))
+			}
+			if v.VarType != "" {
+				vb.SetAttributeRaw("type", hclwrite.TokensForIdentifier(v.VarType))
+			}
+			continue
+		}
+		module.SetAttributeValue(v.Name, exampleValue(v, displayVars[v.Name]))
+	}
+
+	outputs := hclwrite.NewEmptyFile()
+	for i, o := range core.GetSpec().GetInterfaces().GetOutputs() {
+		if i > 0 {
+			outputs.Body().AppendNewline()
+		}
+		ob := outputs.Body().AppendNewBlock("output", []string{o.Name}).Body()
+		if o.Description != "" {
+			ob.SetAttributeValue("description", cty.StringVal(o.Description))
+		}
+		ob.SetAttributeTraversal("value", hcl.Traversal{
+			hcl.TraverseRoot{Name: "module"},
+			hcl.TraverseAttr{Name: moduleName},
+			hcl.TraverseAttr{Name: o.Name},
+		})
+		if o.Sensitive {
+			ob.SetAttributeValue("sensitive", cty.True)
+		}
+	}
+
+	versions := hclwrite.NewEmptyFile()
+	tfVersion := core.GetSpec().GetInfo().GetActuationTool().GetVersion()
+	providers := core.GetSpec().GetRequirements().GetProviderVersions()
+	if tfVersion != "" || len(providers) > 0 {
+		tf := versions.Body().AppendNewBlock("terraform", nil).Body()
+		if tfVersion != "" {
+			tf.SetAttributeValue("required_version", cty.StringVal(tfVersion))
+		}
+		if len(providers) > 0 {
+			rp := tf.AppendNewBlock("required_providers", nil).Body()
+			for _, p := range providers {
+				attrs := map[string]cty.Value{"source": cty.StringVal(p.Source)}
+				if p.Version != "" {
+					attrs["version"] = cty.StringVal(p.Version)
+				}
+				rp.SetAttributeValue(path.Base(p.Source), cty.ObjectVal(attrs))
+			}
+		}
+	}
+
+	files := []exampleFile{{name: "main.tf", file: main}}
+	for _, f := range []exampleFile{{"variables.tf", variables}, {"outputs.tf", outputs}, {"versions.tf", versions}} {
+		if len(f.file.Body().Blocks()) > 0 {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// exampleModuleName returns the label of the module block calling a blueprint.
+func exampleModuleName(name string) string {
+	name = strings.TrimPrefix(name, "terraform-google-")
+	if name == "" {
+		return "blueprint"
+	}
+	return strcase.ToSnake(name)
+}
+
+// exampleValue returns the value a required variable is set to in an example.
+func exampleValue(v *BlueprintVariable, dv *DisplayVariable) cty.Value {
+	t, ok := variableCtyType(v)
+	if !ok {
+		t = cty.DynamicPseudoType
+	}
+	for _, ad := range dv.GetAltDefaults() {
+		if val, ok := exampleAltDefault(ad, t); ok {
+			return val
+		}
+	}
+	if labels := dv.GetEnumValueLabels(); len(labels) > 0 && t == cty.String {
+		return cty.StringVal(labels[0].Value)
+	}
+	return placeholderValue(v.Name, t)
+}
+
+// exampleAltDefault converts an alternate default to a value of type t.
+func exampleAltDefault(ad *DisplayVariable_AlternateDefault, t cty.Type) (cty.Value, bool) {
+	if ad.GetValue() == nil {
+		return cty.NilVal, false
+	}
+	b, err := protojson.Marshal(ad.Value)
+	if err != nil {
+		return cty.NilVal, false
+	}
+	if t.HasDynamicTypes() {
+		if t, err = ctyjson.ImpliedType(b); err != nil {
+			return cty.NilVal, false
+		}
+	}
+	val, err := ctyjson.Unmarshal(b, t)
+	if err != nil {
+		return cty.NilVal, false
+	}
+	return val, true
+}
+
+// placeholderValue returns a placeholder of type t for variable name. Collections have a
+// single placeholder element and objects placeholders for all required attributes.
+func placeholderValue(name string, t cty.Type) cty.Value {
+	switch {
+	case t == cty.String || t == cty.DynamicPseudoType:
+		return cty.StringVal(strings.ReplaceAll(name, "_", "-"))
+	case t == cty.Number:
+		return cty.NumberIntVal(1)
+	case t == cty.Bool:
+		return cty.False
+	case t.IsListType():
+		return cty.ListVal([]cty.Value{placeholderValue(name, t.ElementType())})
+	case t.IsSetType():
+		return cty.SetVal([]cty.Value{placeholderValue(name, t.ElementType())})
+	case t.IsMapType():
+		return cty.MapVal(map[string]cty.Value{"key": placeholderValue(name, t.ElementType())})
+	case t.IsObjectType():
+		attrs := make(map[string]cty.Value)
+		for attr, at := range t.AttributeTypes() {
+			if !t.AttributeOptional(attr) {
+				attrs[attr] = placeholderValue(attr, at)
+			}
+		}
+		return cty.ObjectVal(attrs)
+	case t.IsTupleType():
+		var elems []cty.Value
+		for _, et := range t.TupleElementTypes() {
+			elems = append(elems, placeholderValue(name, et))
+		}
+		return cty.TupleVal(elems)
+	}
+	return cty.NullVal(t)
+}
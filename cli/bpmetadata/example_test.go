@@ -0,0 +1,168 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const wantExampleMain = `module "sample" {
+  source     = "../.."
+  project_id = var.project_id
+  region     = "us-central1"
+  tier       = "BASIC"
+  node_count = 1
+  labels = {
+    key = "labels"
+  }
+  network = {
+    name = "name"
+  }
+}
+`
+
+const wantExampleVariables = `variable "project_id" {
+  description = "The project ID to deploy to"
+  type        = string
+}
+`
+
+const wantExampleOutputs = `output "instance_name" {
+  description = "Name of the instance"
+  value       = module.sample.instance_name
+}
+
+output "password" {
+  value     = module.sample.password
+  sensitive = true
+}
+`
+
+const wantExampleVersions = `terraform {
+  required_version = ">= 1.3"
+  required_providers {
+    google = {
+      source  = "hashicorp/google"
+      version = ">= 4.0, < 6"
+    }
+  }
+}
+`
+
+func TestGenerateExample(t *testing.T) {
+	bpPath := t.TempDir()
+	core := &BlueprintMetadata{
+		ApiVersion: metadataApiVersion,
+		Kind:       metadataKind,
+		Metadata:   &ResourceTypeMeta{Name: "terraform-google-sample"},
+		Spec: &BlueprintMetadataSpec{
+			Info: &BlueprintInfo{
+				ActuationTool: &BlueprintActuationTool{Flavor: "Terraform", Version: ">= 1.3"},
+			},
+			Requirements: &BlueprintRequirements{
+				ProviderVersions: []*ProviderVersion{{Source: "hashicorp/google", Version: ">= 4.0, < 6"}},
+			},
+			Interfaces: &BlueprintInterface{
+				Variables: []*BlueprintVariable{
+					{Name: "project_id", Description: "The project ID to deploy to", VarType: "string", Required: true},
+					{Name: "region", VarType: "string", Required: true},
+					{Name: "tier", VarType: "string", Required: true},
+					{Name: "node_count", VarType: "number", Required: true},
+					{Name: "labels", VarType: "map(string)", Required: true},
+					{Name: "network", VarType: "object({\n    name   = string\n    subnet = optional(string)\n  })", Required: true},
+					{Name: "zone", VarType: "string", DefaultValue: structpb.NewStringValue("us-central1-a")},
+				},
+				Outputs: []*BlueprintOutput{
+					{Name: "instance_name", Description: "Name of the instance"},
+					{Name: "password", Sensitive: true},
+				},
+			},
+		},
+	}
+	display := &BlueprintMetadata{
+		ApiVersion: metadataApiVersion,
+		Kind:       metadataKind,
+		Metadata:   &ResourceTypeMeta{Name: "terraform-google-sample-display"},
+		Spec: &BlueprintMetadataSpec{
+			Ui: &BlueprintUI{
+				Input: &BlueprintUIInput{
+					Variables: map[string]*DisplayVariable{
+						"region": {Name: "region", AltDefaults: []*DisplayVariable_AlternateDefault{{Value: structpb.NewStringValue("us-central1")}}},
+						"tier":   {Name: "tier", EnumValueLabels: []*ValueLabel{{Label: "Basic", Value: "BASIC"}, {Label: "Standard", Value: "STANDARD"}}},
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, WriteMetadata(core, bpPath, metadataFileName))
+	require.NoError(t, WriteMetadata(display, bpPath, metadataDisplayFileName))
+
+	outPath := path.Join(bpPath, examplesPath, "simple")
+	var out bytes.Buffer
+	require.NoError(t, generateExample(bpPath, outPath, &out))
+	assert.Contains(t, out.String(), "main.tf")
+
+	for name, want := range map[string]string{
+		"main.tf":      wantExampleMain,
+		"variables.tf": wantExampleVariables,
+		"outputs.tf":   wantExampleOutputs,
+		"versions.tf":  wantExampleVersions,
+	} {
+		got, err := os.ReadFile(path.Join(outPath, name))
+		require.NoError(t, err)
+		assert.Equal(t, want, string(got), name)
+	}
+
+	// existing examples are not overwritten
+	err := generateExample(bpPath, outPath, &out)
+	assert.ErrorContains(t, err, "already exists")
+}
+
+func TestGenerateExampleKRM(t *testing.T) {
+	bpPath := t.TempDir()
+	md := &BlueprintMetadata{
+		ApiVersion: metadataApiVersion,
+		Kind:       metadataKind,
+		Metadata:   &ResourceTypeMeta{Name: "sample-krm"},
+		Spec: &BlueprintMetadataSpec{
+			Info: &BlueprintInfo{ActuationTool: &BlueprintActuationTool{Flavor: "KRM"}},
+		},
+	}
+	require.NoError(t, WriteMetadata(md, bpPath, metadataFileName))
+
+	err := generateExample(bpPath, path.Join(bpPath, examplesPath, "simple"), &bytes.Buffer{})
+	assert.ErrorContains(t, err, "only be generated for Terraform blueprints")
+}
+
+func TestPlaceholderValue(t *testing.T) {
+	tests := []struct {
+		name string
+		t    cty.Type
+		want cty.Value
+	}{
+		{name: "string", t: cty.String, want: cty.StringVal("bucket-name")},
+		{name: "dynamic", t: cty.DynamicPseudoType, want: cty.StringVal("bucket-name")},
+		{name: "number", t: cty.Number, want: cty.NumberIntVal(1)},
+		{name: "bool", t: cty.Bool, want: cty.False},
+		{name: "list", t: cty.List(cty.String), want: cty.ListVal([]cty.Value{cty.StringVal("bucket-name")})},
+		{name: "set", t: cty.Set(cty.Number), want: cty.SetVal([]cty.Value{cty.NumberIntVal(1)})},
+		{name: "tuple", t: cty.Tuple([]cty.Type{cty.String, cty.Bool}), want: cty.TupleVal([]cty.Value{cty.StringVal("bucket-name"), cty.False})},
+		{
+			name: "object",
+			t:    cty.ObjectWithOptionalAttrs(map[string]cty.Type{"storage_class": cty.String, "versioning": cty.Bool}, []string{"versioning"}),
+			want: cty.ObjectVal(map[string]cty.Value{"storage_class": cty.StringVal("storage-class")}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := placeholderValue("bucket_name", tt.t)
+			assert.True(t, tt.want.RawEquals(got), "got %#v, want %#v", got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+)
+
+// Options configures metadata generation for a blueprint.
+type Options struct {
+	// Path is the path to the root of the blueprint. Relative paths are
+	// resolved against the working directory.
+	Path string
+	// Nested generates metadata for sub-modules under modules/ as well.
+	Nested bool
+	// Force generates fresh metadata ignoring invalid metadata on disk.
+	Force bool
+	// Display generates the display metadata used for UI rendering as well.
+	Display bool
+	// Check compares generated metadata against metadata on disk without writing it.
+	Check bool
+	// Quiet disables prompting for a missing blueprint name or source URL.
+	Quiet bool
+	// Prompt asks for a missing blueprint name or source URL unless Quiet or
+	// Check is set. Values are not prompted for if Prompt is nil.
+	Prompt func(question string) (string, error)
+	// Name overrides the name of the blueprint. Sub-module names are derived from it.
+	Name string
+	// SourceURL overrides the URL of the repo hosting the blueprint.
+	SourceURL string
+	// PerModuleRequirements generates requirements for root and sub-modules separately.
+	PerModuleRequirements bool
+	// RequirementsSource is the source of requirements, one of "setup" (default)
+	// or "resources".
+	RequirementsSource string
+	// GenerateOutputType generates types of outputs.
+	GenerateOutputType bool
+	// OutputTypeSource is the source of output types, one of "state" (default)
+	// or "static".
+	OutputTypeSource string
+	// ProviderSchema is the path to a provider schema JSON used with the "static"
+	// output type source.
+	ProviderSchema string
+	// InferWidgets infers UI widgets for display variables.
+	InferWidgets bool
+	// InferQuotas proposes quota details and org policy checks
+	// from the resources of the blueprint unless they are authored.
+	InferQuotas bool
+}
+
+// Result is the outcome of generating metadata for a blueprint or one of its sub-modules.
+type Result struct {
+	// Path is the path to the blueprint or sub-module.
+	Path string
+	// Metadata is the generated metadata.
+	Metadata *BlueprintMetadata
+	// DisplayMetadata is the generated display metadata, if requested.
+	DisplayMetadata *BlueprintMetadata
+	// Stale is set in check mode if metadata on disk is out of date.
+	Stale bool
+	// Diff is a unified diff of metadata on disk to generated metadata in check mode.
+	Diff string
+	// Files are the statuses of metadata files written, or that would be written in
+	// check mode, keyed by file path.
+	Files map[string]FileStatus
+	// Warnings are issues found while generating metadata which did not fail generation.
+	Warnings []string
+	// Err is the error generating metadata, if any.
+	Err error
+}
+
+// FileStatus is the status of a generated metadata file.
+type FileStatus string
+
+const (
+	FileCreated   FileStatus = "created"
+	FileUpdated   FileStatus = "updated"
+	FileUnchanged FileStatus = "unchanged"
+)
+
+// generator holds the state of a single metadata generation run.
+type generator struct {
+	opts        Options
+	rootPath    string
+	repoDetails repoDetail
+	// warnings of the blueprint being generated
+	warnings []string
+}
+
+// Generate generates metadata for the blueprint at opts.Path and its sub-modules,
+// if opts.Nested is set. Metadata is written to disk unless opts.Check is set.
+// Results are returned for each blueprint path with a readme. Generate may be called
+// concurrently for different blueprints, though output types are generated from
+// Terraform state for one blueprint at a time.
+func Generate(opts Options) ([]Result, error) {
+	if opts.OutputTypeSource == "" {
+		opts.OutputTypeSource = outputTypeSourceState
+	}
+	if opts.OutputTypeSource != outputTypeSourceState && opts.OutputTypeSource != outputTypeSourceStatic {
+		return nil, fmt.Errorf("invalid output type source %s - one of %+q expected", opts.OutputTypeSource, []string{outputTypeSourceState, outputTypeSourceStatic})
+	}
+
+	if opts.RequirementsSource == "" {
+		opts.RequirementsSource = requirementsSourceSetup
+	}
+	if opts.RequirementsSource != requirementsSourceSetup && opts.RequirementsSource != requirementsSourceResources {
+		return nil, fmt.Errorf("invalid requirements source %s - one of %+q expected", opts.RequirementsSource, []string{requirementsSourceSetup, requirementsSourceResources})
+	}
+
+	currBpPath, err := filepath.Abs(opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving blueprint path: %w", err)
+	}
+
+	// throw an error and exit if root level readme.md doesn't exist
+	if _, err := os.Stat(path.Join(currBpPath, readmeFileName)); err != nil {
+		return nil, fmt.Errorf("top-level module does not have a readme: %w", err)
+	}
+
+	allBpPaths := []string{currBpPath}
+	var results []Result
+
+	// if nested, check if modules/ exists and create paths
+	// for submodules
+	if opts.Nested {
+		modulesPathforBp := path.Join(currBpPath, modulesPath)
+		_, err = os.Stat(modulesPathforBp)
+		if os.IsNotExist(err) {
+			Log.Info("sub-modules do not exist for this blueprint")
+		} else {
+			moduleDirs, err := util.WalkTerraformDirs(modulesPathforBp)
+			if err != nil {
+				results = append(results, Result{Path: modulesPathforBp, Err: err})
+			} else {
+				allBpPaths = append(allBpPaths, moduleDirs...)
+			}
+		}
+	}
+
+	g := &generator{opts: opts, rootPath: currBpPath}
+	for _, modPath := range allBpPaths {
+		// check if module path has readme.md
+		_, err := os.Stat(path.Join(modPath, readmeFileName))
+
+		// log info if a sub-module doesn't have a readme.md and continue
+		if err != nil {
+			Log.Info("skipping metadata for sub-module identified as an internal module", "Path:", modPath)
+			continue
+		}
+
+		results = append(results, g.generateMetadataForBpPath(modPath))
+	}
+
+	return results, nil
+}
+
+// warn records a warning for the blueprint being generated.
+func (g *generator) warn(format string, a ...any) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, a...))
+}
+
+// generateMetadataForBpPath generates metadata for the blueprint at bpPath and writes it to disk.
+// In check mode, metadata is only compared against the files on disk with any
+// differences and staleness recorded in the result.
+func (g *generator) generateMetadataForBpPath(bpPath string) Result {
+	g.warnings = nil
+	r := Result{Path: bpPath}
+	var diff bytes.Buffer
+	r.Err = g.generateMetadata(bpPath, &r, &diff)
+	r.Diff = diff.String()
+	r.Warnings = g.warnings
+	return r
+}
+
+func (g *generator) generateMetadata(bpPath string, r *Result, diff *bytes.Buffer) error {
+	//try to read existing metadata.yaml
+	bpObj, err := UnmarshalMetadata(bpPath, metadataFileName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) && !g.opts.Force {
+		return err
+	}
+
+	existing := existingMetadata(bpObj, g.opts.Force)
+	base, err := readMergeBase(bpPath, metadataFileName)
+	if err != nil {
+		return err
+	}
+
+	// create core metadata
+	bpMetaObj, err := g.createBlueprintMetadata(bpPath, bpObj)
+	if err != nil {
+		return fmt.Errorf("error creating metadata for blueprint at path: %s. Details: %w", bpPath, err)
+	}
+
+	// If the option is set, update output types of TF blueprints
+	if g.opts.GenerateOutputType && !isKptPackage(bpPath) {
+		if g.opts.OutputTypeSource == outputTypeSourceStatic {
+			err = updateOutputTypesStatic(bpPath, g.opts.ProviderSchema, bpMetaObj.Spec.Interfaces)
+		} else {
+			err = updateOutputTypes(bpPath, bpMetaObj.Spec.Interfaces)
+		}
+		if err != nil {
+			return fmt.Errorf("error updating output types: %w", err)
+		}
+	}
+
+	nextBase, conflicts := mergeGeneratedMetadata(existing, bpMetaObj, base)
+	g.warnMergeConflicts(metadataFileName, conflicts)
+	r.Metadata = bpMetaObj
+
+	if g.opts.Check {
+		stale, err := g.checkMetadata(r, bpMetaObj, bpPath, metadataFileName, diff)
+		if err != nil {
+			return fmt.Errorf("error checking metadata for blueprint at path: %s. Details: %w", bpPath, err)
+		}
+		r.Stale = stale
+	} else {
+		// write core metadata to disk
+		err = g.writeMetadata(r, bpMetaObj, bpPath, metadataFileName, nextBase)
+		if err != nil {
+			return fmt.Errorf("error writing metadata to disk for blueprint at path: %s. Details: %w", bpPath, err)
+		}
+	}
+
+	// continue with creating display metadata if the option is set
+	if !g.opts.Display {
+		return nil
+	}
+
+	bpDpObj, err := UnmarshalMetadata(bpPath, metadataDisplayFileName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) && !g.opts.Force {
+		return err
+	}
+
+	existingDp := existingMetadata(bpDpObj, g.opts.Force)
+	baseDp, err := readMergeBase(bpPath, metadataDisplayFileName)
+	if err != nil {
+		return err
+	}
+
+	// create display metadata
+	bpMetaDpObj, err := g.createBlueprintDisplayMetadata(bpPath, bpDpObj, bpMetaObj)
+	if err != nil {
+		return fmt.Errorf("error creating display metadata for blueprint at path: %s. Details: %w", bpPath, err)
+	}
+
+	nextBaseDp, conflicts := mergeGeneratedMetadata(existingDp, bpMetaDpObj, baseDp)
+	g.warnMergeConflicts(metadataDisplayFileName, conflicts)
+	r.DisplayMetadata = bpMetaDpObj
+
+	if g.opts.Check {
+		displayStale, err := g.checkMetadata(r, bpMetaDpObj, bpPath, metadataDisplayFileName, diff)
+		if err != nil {
+			return fmt.Errorf("error checking display metadata for blueprint at path: %s. Details: %w", bpPath, err)
+		}
+		r.Stale = r.Stale || displayStale
+		return nil
+	}
+
+	// write display metadata to disk
+	err = g.writeMetadata(r, bpMetaDpObj, bpPath, metadataDisplayFileName, nextBaseDp)
+	if err != nil {
+		return fmt.Errorf("error writing display metadata to disk for blueprint at path: %s. Details: %w", bpPath, err)
+	}
+
+	return nil
+}
+
+// checkMetadata compares generated metadata against fileName on disk recording
+// the file status that writing it would result in.
+func (g *generator) checkMetadata(r *Result, obj *BlueprintMetadata, bpPath, fileName string, diff *bytes.Buffer) (bool, error) {
+	_, statErr := os.Stat(path.Join(bpPath, fileName))
+	stale, err := checkMetadata(obj, bpPath, fileName, diff)
+	if err != nil {
+		return false, err
+	}
+
+	status := FileUnchanged
+	switch {
+	case errors.Is(statErr, os.ErrNotExist):
+		status = FileCreated
+	case stale:
+		status = FileUpdated
+	}
+	r.setFileStatus(path.Join(bpPath, fileName), status)
+	return stale, nil
+}
+
+// writeMetadata writes metadata to fileName on disk along with its merge base
+// recording the file status.
+func (g *generator) writeMetadata(r *Result, obj *BlueprintMetadata, bpPath, fileName string, base map[string]string) error {
+	b, err := marshalMetadata(obj)
+	if err != nil {
+		return err
+	}
+
+	filePath := path.Join(bpPath, fileName)
+	status := FileUpdated
+	existing, err := os.ReadFile(filePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		status = FileCreated
+	case err == nil && bytes.Equal(existing, b):
+		status = FileUnchanged
+	}
+
+	if err := os.WriteFile(filePath, b, 0644); err != nil {
+		return err
+	}
+	if err := writeMergeBase(bpPath, fileName, base); err != nil {
+		return err
+	}
+	r.setFileStatus(filePath, status)
+	return nil
+}
+
+func (r *Result) setFileStatus(filePath string, status FileStatus) {
+	if r.Files == nil {
+		r.Files = make(map[string]FileStatus)
+	}
+	r.Files[filePath] = status
+}
+
+// warnMergeConflicts records conflicts of merging generated metadata with fileName as warnings.
+func (g *generator) warnMergeConflicts(fileName string, conflicts []mergeConflict) {
+	for _, c := range conflicts {
+		g.warn("%s: merge conflict for %s: %s", fileName, c.Path, c.Message)
+	}
+}
+
+// resolveRepoDetails sets repo details for the blueprint at bpPath applying
+// overrides from options and prompting for missing values.
+func (g *generator) resolveRepoDetails(bpPath string, readmeContent []byte) {
+	getRepoDetailsByPath(bpPath, &g.repoDetails, readmeContent)
+
+	if g.opts.SourceURL != "" {
+		g.repoDetails.Source.URL = g.opts.SourceURL
+		g.repoDetails.Source.SourceType = "git"
+	}
+
+	if g.opts.Name != "" {
+		if path.Clean(bpPath) == g.rootPath || g.rootPath == "" {
+			g.repoDetails.ModuleName = g.opts.Name
+		} else {
+			g.repoDetails.ModuleName = g.opts.Name + "-" + getBpSubmoduleNameInKebabCase(bpPath)
+		}
+		g.repoDetails.RepoName = g.opts.Name
+	}
+
+	if g.opts.Quiet || g.opts.Check || g.opts.Prompt == nil {
+		return
+	}
+
+	if g.repoDetails.ModuleName == "" {
+		name, err := g.opts.Prompt(fmt.Sprintf("Provide a name for the blueprint at path [%s]", bpPath))
+		if err != nil {
+			g.warn("unable to read the name for the blueprint: %v", err)
+		}
+		g.repoDetails.ModuleName = name
+	}
+
+	if g.repoDetails.Source.URL == "" {
+		url, err := g.opts.Prompt(fmt.Sprintf("Provide a URL for the blueprint source at path [%s]", bpPath))
+		if err != nil {
+			g.warn("unable to read the URL for the blueprint: %v", err)
+		}
+		g.repoDetails.Source.URL = url
+	}
+}
@@ -0,0 +1,196 @@
+package bpmetadata
+
+import (
+	"errors"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/otiai10/copy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBlueprint creates a blueprint from the sample module with a readme
+// and a sub-module in a temp dir.
+func newTestBlueprint(t *testing.T) string {
+	t.Helper()
+	bpPath := t.TempDir()
+	subPath := path.Join(bpPath, "modules", "sub")
+	for _, p := range []string{bpPath, subPath} {
+		require.NoError(t, copy.Copy(path.Join(tfTestdataPath, "sample-module", "variables.tf"), path.Join(p, "variables.tf")))
+		require.NoError(t, copy.Copy(path.Join(tfTestdataPath, "sample-module", "outputs.tf"), path.Join(p, "outputs.tf")))
+		require.NoError(t, os.WriteFile(path.Join(p, readmeFileName), []byte("# Sample\n"), 0644))
+	}
+	return bpPath
+}
+
+func TestGenerate(t *testing.T) {
+	bpPath := newTestBlueprint(t)
+	prompted := false
+	opts := Options{
+		Path:      bpPath,
+		Nested:    true,
+		Display:   true,
+		Name:      "terraform-google-sample",
+		SourceURL: "https://github.com/GoogleCloudPlatform/terraform-google-sample",
+		Prompt: func(string) (string, error) {
+			prompted = true
+			return "", nil
+		},
+	}
+
+	results, err := Generate(opts)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.False(t, prompted)
+
+	for _, r := range results {
+		require.NoError(t, r.Err)
+		assert.NotNil(t, r.DisplayMetadata)
+		onDisk, err := UnmarshalMetadata(r.Path, metadataFileName)
+		require.NoError(t, err)
+		assert.Equal(t, r.Metadata.Metadata.Name, onDisk.Metadata.Name)
+		assert.Equal(t, opts.SourceURL, onDisk.Spec.Info.Source.Repo)
+	}
+	assert.Equal(t, bpPath, results[0].Path)
+	assert.Equal(t, "terraform-google-sample", results[0].Metadata.Metadata.Name)
+	assert.Equal(t, "terraform-google-sample-sub", results[1].Metadata.Metadata.Name)
+
+	// metadata is up to date after generation
+	opts.Check = true
+	results, err = Generate(opts)
+	require.NoError(t, err)
+	for _, r := range results {
+		assert.NoError(t, r.Err)
+		assert.False(t, r.Stale)
+		assert.Empty(t, r.Diff)
+	}
+
+	// changes are reported as stale in check mode without writing metadata
+	require.NoError(t, os.WriteFile(path.Join(bpPath, readmeFileName), []byte("# Changed\n"), 0644))
+	results, err = Generate(opts)
+	require.NoError(t, err)
+	assert.True(t, results[0].Stale)
+	assert.Contains(t, results[0].Diff, "+    title: Changed")
+	onDisk, err := UnmarshalMetadata(bpPath, metadataFileName)
+	require.NoError(t, err)
+	assert.Equal(t, "Sample", onDisk.Spec.Info.Title)
+}
+
+func TestGenerateKeepsHandEdits(t *testing.T) {
+	bpPath := newTestBlueprint(t)
+	opts := Options{Path: bpPath, Quiet: true, Name: "terraform-google-sample"}
+	regenerate := func() Result {
+		t.Helper()
+		results, err := Generate(opts)
+		require.NoError(t, err)
+		require.NoError(t, results[0].Err)
+		return results[0]
+	}
+	edit := func(fn func(*BlueprintMetadata)) {
+		t.Helper()
+		md, err := UnmarshalMetadata(bpPath, metadataFileName)
+		require.NoError(t, err)
+		fn(md)
+		require.NoError(t, WriteMetadata(md, bpPath, metadataFileName))
+	}
+
+	regenerate()
+	assert.FileExists(t, path.Join(bpPath, mergeBaseFileName(metadataFileName)))
+
+	// edits are kept without conflicts when the generated values are unchanged
+	edit(func(md *BlueprintMetadata) { md.Spec.Info.Title = "Edited" })
+	r := regenerate()
+	assert.Empty(t, r.Warnings)
+	assert.Equal(t, "Edited", r.Metadata.Spec.Info.Title)
+
+	// edits of metadata without a merge base are kept and reported
+	require.NoError(t, os.Remove(path.Join(bpPath, mergeBaseFileName(metadataFileName))))
+	edit(func(md *BlueprintMetadata) { md.Spec.Interfaces.Variables[0].Description = "Edited description" })
+	r = regenerate()
+	assert.Equal(t, "Edited", r.Metadata.Spec.Info.Title)
+	assert.Equal(t, "Edited description", r.Metadata.Spec.Interfaces.Variables[0].Description)
+	assert.Len(t, r.Warnings, 2)
+	onDisk, err := UnmarshalMetadata(bpPath, metadataFileName)
+	require.NoError(t, err)
+	assert.Equal(t, "Edited", onDisk.Spec.Info.Title)
+	assert.Equal(t, "Edited description", onDisk.Spec.Interfaces.Variables[0].Description)
+}
+
+func TestGeneratePrompt(t *testing.T) {
+	bpPath := newTestBlueprint(t)
+	var questions []string
+	opts := Options{
+		Path: bpPath,
+		Prompt: func(question string) (string, error) {
+			questions = append(questions, question)
+			return "", errors.New("no input")
+		},
+	}
+
+	results, err := Generate(opts)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, []string{"Provide a URL for the blueprint source at path [" + bpPath + "]"}, questions)
+	assert.Equal(t, []string{"unable to read the URL for the blueprint: no input"}, results[0].Warnings)
+
+	// values are not prompted for in quiet mode
+	questions = nil
+	opts.Quiet = true
+	results, err = Generate(opts)
+	require.NoError(t, err)
+	assert.Empty(t, results[0].Warnings)
+	assert.Empty(t, questions)
+}
+
+func TestGenerateConcurrent(t *testing.T) {
+	const n = 4
+	bpPaths := make([]string, n)
+	for i := range bpPaths {
+		bpPaths[i] = newTestBlueprint(t)
+	}
+
+	var wg sync.WaitGroup
+	names := make([]string, n)
+	errs := make([]error, n)
+	for i, bpPath := range bpPaths {
+		wg.Add(1)
+		go func(i int, bpPath string) {
+			defer wg.Done()
+			results, err := Generate(Options{Path: bpPath, Nested: true, Quiet: true, Name: "bp-" + string(rune('a'+i))})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			errs[i] = results[len(results)-1].Err
+			names[i] = results[len(results)-1].Metadata.Metadata.Name
+		}(i, bpPath)
+	}
+	wg.Wait()
+
+	for i := range bpPaths {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, "bp-"+string(rune('a'+i))+"-sub", names[i])
+	}
+}
+
+func TestGenerateInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{name: "output type source", opts: Options{Path: ".", OutputTypeSource: "foo"}},
+		{name: "requirements source", opts: Options{Path: ".", RequirementsSource: "foo"}},
+		{name: "missing readme", opts: Options{Path: t.TempDir()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(tt.opts)
+			assert.Error(t, err)
+		})
+	}
+}
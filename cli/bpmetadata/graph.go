@@ -0,0 +1,427 @@
+package bpmetadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+const (
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+)
+
+// graphBlueprint is a blueprint node in the connection graph.
+type graphBlueprint struct {
+	// id is the normalized module source of the blueprint
+	id       string
+	path     string
+	metadata *BlueprintMetadata
+}
+
+func (b *graphBlueprint) label() string {
+	if title := b.metadata.GetSpec().GetInfo().GetTitle(); title != "" {
+		return title
+	}
+	if name := b.metadata.GetMetadata().GetName(); name != "" {
+		return name
+	}
+	return b.id
+}
+
+// graphConnection is an edge from a source blueprint output to a variable.
+type graphConnection struct {
+	// from is the id of the source blueprint which is the normalized
+	// connection source if the source is not in the workspace
+	from       string
+	to         string
+	variable   string
+	outputExpr string
+	// issue describes why the connection is invalid, if it is
+	issue string
+	// external is set if the source blueprint is not in the workspace,
+	// which makes the connection dangling
+	external bool
+	// note describes a dangling connection to a remote source which is not an issue
+	note string
+}
+
+func (c *graphConnection) label() string {
+	return fmt.Sprintf("%s → %s", c.outputExpr, c.variable)
+}
+
+// connectionGraph is the graph of blueprint connections across a workspace.
+type connectionGraph struct {
+	blueprints  []*graphBlueprint
+	connections []*graphConnection
+}
+
+// issues returns descriptions of invalid connections.
+func (g *connectionGraph) issues() []string {
+	var issues []string
+	for _, c := range g.connections {
+		if c.issue != "" {
+			issues = append(issues, c.issue)
+		}
+	}
+	return issues
+}
+
+// notes returns descriptions of dangling connections to remote sources.
+func (g *connectionGraph) notes() []string {
+	var notes []string
+	for _, c := range g.connections {
+		if c.note != "" {
+			notes = append(notes, c.note)
+		}
+	}
+	return notes
+}
+
+// loadWorkspaceBlueprints loads metadata for all blueprints within dirs.
+// Blueprints within overlapping dirs are loaded once.
+func loadWorkspaceBlueprints(dirs []string) ([]*graphBlueprint, error) {
+	var blueprints []*graphBlueprint
+	seen := make(map[string]string)
+	loaded := make(map[string]bool)
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if p != dir && (strings.HasPrefix(d.Name(), ".terraform") || d.Name() == ".git") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Name() != metadataFileName {
+				return nil
+			}
+			bpPath := filepath.Dir(p)
+			absPath, err := filepath.Abs(bpPath)
+			if err != nil {
+				return err
+			}
+			if loaded[absPath] {
+				return nil
+			}
+			loaded[absPath] = true
+			md, err := UnmarshalMetadata(bpPath, metadataFileName)
+			if err != nil {
+				return fmt.Errorf("error loading metadata from %s: %w", bpPath, err)
+			}
+			id := blueprintSourceID(md)
+			if id == "" {
+				Log.Info("skipping blueprint without source repo", "path", bpPath)
+				return nil
+			}
+			if prev, ok := seen[id]; ok {
+				return fmt.Errorf("blueprints at %s and %s have the same source %s", prev, bpPath, id)
+			}
+			seen[id] = bpPath
+			blueprints = append(blueprints, &graphBlueprint{id: id, path: bpPath, metadata: md})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(blueprints, func(i, j int) bool { return blueprints[i].id < blueprints[j].id })
+	return blueprints, nil
+}
+
+// blueprintSourceID returns the normalized module source of a blueprint from its repo details.
+func blueprintSourceID(md *BlueprintMetadata) string {
+	src := md.GetSpec().GetInfo().GetSource()
+	if src.GetRepo() == "" {
+		return ""
+	}
+	source := src.GetRepo()
+	if dir := strings.Trim(src.GetDir(), "/"); dir != "" {
+		source += "//" + dir
+	}
+	return util.NormalizeModuleSource(source)
+}
+
+// buildConnectionGraph resolves connections of all blueprints against the outputs
+// of their source blueprints. Connections to sources that are not in the workspace
+// are dangling, which is only an issue for local sources.
+func buildConnectionGraph(blueprints []*graphBlueprint) *connectionGraph {
+	g := &connectionGraph{blueprints: blueprints}
+	byID := make(map[string]*graphBlueprint)
+	byPath := make(map[string]*graphBlueprint)
+	for _, b := range blueprints {
+		byID[b.id] = b
+		byPath[filepath.Clean(b.path)] = b
+	}
+	for _, b := range blueprints {
+		for _, v := range b.metadata.GetSpec().GetInterfaces().GetVariables() {
+			for _, conn := range v.Connections {
+				c := &graphConnection{
+					from:       connectionSourceID(b, conn.GetSource().GetSource()),
+					to:         b.id,
+					variable:   v.Name,
+					outputExpr: conn.GetSpec().GetOutputExpr(),
+				}
+				if inputPath := conn.GetSpec().GetInputPath(); inputPath != "" {
+					c.variable += "." + inputPath
+				}
+				src, ok := byID[c.from]
+				if !ok {
+					src, ok = byPath[c.from]
+				}
+				switch {
+				case !ok && util.IsLocalModuleSource(conn.GetSource().GetSource()):
+					c.external = true
+					c.issue = fmt.Sprintf("%s: variable %s connection source %s not found in workspace", b.path, v.Name, conn.GetSource().GetSource())
+				case !ok:
+					c.external = true
+					c.note = fmt.Sprintf("%s: variable %s connection source %s is not in the workspace and was not checked", b.path, v.Name, conn.GetSource().GetSource())
+				default:
+					c.from = src.id
+					c.issue = checkConnection(src, b, v, conn)
+				}
+				g.connections = append(g.connections, c)
+			}
+		}
+	}
+	return g
+}
+
+// connectionSourceID returns the id of the source blueprint of a connection of b.
+// Relative sources are resolved against b, within its repo if possible and otherwise
+// to the cleaned path of the source directory.
+func connectionSourceID(b *graphBlueprint, source string) string {
+	if !util.IsLocalModuleSource(source) {
+		return util.NormalizeModuleSource(source)
+	}
+	base, subDir, _ := strings.Cut(b.id, "//")
+	rel := path.Clean(path.Join(subDir, filepath.ToSlash(source)))
+	switch {
+	case rel == ".":
+		return base
+	case rel != ".." && !strings.HasPrefix(rel, "../"):
+		return util.NormalizeModuleSource(base + "//" + rel)
+	}
+	return filepath.Clean(filepath.Join(b.path, source))
+}
+
+// checkConnection validates a connection from src to variable v of blueprint b
+// and returns a description of the issue, if any.
+func checkConnection(src, b *graphBlueprint, v *BlueprintVariable, conn *BlueprintConnection) string {
+	prefix := fmt.Sprintf("%s: variable %s connection to %s", b.path, v.Name, src.path)
+
+	if constraint := conn.GetSource().GetVersion(); constraint != "" {
+		if srcVersion := src.metadata.GetSpec().GetInfo().GetVersion(); srcVersion != "" {
+			c, err := version.NewConstraint(constraint)
+			if err != nil {
+				return fmt.Sprintf("%s has invalid version constraint %s: %v", prefix, constraint, err)
+			}
+			sv, err := version.NewVersion(srcVersion)
+			if err == nil && !c.Check(sv) {
+				return fmt.Sprintf("%s requires version %s but the source blueprint is version %s", prefix, constraint, srcVersion)
+			}
+		}
+	}
+
+	traversal, diags := hclsyntax.ParseTraversalAbs([]byte(conn.GetSpec().GetOutputExpr()), "", hcl.InitialPos)
+	if diags.HasErrors() || len(traversal) == 0 {
+		return fmt.Sprintf("%s has invalid output expression %q", prefix, conn.GetSpec().GetOutputExpr())
+	}
+	var output *BlueprintOutput
+	for _, o := range src.metadata.GetSpec().GetInterfaces().GetOutputs() {
+		if o.Name == traversal.RootName() {
+			output = o
+		}
+	}
+	if output == nil {
+		return fmt.Sprintf("%s references missing output %s", prefix, traversal.RootName())
+	}
+
+	outputType, ok := outputCtyType(output)
+	if !ok {
+		return ""
+	}
+	outputType = applyTraversal(outputType, traversal[1:])
+	varType, ok := variableCtyType(v)
+	if !ok {
+		return ""
+	}
+	if inputPath := conn.GetSpec().GetInputPath(); inputPath != "" {
+		for _, attr := range strings.Split(inputPath, ".") {
+			varType = traverseKey(varType, attr)
+		}
+	}
+	if outputType == cty.DynamicPseudoType || varType == cty.DynamicPseudoType {
+		return ""
+	}
+	if !outputType.Equals(varType) && convert.GetConversion(outputType, varType) == nil {
+		return fmt.Sprintf("%s has incompatible types: output %s is %s but variable expects %s", prefix, conn.GetSpec().GetOutputExpr(), outputType.FriendlyName(), varType.FriendlyName())
+	}
+	return ""
+}
+
+// outputCtyType returns the type of an output from its serialized type, if set.
+func outputCtyType(o *BlueprintOutput) (cty.Type, bool) {
+	if o.Type == nil {
+		return cty.NilType, false
+	}
+	b, err := json.Marshal(o.Type.AsInterface())
+	if err != nil {
+		return cty.NilType, false
+	}
+	t, err := ctyjson.UnmarshalType(b)
+	if err != nil {
+		return cty.NilType, false
+	}
+	return t, true
+}
+
+// variableCtyType returns the type of a variable from its type constraint, if set.
+func variableCtyType(v *BlueprintVariable) (cty.Type, bool) {
+	if strings.TrimSpace(v.VarType) == "" {
+		return cty.NilType, false
+	}
+	expr, diags := hclsyntax.ParseExpression([]byte(v.VarType), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilType, false
+	}
+	t, _, diags := typeexpr.TypeConstraintWithDefaults(expr)
+	if diags.HasErrors() {
+		return cty.NilType, false
+	}
+	return t, true
+}
+
+// writeGraph renders the connection graph to w in the provided format.
+func writeGraph(g *connectionGraph, format string, w io.Writer) error {
+	switch format {
+	case graphFormatDOT:
+		writeGraphDOT(g, w)
+		return nil
+	case graphFormatMermaid:
+		writeGraphMermaid(g, w)
+		return nil
+	}
+	return fmt.Errorf("invalid format %s - one of %+q expected", format, []string{graphFormatDOT, graphFormatMermaid})
+}
+
+func writeGraphDOT(g *connectionGraph, w io.Writer) {
+	fmt.Fprintln(w, "digraph blueprints {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	for _, b := range g.blueprints {
+		fmt.Fprintf(w, "  %q [label=%q];\n", b.id, b.label())
+	}
+	for _, id := range g.externalSources() {
+		fmt.Fprintf(w, "  %q [label=%q, style=dashed];\n", id, id)
+	}
+	for _, c := range g.connections {
+		attrs := fmt.Sprintf("label=%q", c.label())
+		if c.external {
+			attrs += ", style=dashed"
+		}
+		if c.issue != "" {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(w, "  %q -> %q [%s];\n", c.from, c.to, attrs)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func writeGraphMermaid(g *connectionGraph, w io.Writer) {
+	ids := make(map[string]string)
+	nodeID := func(id string) string {
+		if _, ok := ids[id]; !ok {
+			ids[id] = fmt.Sprintf("n%d", len(ids))
+		}
+		return ids[id]
+	}
+
+	fmt.Fprintln(w, "graph LR")
+	for _, b := range g.blueprints {
+		fmt.Fprintf(w, "  %s[%s]\n", nodeID(b.id), mermaidText(b.label()))
+	}
+	for _, id := range g.externalSources() {
+		fmt.Fprintf(w, "  %s([%s])\n", nodeID(id), mermaidText(id))
+	}
+	var invalid []int
+	for i, c := range g.connections {
+		arrow := "-->"
+		if c.external {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(w, "  %s %s|%s| %s\n", nodeID(c.from), arrow, mermaidText(c.label()), nodeID(c.to))
+		if c.issue != "" {
+			invalid = append(invalid, i)
+		}
+	}
+	for _, i := range invalid {
+		fmt.Fprintf(w, "  linkStyle %d stroke:red\n", i)
+	}
+}
+
+// mermaidText quotes text for use as a mermaid node or edge label.
+func mermaidText(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// externalSources returns sorted connection sources that are not in the workspace.
+func (g *connectionGraph) externalSources() []string {
+	seen := make(map[string]bool)
+	for _, c := range g.connections {
+		if c.external {
+			seen[c.from] = true
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// writeGraphIssues writes notes on dangling connections and graph issues to w and
+// returns an error if there are any issues.
+func writeGraphIssues(g *connectionGraph, w io.Writer) error {
+	for _, note := range g.notes() {
+		fmt.Fprintln(w, note)
+	}
+	issues := g.issues()
+	for _, issue := range issues {
+		fmt.Fprintln(w, issue)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("found %d invalid blueprint connections", len(issues))
+	}
+	return nil
+}
+
+// generateGraph loads blueprints within dirs and renders their connection graph.
+func generateGraph(dirs []string, format string, out, errOut io.Writer) error {
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); err != nil {
+			return err
+		}
+	}
+	blueprints, err := loadWorkspaceBlueprints(dirs)
+	if err != nil {
+		return err
+	}
+	g := buildConnectionGraph(blueprints)
+	if err := writeGraph(g, format, out); err != nil {
+		return err
+	}
+	return writeGraphIssues(g, errOut)
+}
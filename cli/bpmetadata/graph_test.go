@@ -0,0 +1,273 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/otiai10/copy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const graphTestdataPath = "../testdata/bpmetadata/graph"
+
+func TestBuildConnectionGraph(t *testing.T) {
+	blueprints, err := loadWorkspaceBlueprints([]string{graphTestdataPath})
+	require.NoError(t, err)
+
+	var ids []string
+	for _, b := range blueprints {
+		ids = append(ids, b.id)
+	}
+	assert.Equal(t, []string{
+		"github.com/example/terraform-google-app",
+		"github.com/example/terraform-google-app//modules/db",
+		"github.com/example/terraform-google-network",
+	}, ids)
+
+	g := buildConnectionGraph(blueprints)
+	assert.Len(t, g.connections, 7)
+	assert.Equal(t, []string{
+		"github.com/example/terraform-google-app//modules/cache",
+		"github.com/example/terraform-google-project",
+	}, g.externalSources())
+	appPath, dbPath, networkPath := path.Join(graphTestdataPath, "app"), path.Join(graphTestdataPath, "app/modules/db"), path.Join(graphTestdataPath, "network")
+	assert.Equal(t, []string{
+		appPath + ": variable subnet connection to " + networkPath + " has incompatible types: output subnets is list of string but variable expects string",
+		appPath + ": variable region connection to " + networkPath + " references missing output region",
+		dbPath + ": variable network connection to " + networkPath + " requires version ~> 1.0 but the source blueprint is version 2.1.0",
+		dbPath + ": variable cache_host connection source ../cache not found in workspace",
+	}, g.issues())
+	assert.Equal(t, []string{
+		appPath + ": variable project_id connection source github.com/example/terraform-google-project is not in the workspace and was not checked",
+	}, g.notes())
+
+	// relative sources are resolved against the consuming blueprint
+	var local *graphConnection
+	for _, c := range g.connections {
+		if c.variable == "app_name" {
+			local = c
+		}
+	}
+	require.NotNil(t, local)
+	assert.Equal(t, "github.com/example/terraform-google-app", local.from)
+	assert.False(t, local.external)
+	assert.Empty(t, local.issue)
+}
+
+func TestConnectionSourceID(t *testing.T) {
+	b := &graphBlueprint{id: "github.com/example/terraform-google-app//modules/db", path: path.Join("repos", "app", "modules", "db")}
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: "../..", want: "github.com/example/terraform-google-app"},
+		{source: "../cache", want: "github.com/example/terraform-google-app//modules/cache"},
+		{source: "./sub", want: "github.com/example/terraform-google-app//modules/db/sub"},
+		{source: "../../../network", want: path.Join("repos", "network")},
+		{source: "example/network/google", want: "github.com/example/terraform-google-network"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			assert.Equal(t, tt.want, connectionSourceID(b, tt.source))
+		})
+	}
+}
+
+func TestLoadWorkspaceBlueprintsOverlappingDirs(t *testing.T) {
+	networkPath := path.Join(graphTestdataPath, "network")
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	blueprints, err := loadWorkspaceBlueprints([]string{graphTestdataPath, networkPath, path.Join(wd, networkPath)})
+	require.NoError(t, err)
+	assert.Len(t, blueprints, 3)
+
+	// copies of a blueprint have the same source
+	copyPath := path.Join(t.TempDir(), "network")
+	require.NoError(t, copy.Copy(networkPath, copyPath))
+	_, err = loadWorkspaceBlueprints([]string{networkPath, copyPath})
+	assert.ErrorContains(t, err, "have the same source github.com/example/terraform-google-network")
+}
+
+func TestWriteGraphIssues(t *testing.T) {
+	g := &connectionGraph{connections: []*graphConnection{
+		{note: "remote"},
+		{issue: "missing"},
+	}}
+	var b bytes.Buffer
+	assert.EqualError(t, writeGraphIssues(g, &b), "found 1 invalid blueprint connections")
+	assert.Equal(t, "remote\nmissing\n", b.String())
+
+	b.Reset()
+	assert.NoError(t, writeGraphIssues(&connectionGraph{connections: g.connections[:1]}, &b))
+	assert.Equal(t, "remote\n", b.String())
+}
+
+func TestCheckConnection(t *testing.T) {
+	objectType, err := structpb.NewValue([]any{"object", map[string]any{"id": "string", "count": "number"}})
+	require.NoError(t, err)
+	src := &graphBlueprint{
+		path: "src",
+		metadata: &BlueprintMetadata{Spec: &BlueprintMetadataSpec{
+			Info: &BlueprintInfo{Version: "1.2.0"},
+			Interfaces: &BlueprintInterface{Outputs: []*BlueprintOutput{
+				{Name: "obj", Type: objectType},
+				{Name: "untyped"},
+			}},
+		}},
+	}
+	dst := &graphBlueprint{path: "dst"}
+	inputPath := "id"
+
+	tests := []struct {
+		name    string
+		varType string
+		conn    *BlueprintConnection
+		want    string
+	}{
+		{
+			name:    "attribute",
+			varType: "string",
+			conn:    &BlueprintConnection{Source: &ConnectionSource{Version: "~> 1.0"}, Spec: &ConnectionSpec{OutputExpr: "obj.id"}},
+		},
+		{
+			name:    "safe conversion",
+			varType: "string",
+			conn:    &BlueprintConnection{Spec: &ConnectionSpec{OutputExpr: "obj.count"}},
+		},
+		{
+			name:    "input path",
+			varType: "object({id = string})",
+			conn:    &BlueprintConnection{Spec: &ConnectionSpec{OutputExpr: "obj.id", InputPath: &inputPath}},
+		},
+		{
+			name:    "whole object",
+			varType: "object({id = string, count = number})",
+			conn:    &BlueprintConnection{Spec: &ConnectionSpec{OutputExpr: "obj"}},
+		},
+		{
+			name:    "untyped output",
+			varType: "number",
+			conn:    &BlueprintConnection{Spec: &ConnectionSpec{OutputExpr: "untyped"}},
+		},
+		{
+			name: "untyped variable",
+			conn: &BlueprintConnection{Spec: &ConnectionSpec{OutputExpr: "obj"}},
+		},
+		{
+			name:    "incompatible",
+			varType: "list(string)",
+			conn:    &BlueprintConnection{Spec: &ConnectionSpec{OutputExpr: "obj.id"}},
+			want:    "dst: variable v connection to src has incompatible types: output obj.id is string but variable expects list of string",
+		},
+		{
+			name:    "invalid output expression",
+			varType: "string",
+			conn:    &BlueprintConnection{Spec: &ConnectionSpec{OutputExpr: "obj["}},
+			want:    `dst: variable v connection to src has invalid output expression "obj["`,
+		},
+		{
+			name:    "invalid version constraint",
+			varType: "string",
+			conn:    &BlueprintConnection{Source: &ConnectionSource{Version: "latest"}, Spec: &ConnectionSpec{OutputExpr: "obj.id"}},
+			want:    "dst: variable v connection to src has invalid version constraint latest: malformed constraint: latest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &BlueprintVariable{Name: "v", VarType: tt.varType}
+			assert.Equal(t, tt.want, checkConnection(src, dst, v, tt.conn))
+		})
+	}
+}
+
+func TestWriteGraph(t *testing.T) {
+	g := &connectionGraph{
+		blueprints: []*graphBlueprint{
+			{id: "github.com/example/a", metadata: &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Info: &BlueprintInfo{Title: "A"}}}},
+			{id: "github.com/example/b", metadata: &BlueprintMetadata{Metadata: &ResourceTypeMeta{Name: "b"}}},
+		},
+		connections: []*graphConnection{
+			{from: "github.com/example/a", to: "github.com/example/b", variable: "network", outputExpr: "network.id"},
+			{from: "github.com/example/a", to: "github.com/example/b", variable: "region", outputExpr: "region", issue: "missing"},
+			{from: "github.com/example/c", to: "github.com/example/b", variable: "project_id", outputExpr: "project_id", external: true, issue: "dangling"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "dot",
+			format: graphFormatDOT,
+			want: `digraph blueprints {
+  rankdir=LR;
+  "github.com/example/a" [label="A"];
+  "github.com/example/b" [label="b"];
+  "github.com/example/c" [label="github.com/example/c", style=dashed];
+  "github.com/example/a" -> "github.com/example/b" [label="network.id → network"];
+  "github.com/example/a" -> "github.com/example/b" [label="region → region", color=red];
+  "github.com/example/c" -> "github.com/example/b" [label="project_id → project_id", style=dashed, color=red];
+}
+`,
+		},
+		{
+			name:   "mermaid",
+			format: graphFormatMermaid,
+			want: `graph LR
+  n0["A"]
+  n1["b"]
+  n2(["github.com/example/c"])
+  n0 -->|"network.id → network"| n1
+  n0 -->|"region → region"| n1
+  n2 -.->|"project_id → project_id"| n1
+  linkStyle 1 stroke:red
+  linkStyle 2 stroke:red
+`,
+		},
+		{
+			name:    "invalid",
+			format:  "svg",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := writeGraph(g, tt.format, &b)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestBlueprintSourceID(t *testing.T) {
+	tests := []struct {
+		name string
+		src  *BlueprintRepoDetail
+		want string
+	}{
+		{name: "root", src: &BlueprintRepoDetail{Repo: "https://github.com/Example/terraform-google-a.git"}, want: "github.com/example/terraform-google-a"},
+		{name: "sub module", src: &BlueprintRepoDetail{Repo: "https://github.com/example/terraform-google-a.git", Dir: "/modules/b"}, want: "github.com/example/terraform-google-a//modules/b"},
+		{name: "no repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Info: &BlueprintInfo{Source: tt.src}}}
+			assert.Equal(t, tt.want, blueprintSourceID(md))
+		})
+	}
+}
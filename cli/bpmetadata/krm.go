@@ -0,0 +1,441 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const (
+	kptfileName          = "Kptfile"
+	krmFlavor            = "KRM"
+	applySettersFnImage  = "apply-setters"
+	cnrmGroupSuffix      = ".cnrm.cloud.google.com"
+	cnrmResourceDocsURL  = "https://cloud.google.com/config-connector/docs/reference/resource-docs"
+	kptFunctionConfigKey = "config.kubernetes.io/function"
+)
+
+// cnrmGroupServices maps Config Connector API groups to the services they require
+// where the service name differs from the group name.
+var cnrmGroupServices = map[string]string{
+	"kms":             "cloudkms.googleapis.com",
+	"resourcemanager": "cloudresourcemanager.googleapis.com",
+	"sql":             "sqladmin.googleapis.com",
+}
+
+// kptfile is the subset of a kpt package Kptfile used for metadata generation.
+type kptfile struct {
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Info struct {
+		Description string `yaml:"description"`
+	} `yaml:"info"`
+	Pipeline struct {
+		Mutators   []kptFunction `yaml:"mutators"`
+		Validators []kptFunction `yaml:"validators"`
+	} `yaml:"pipeline"`
+}
+
+// kptFunction is a function in a Kptfile pipeline.
+type kptFunction struct {
+	Image      string      `yaml:"image"`
+	ConfigPath string      `yaml:"configPath"`
+	ConfigMap  yamlv3.Node `yaml:"configMap"`
+}
+
+// krmResource is the subset of a KRM resource used for metadata generation.
+type krmResource struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Annotations map[string]string `yaml:"annotations"`
+	} `yaml:"metadata"`
+	Data yamlv3.Node `yaml:"data"`
+	// file is the path of the file declaring the resource relative to the package
+	file string
+}
+
+// krmPackage is a kpt package read from disk.
+type krmPackage struct {
+	kptfile   kptfile
+	setters   []*BlueprintVariable
+	resources []krmResource
+	// subPackages are the paths of sub-packages relative to the package
+	subPackages []string
+}
+
+// isKptPackage returns whether bpPath is the root of a kpt package.
+func isKptPackage(bpPath string) bool {
+	_, err := os.Stat(filepath.Join(bpPath, kptfileName))
+	return err == nil
+}
+
+// readKptPackage reads the Kptfile, setters and resources of the kpt package at bpPath.
+// Resources of sub-packages are not read.
+func readKptPackage(bpPath string) (*krmPackage, error) {
+	b, err := os.ReadFile(filepath.Join(bpPath, kptfileName))
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &krmPackage{}
+	if err := yamlv3.Unmarshal(b, &pkg.kptfile); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", kptfileName, err)
+	}
+
+	err = filepath.WalkDir(bpPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if p == bpPath {
+				return nil
+			}
+			if strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			// resources of sub-packages belong to the sub-package
+			if isKptPackage(p) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !isKRMResourceFile(d.Name()) {
+			return nil
+		}
+
+		resources, err := readKRMResources(p)
+		if err != nil {
+			return fmt.Errorf("error parsing resources in %s: %w", p, err)
+		}
+		rel, err := filepath.Rel(bpPath, p)
+		if err != nil {
+			return err
+		}
+		for i := range resources {
+			resources[i].file = rel
+		}
+		pkg.resources = append(pkg.resources, resources...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.subPackages, err = findKptPackages(bpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.setters, err = pkg.readSetters(bpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg, nil
+}
+
+// findKptPackages returns the paths of the outermost kpt packages under dir relative
+// to dir ordered by path. dir itself is not included.
+func findKptPackages(dir string) ([]string, error) {
+	var pkgs []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || p == dir {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !isKptPackage(p) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		pkgs = append(pkgs, rel)
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error finding kpt packages under %s: %w", dir, err)
+	}
+	return pkgs, nil
+}
+
+// isKRMResourceFile returns whether a file may contain KRM resources of a package.
+func isKRMResourceFile(name string) bool {
+	if name == metadataFileName || name == metadataDisplayFileName {
+		return false
+	}
+	ext := filepath.Ext(name)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// readKRMResources reads all resources from a multi-document YAML file.
+func readKRMResources(filePath string) ([]krmResource, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []krmResource
+	dec := yamlv3.NewDecoder(bytes.NewReader(b))
+	for {
+		var r krmResource
+		err := dec.Decode(&r)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.Kind == "" {
+			continue
+		}
+		resources = append(resources, r)
+	}
+
+	return resources, nil
+}
+
+// readSetters returns the setters of apply-setters functions in the package pipeline
+// as variables in the order they are declared in.
+func (pkg *krmPackage) readSetters(bpPath string) ([]*BlueprintVariable, error) {
+	var setters []*BlueprintVariable
+	for _, fn := range pkg.kptfile.Pipeline.Mutators {
+		if !strings.Contains(fn.Image, applySettersFnImage) {
+			continue
+		}
+
+		data := &fn.ConfigMap
+		if fn.ConfigPath != "" {
+			fnConfig, err := readKRMResources(filepath.Join(bpPath, fn.ConfigPath))
+			if err != nil {
+				return nil, fmt.Errorf("error parsing setters config %s: %w", fn.ConfigPath, err)
+			}
+			if len(fnConfig) != 1 {
+				return nil, fmt.Errorf("setters config %s should contain a single ConfigMap", fn.ConfigPath)
+			}
+			data = &fnConfig[0].Data
+		}
+
+		vars, err := settersFromData(data)
+		if err != nil {
+			return nil, err
+		}
+		setters = append(setters, vars...)
+	}
+
+	return setters, nil
+}
+
+// settersFromData converts ConfigMap data of setters to variables with the setter
+// values as defaults.
+func settersFromData(data *yamlv3.Node) ([]*BlueprintVariable, error) {
+	if data.Kind == 0 {
+		return nil, nil
+	}
+	if data.Kind != yamlv3.MappingNode {
+		return nil, fmt.Errorf("setters at line %d should be a map", data.Line)
+	}
+
+	var vars []*BlueprintVariable
+	for i := 0; i+1 < len(data.Content); i += 2 {
+		name, value := data.Content[i].Value, data.Content[i+1]
+		v := &BlueprintVariable{
+			Name:    name,
+			VarType: "string",
+		}
+
+		if value.Kind == yamlv3.ScalarNode {
+			// list setters may be set as YAML strings e.g. "[a, b]"
+			var n yamlv3.Node
+			if err := yamlv3.Unmarshal([]byte(value.Value), &n); err == nil && len(n.Content) == 1 && n.Content[0].Kind == yamlv3.SequenceNode {
+				value = n.Content[0]
+			}
+		}
+
+		var defaultValue any = value.Value
+		if value.Kind == yamlv3.SequenceNode {
+			var list []any
+			if err := value.Decode(&list); err != nil {
+				return nil, fmt.Errorf("error parsing setter %s: %w", name, err)
+			}
+			v.VarType = "list(string)"
+			defaultValue = list
+		}
+
+		dv, err := structpb.NewValue(defaultValue)
+		if err != nil {
+			return nil, fmt.Errorf("error converting default value of setter %s: %w", name, err)
+		}
+		v.DefaultValue = dv
+		vars = append(vars, v)
+	}
+
+	return vars, nil
+}
+
+// deployedResources returns the resources of the package that are applied to a
+// cluster i.e. excluding local config and function configs.
+func (pkg *krmPackage) deployedResources() []krmResource {
+	fnConfigs := make(map[string]bool)
+	for _, fns := range [][]kptFunction{pkg.kptfile.Pipeline.Mutators, pkg.kptfile.Pipeline.Validators} {
+		for _, fn := range fns {
+			if fn.ConfigPath != "" {
+				fnConfigs[filepath.Clean(fn.ConfigPath)] = true
+			}
+		}
+	}
+
+	var resources []krmResource
+	for _, r := range pkg.resources {
+		if r.Kind == kptfileName || fnConfigs[r.file] || r.Metadata.Annotations[localConfigAnnotation] == "true" {
+			continue
+		}
+		if _, ok := r.Metadata.Annotations[kptFunctionConfigKey]; ok {
+			continue
+		}
+		resources = append(resources, r)
+	}
+	return resources
+}
+
+// requirements returns the services required by the Config Connector resources
+// of the package.
+func (pkg *krmPackage) requirements() *BlueprintRequirements {
+	services := make(map[string]bool)
+	for _, r := range pkg.deployedResources() {
+		if s := cnrmService(r.APIVersion); s != "" {
+			services[s] = true
+		}
+	}
+	if len(services) == 0 {
+		return nil
+	}
+	return &BlueprintRequirements{Services: sortedKeys(services)}
+}
+
+// resourceReferences returns links to the reference docs of the Config Connector
+// resource kinds of the package ordered by kind.
+func (pkg *krmPackage) resourceReferences() []*BlueprintListContent {
+	refs := make(map[string]string)
+	for _, r := range pkg.deployedResources() {
+		group, ok := cnrmGroup(r.APIVersion)
+		if !ok {
+			continue
+		}
+		refs[r.Kind] = fmt.Sprintf("%s/%s/%s", cnrmResourceDocsURL, group, strings.ToLower(r.Kind))
+	}
+
+	var docs []*BlueprintListContent
+	for _, kind := range sortedKeys(refs) {
+		docs = append(docs, &BlueprintListContent{Title: kind, Url: refs[kind]})
+	}
+	return docs
+}
+
+// cnrmGroup returns the Config Connector API group of apiVersion without the
+// cnrm suffix e.g. "compute" for "compute.cnrm.cloud.google.com/v1beta1".
+func cnrmGroup(apiVersion string) (string, bool) {
+	group, _, ok := strings.Cut(apiVersion, "/")
+	if !ok || !strings.HasSuffix(group, cnrmGroupSuffix) {
+		return "", false
+	}
+	group = strings.TrimSuffix(group, cnrmGroupSuffix)
+	// core resources e.g. ConfigConnectorContext do not require a service
+	return group, group != "core"
+}
+
+// cnrmService returns the service required by Config Connector resources of apiVersion
+// or an empty string if apiVersion is not a Config Connector resource.
+func cnrmService(apiVersion string) string {
+	group, ok := cnrmGroup(apiVersion)
+	if !ok {
+		return ""
+	}
+	if s, ok := cnrmGroupServices[group]; ok {
+		return s
+	}
+	return group + ".googleapis.com"
+}
+
+// createKRM creates the generated fields of a KRM blueprint spec from the kpt package
+// at bpPath. Setters are created as variables, Config Connector resource kinds as
+// documentation and the services they use as requirements.
+func (s *BlueprintMetadataSpec) createKRM(bpPath, rootPath string) error {
+	pkg, err := readKptPackage(bpPath)
+	if err != nil {
+		return err
+	}
+
+	s.Info.ActuationTool = &BlueprintActuationTool{Flavor: krmFlavor}
+	if s.Info.Description.GetTagline() == "" && pkg.kptfile.Info.Description != "" {
+		if s.Info.Description == nil {
+			s.Info.Description = &BlueprintDescription{}
+		}
+		s.Info.Description.Tagline = pkg.kptfile.Info.Description
+	}
+
+	var existingInterfaces *BlueprintInterface
+	if s.Interfaces == nil {
+		s.Interfaces = &BlueprintInterface{}
+	} else {
+		existingInterfaces = proto.Clone(s.Interfaces).(*BlueprintInterface)
+	}
+	s.Interfaces.Variables = pkg.setters
+	mergeExistingConnections(s.Interfaces, existingInterfaces)
+
+	s.Requirements = pkg.requirements()
+
+	// documentation from the readme is kept when it can not be parsed, so
+	// references from previous generations are skipped
+	docs := make(map[string]bool)
+	for _, d := range s.Content.Documentation {
+		docs[d.Title] = true
+	}
+	for _, ref := range pkg.resourceReferences() {
+		if !docs[ref.Title] {
+			s.Content.Documentation = append(s.Content.Documentation, ref)
+		}
+	}
+	s.Content.SubBlueprints = nil
+	for _, p := range pkg.subPackages {
+		// examples are listed separately
+		if strings.HasPrefix(filepath.ToSlash(p), examplesPath+"/") {
+			continue
+		}
+		s.Content.SubBlueprints = append(s.Content.SubBlueprints, &BlueprintMiscContent{
+			Name:     filepath.Base(p),
+			Location: filepath.ToSlash(p),
+		})
+	}
+
+	examples, err := findKptPackages(filepath.Join(rootPath, examplesPath))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for _, p := range examples {
+		s.Content.Examples = append(s.Content.Examples, &BlueprintMiscContent{
+			Name:     filepath.Base(p),
+			Location: filepath.ToSlash(filepath.Join(examplesPath, p)),
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package bpmetadata
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	testKptfile = `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: vpc
+info:
+  description: A Virtual Private Cloud (VPC)
+pipeline:
+  mutators:
+    - image: gcr.io/kpt-fn/apply-setters:v0.2
+      configPath: setters.yaml
+`
+	testSetters = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: setters
+data:
+  project-id: project-id
+  network-name: my-network
+  regions: "[us-east1, us-west1]"
+`
+	testResources = `apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeNetwork
+metadata:
+  name: my-network # kpt-set: ${network-name}
+---
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeSubnetwork
+metadata:
+  name: my-subnet
+---
+apiVersion: sql.cnrm.cloud.google.com/v1beta1
+kind: SQLInstance
+metadata:
+  name: my-sql
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: local
+  annotations:
+    config.kubernetes.io/local-config: "true"
+`
+)
+
+// newTestKptPackage creates a KRM blueprint with setters, Config Connector
+// resources and a sub-package in a temp dir.
+func newTestKptPackage(t *testing.T) string {
+	t.Helper()
+	bpPath := t.TempDir()
+	files := map[string]string{
+		readmeFileName:                         "# VPC\n",
+		kptfileName:                            testKptfile,
+		"setters.yaml":                         testSetters,
+		"network.yaml":                         testResources,
+		filepath.Join("subnet", kptfileName):   "apiVersion: kpt.dev/v1\nkind: Kptfile\n",
+		filepath.Join("subnet", "subnet.yaml"): "apiVersion: kms.cnrm.cloud.google.com/v1beta1\nkind: KMSKeyRing\n",
+		filepath.Join(examplesPath, "simple", kptfileName): "apiVersion: kpt.dev/v1\nkind: Kptfile\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(bpPath, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+	return bpPath
+}
+
+func TestGenerateKRM(t *testing.T) {
+	bpPath := newTestKptPackage(t)
+
+	results, err := Generate(Options{Path: bpPath, Display: true, Quiet: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+
+	spec := results[0].Metadata.Spec
+	assert.Equal(t, krmFlavor, spec.Info.ActuationTool.Flavor)
+	assert.Equal(t, "A Virtual Private Cloud (VPC)", spec.Info.Description.Tagline)
+
+	regions, err := structpb.NewValue([]any{"us-east1", "us-west1"})
+	require.NoError(t, err)
+	wantInterfaces := &BlueprintInterface{Variables: []*BlueprintVariable{
+		{Name: "project-id", VarType: "string", DefaultValue: structpb.NewStringValue("project-id")},
+		{Name: "network-name", VarType: "string", DefaultValue: structpb.NewStringValue("my-network")},
+		{Name: "regions", VarType: "list(string)", DefaultValue: regions},
+	}}
+	assert.True(t, proto.Equal(wantInterfaces, spec.Interfaces), "got %v, want %v", spec.Interfaces, wantInterfaces)
+
+	assert.Equal(t, []string{"compute.googleapis.com", "sqladmin.googleapis.com"}, spec.Requirements.Services)
+	wantContent := &BlueprintContent{
+		Documentation: []*BlueprintListContent{
+			{Title: "ComputeNetwork", Url: cnrmResourceDocsURL + "/compute/computenetwork"},
+			{Title: "ComputeSubnetwork", Url: cnrmResourceDocsURL + "/compute/computesubnetwork"},
+			{Title: "SQLInstance", Url: cnrmResourceDocsURL + "/sql/sqlinstance"},
+		},
+		SubBlueprints: []*BlueprintMiscContent{{Name: "subnet", Location: "subnet"}},
+		Examples:      []*BlueprintMiscContent{{Name: "simple", Location: "examples/simple"}},
+	}
+	assert.True(t, proto.Equal(wantContent, spec.Content), "got %v, want %v", spec.Content, wantContent)
+	assert.Len(t, results[0].DisplayMetadata.Spec.Ui.Input.Variables, 3)
+
+	// regeneration does not duplicate generated content
+	results, err = Generate(Options{Path: bpPath, Check: true})
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	assert.False(t, results[0].Stale, results[0].Diff)
+}
+
+func TestIsBlueprintRootKRM(t *testing.T) {
+	bpPath := newTestKptPackage(t)
+	r, err := git.PlainInit(bpPath, false)
+	require.NoError(t, err)
+	_, err = r.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://github.com/GoogleCloudPlatform/blueprints"}})
+	require.NoError(t, err)
+
+	assert.True(t, isBlueprintRoot(bpPath))
+	assert.False(t, isBlueprintRoot(filepath.Join(bpPath, "subnet")))
+}
+
+func TestSettersFromInlineConfigMap(t *testing.T) {
+	bpPath := t.TempDir()
+	kptfile := `apiVersion: kpt.dev/v1
+kind: Kptfile
+pipeline:
+  mutators:
+    - image: gcr.io/kpt-fn/set-labels:v0.1
+      configMap:
+        env: dev
+    - image: gcr.io/kpt-fn/apply-setters:v0.2
+      configMap:
+        name: my-bucket
+        zones:
+          - us-east1-b
+`
+	require.NoError(t, os.WriteFile(filepath.Join(bpPath, kptfileName), []byte(kptfile), 0644))
+
+	pkg, err := readKptPackage(bpPath)
+	require.NoError(t, err)
+	zones, err := structpb.NewValue([]any{"us-east1-b"})
+	require.NoError(t, err)
+	want := &BlueprintInterface{Variables: []*BlueprintVariable{
+		{Name: "name", VarType: "string", DefaultValue: structpb.NewStringValue("my-bucket")},
+		{Name: "zones", VarType: "list(string)", DefaultValue: zones},
+	}}
+	got := &BlueprintInterface{Variables: pkg.setters}
+	assert.True(t, proto.Equal(want, got), "got %v, want %v", got, want)
+	assert.Nil(t, pkg.requirements())
+}
+
+func TestCnrmService(t *testing.T) {
+	tests := []struct {
+		apiVersion string
+		want       string
+	}{
+		{apiVersion: "compute.cnrm.cloud.google.com/v1beta1", want: "compute.googleapis.com"},
+		{apiVersion: "resourcemanager.cnrm.cloud.google.com/v1beta1", want: "cloudresourcemanager.googleapis.com"},
+		{apiVersion: "core.cnrm.cloud.google.com/v1beta1", want: ""},
+		{apiVersion: "rbac.authorization.k8s.io/v1", want: ""},
+		{apiVersion: "v1", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			assert.Equal(t, tt.want, cnrmService(tt.apiVersion))
+		})
+	}
+}
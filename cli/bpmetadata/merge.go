@@ -0,0 +1,608 @@
+package bpmetadata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// userOwnedFieldsAnnotation lists comma separated field paths, in addition to
+	// userOwnedFields, that are never overwritten on regeneration
+	// e.g. "spec.info.description.tagline, spec.interfaces.variables[region].description".
+	userOwnedFieldsAnnotation = "blueprints.cloud.google.com/user-owned-fields"
+
+	// fingerprintLength is the number of hex characters kept from field value hashes.
+	fingerprintLength = 12
+
+	// elementFingerprint is the fingerprint recording the presence of a keyed element.
+	elementFingerprint = "present"
+
+	// mergeBaseHeader is written at the top of merge base files.
+	mergeBaseHeader = "# Fingerprints of generated metadata values used to keep hand edits on regeneration. Do not edit.\n"
+)
+
+var (
+	// generatedFields are the field paths populated by metadata generation.
+	// Fields not covered by a path here are user-owned. Keyed elements of
+	// repeated fields and maps are matched with [*] or [<key>].
+	generatedFields = []string{
+		"apiVersion",
+		"kind",
+		"spec.info.title",
+		"spec.info.source",
+		"spec.info.version",
+		"spec.info.actuationTool",
+		"spec.info.description",
+		"spec.info.icon",
+		"spec.info.deploymentDuration",
+		"spec.info.costEstimate",
+		"spec.content.documentation",
+		"spec.content.architecture",
+		"spec.content.subBlueprints",
+		"spec.content.examples",
+		"spec.interfaces.variables",
+		"spec.interfaces.outputs",
+		"spec.requirements",
+		"spec.ui.input.variables",
+	}
+
+	// userOwnedFields are hand-authored field paths nested under generatedFields.
+	userOwnedFields = []string{
+		"spec.interfaces.variables[*].connections",
+		"spec.ui.input.variables[*].altDefaults",
+	}
+)
+
+// fieldOwner is the owner of a metadata field.
+type fieldOwner int
+
+const (
+	ownerUser fieldOwner = iota
+	ownerGenerated
+	// ownerMixed fields contain both generated and user-owned fields.
+	ownerMixed
+)
+
+// mergeConflict is a field that was edited by hand while its generated value
+// changed since the last generation.
+type mergeConflict struct {
+	Path    string
+	Message string
+}
+
+// fieldMerger merges newly generated metadata with the existing metadata on disk.
+type fieldMerger struct {
+	// base holds fingerprints of generated values and elements from the last
+	// generation, nil if the last generation is unknown.
+	base map[string]string
+	// next holds fingerprints of generated values and elements to merge with on
+	// the next generation.
+	next      map[string]string
+	generated [][]string
+	userOwned [][]string
+	conflicts []mergeConflict
+	// edits is the number of generated values edited by hand since the last generation.
+	edits int
+}
+
+// newFieldMerger returns a merger for metadata with annotations.
+func newFieldMerger(annotations map[string]string) *fieldMerger {
+	m := &fieldMerger{
+		next:      make(map[string]string),
+		generated: parseFieldPaths(generatedFields),
+		userOwned: parseFieldPaths(userOwnedFields),
+	}
+	m.userOwned = append(m.userOwned, parseFieldPaths(strings.Split(annotations[userOwnedFieldsAnnotation], ","))...)
+	return m
+}
+
+// mergeGeneratedMetadata performs a three-way merge of generated metadata with
+// the existing metadata on disk, using the fingerprints of generated values of the
+// last generation as the base. Generated fields edited by hand are kept if their
+// generated value is unchanged and reported as conflicts otherwise. User-owned fields
+// are always kept. Without a base, existing values of generated fields are kept and
+// reported as conflicts if they differ from the generated ones.
+// The merge result is written to generated. Existing may be nil if there is no
+// metadata on disk. Returns the base of the next generation and the conflicts.
+func mergeGeneratedMetadata(existing, generated *BlueprintMetadata, base map[string]string) (map[string]string, []mergeConflict) {
+	if existing == nil {
+		existing = &BlueprintMetadata{}
+		base = nil
+	}
+	m := newFieldMerger(existing.GetMetadata().GetAnnotations())
+	m.base = base
+	m.mergeMessage(nil, existing.ProtoReflect(), generated.ProtoReflect())
+	return m.next, m.conflicts
+}
+
+// mergeBaseFileName returns the name of the file recording the merge base of
+// metadata fileName e.g. .metadata.yaml.generated.
+func mergeBaseFileName(fileName string) string {
+	return "." + fileName + ".generated"
+}
+
+// readMergeBase reads the merge base of metadata fileName for the blueprint at bpPath.
+// Returns nil if the metadata was not generated with a merge base yet.
+func readMergeBase(bpPath, fileName string) (map[string]string, error) {
+	b, err := os.ReadFile(path.Join(bpPath, mergeBaseFileName(fileName)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading merge base for %s: %w", fileName, err)
+	}
+	return parseFieldFingerprints(string(b)), nil
+}
+
+// writeMergeBase writes the merge base of metadata fileName for the blueprint at bpPath.
+func writeMergeBase(bpPath, fileName string, base map[string]string) error {
+	content := mergeBaseHeader + formatFieldFingerprints(base) + "\n"
+	return os.WriteFile(path.Join(bpPath, mergeBaseFileName(fileName)), []byte(content), 0644)
+}
+
+// existingMetadata returns a copy of metadata read from disk to merge generated
+// metadata with. Returns nil when fresh metadata is forced.
+func existingMetadata(bpObj *BlueprintMetadata, force bool) *BlueprintMetadata {
+	if force || bpObj == nil {
+		return nil
+	}
+	return proto.Clone(bpObj).(*BlueprintMetadata)
+}
+
+// mergeMessage merges fields of cur into gen for the message at path p.
+func (m *fieldMerger) mergeMessage(p []string, cur, gen protoreflect.Message) {
+	fields := gen.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if len(p) > 0 && isKeySegment(p[len(p)-1]) && fd == messageKeyField(gen.Descriptor()) {
+			// keys of elements are matched and not merged
+			continue
+		}
+		fp := appendPath(p, fd.JSONName())
+		owner := m.owner(fp)
+		if owner == ownerUser {
+			if cur.Has(fd) {
+				gen.Set(fd, cur.Get(fd))
+			}
+			continue
+		}
+
+		switch {
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			m.mergeMap(fp, fd, cur, gen)
+		case fd.IsList() && keyField(fd) != nil:
+			m.mergeKeyedList(fp, fd, cur, gen)
+		case !fd.IsList() && !fd.IsMap() && isStructuredMessage(fd):
+			gm := gen.NewField(fd).Message()
+			if gen.Has(fd) {
+				gm = gen.Mutable(fd).Message()
+			}
+			m.mergeMessage(fp, cur.Get(fd).Message(), gm)
+			if !gen.Has(fd) && !isEmptyMessage(gm) {
+				gen.Set(fd, protoreflect.ValueOfMessage(gm))
+			}
+		case owner == ownerGenerated:
+			m.mergeValue(fp, fd, cur, gen)
+		default:
+			if cur.Has(fd) {
+				gen.Set(fd, cur.Get(fd))
+			}
+		}
+	}
+}
+
+// mergeValue performs a three-way merge of a generated field value.
+func (m *fieldMerger) mergeValue(p []string, fd protoreflect.FieldDescriptor, cur, gen protoreflect.Message) {
+	key := formatPath(p)
+	c, n := fieldFingerprint(cur, fd), fieldFingerprint(gen, fd)
+	b := m.base[key]
+
+	keepCurrent := false
+	switch {
+	case c == n:
+	case m.base == nil:
+		// without a base, existing values can not be told apart from hand edits
+		keepCurrent = c != ""
+		if keepCurrent && n != "" {
+			m.conflicts = append(m.conflicts, mergeConflict{
+				Path:    key,
+				Message: "field differs from its generated value and there is no record of the last generation, keeping the existing value",
+			})
+			// leave the field out of the base so that the conflict is reported until it is resolved
+			n = ""
+		}
+	case c == b:
+		// unchanged since the last generation
+	case n == b:
+		// edited by hand and the generated value is unchanged
+		keepCurrent = true
+		m.edits++
+	default:
+		keepCurrent = true
+		m.edits++
+		m.conflicts = append(m.conflicts, mergeConflict{
+			Path:    key,
+			Message: "field was edited and its generated value changed, keeping the edited value",
+		})
+		// keep the base so that the conflict is reported until it is resolved
+		n = b
+	}
+
+	if n != "" {
+		m.next[key] = n
+	}
+	if !keepCurrent {
+		return
+	}
+	if cur.Has(fd) {
+		gen.Set(fd, cur.Get(fd))
+	} else {
+		gen.Clear(fd)
+	}
+}
+
+// mergeKeyedList merges elements of a repeated message field keyed by name.
+// Elements are ordered as generated followed by elements only in cur.
+func (m *fieldMerger) mergeKeyedList(p []string, fd protoreflect.FieldDescriptor, cur, gen protoreflect.Message) {
+	kfd := keyField(fd)
+	curElems := make(map[string]protoreflect.Message)
+	var curKeys []string
+	if cur.Has(fd) {
+		l := cur.Get(fd).List()
+		for i := 0; i < l.Len(); i++ {
+			e := l.Get(i).Message()
+			k := e.Get(kfd).String()
+			if _, ok := curElems[k]; !ok {
+				curElems[k] = e
+				curKeys = append(curKeys, k)
+			}
+		}
+	}
+
+	genKeys := make(map[string]bool)
+	var genList protoreflect.List
+	if gen.Has(fd) {
+		genList = gen.Mutable(fd).List()
+		for i := 0; i < genList.Len(); i++ {
+			e := genList.Get(i).Message()
+			k := e.Get(kfd).String()
+			if genKeys[k] {
+				continue
+			}
+			genKeys[k] = true
+			m.mergeElement(appendKey(p, k), curElems[k], e)
+		}
+	}
+
+	for _, k := range curKeys {
+		if genKeys[k] || !m.keepRemoved(appendKey(p, k), curElems[k]) {
+			continue
+		}
+		if genList == nil {
+			genList = gen.Mutable(fd).List()
+		}
+		genList.Append(protoreflect.ValueOfMessage(curElems[k]))
+	}
+}
+
+// mergeMap merges entries of a map field with message values.
+func (m *fieldMerger) mergeMap(p []string, fd protoreflect.FieldDescriptor, cur, gen protoreflect.Message) {
+	genKeys := make(map[string]bool)
+	if gen.Has(fd) {
+		genMap := gen.Mutable(fd).Map()
+		genMap.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			genKeys[k.String()] = true
+			var curElem protoreflect.Message
+			if cur.Has(fd) && cur.Get(fd).Map().Has(k) {
+				curElem = cur.Get(fd).Map().Get(k).Message()
+			}
+			m.mergeElement(appendKey(p, k.String()), curElem, v.Message())
+			return true
+		})
+	}
+
+	if !cur.Has(fd) {
+		return
+	}
+	curMap := cur.Get(fd).Map()
+	var curKeys []protoreflect.MapKey
+	curMap.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		if !genKeys[k.String()] {
+			curKeys = append(curKeys, k)
+		}
+		return true
+	})
+	sort.Slice(curKeys, func(i, j int) bool { return curKeys[i].String() < curKeys[j].String() })
+	for _, k := range curKeys {
+		if m.keepRemoved(appendKey(p, k.String()), curMap.Get(k).Message()) {
+			gen.Mutable(fd).Map().Set(k, curMap.Get(k))
+		}
+	}
+}
+
+// mergeElement merges a keyed element present in the generated metadata.
+func (m *fieldMerger) mergeElement(p []string, cur, gen protoreflect.Message) {
+	if m.owner(p) == ownerUser {
+		if cur != nil {
+			proto.Reset(gen.Interface())
+			proto.Merge(gen.Interface(), cur.Interface())
+		}
+		return
+	}
+	m.next[formatPath(p)] = elementFingerprint
+	if cur == nil {
+		// new elements are generated as is
+		cur = gen
+	}
+	m.mergeMessage(p, cur, gen)
+}
+
+// keepRemoved returns whether a keyed element that is no longer generated
+// should be kept. User-owned elements and elements added by hand are kept, as
+// are previously generated elements edited by hand which are reported as
+// conflicts. Without a base, elements are kept and reported as conflicts.
+func (m *fieldMerger) keepRemoved(p []string, cur protoreflect.Message) bool {
+	key := formatPath(p)
+	if m.owner(p) == ownerUser {
+		return true
+	}
+	if m.base == nil {
+		m.conflicts = append(m.conflicts, mergeConflict{
+			Path:    key,
+			Message: "element is not generated and there is no record of the last generation, keeping the existing element",
+		})
+		return true
+	}
+	if _, ok := m.base[key]; !ok {
+		// added by hand since the last generation
+		return true
+	}
+
+	probe := &fieldMerger{
+		base:      m.base,
+		next:      make(map[string]string),
+		generated: m.generated,
+		userOwned: m.userOwned,
+	}
+	probe.mergeMessage(p, cur, cur.Type().New())
+	if probe.edits == 0 {
+		return false
+	}
+
+	m.conflicts = append(m.conflicts, mergeConflict{
+		Path:    key,
+		Message: "element was edited and is no longer generated, keeping the edited element",
+	})
+	for k, v := range m.base {
+		if isSubPath(k, key) {
+			m.next[k] = v
+		}
+	}
+	return true
+}
+
+// owner returns the owner of the field at path p.
+func (m *fieldMerger) owner(p []string) fieldOwner {
+	for _, pattern := range m.userOwned {
+		if matchPathPrefix(pattern, p) {
+			return ownerUser
+		}
+	}
+	for _, pattern := range m.generated {
+		if !matchPathPrefix(pattern, p) {
+			continue
+		}
+		for _, userPattern := range m.userOwned {
+			if matchPathPrefix(p, userPattern) {
+				return ownerMixed
+			}
+		}
+		return ownerGenerated
+	}
+	for _, pattern := range m.generated {
+		if matchPathPrefix(p, pattern) {
+			return ownerMixed
+		}
+	}
+	return ownerUser
+}
+
+// matchPathPrefix returns whether prefix matches the leading segments of p.
+// Key segments [*] in either path match any key.
+func matchPathPrefix(prefix, p []string) bool {
+	if len(prefix) > len(p) {
+		return false
+	}
+	for i, s := range prefix {
+		if s == p[i] {
+			continue
+		}
+		if isKeySegment(s) && isKeySegment(p[i]) && (s == "[*]" || p[i] == "[*]") {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func isKeySegment(s string) bool {
+	return strings.HasPrefix(s, "[")
+}
+
+// isSubPath returns whether the formatted path p is at or under parent.
+func isSubPath(p, parent string) bool {
+	return p == parent || strings.HasPrefix(p, parent+".") || strings.HasPrefix(p, parent+"[")
+}
+
+func appendPath(p []string, field string) []string {
+	return append(append([]string{}, p...), field)
+}
+
+func appendKey(p []string, key string) []string {
+	return appendPath(p, "["+key+"]")
+}
+
+// formatPath formats path segments e.g. spec.interfaces.variables[region].description.
+func formatPath(p []string) string {
+	var sb strings.Builder
+	for i, s := range p {
+		if i > 0 && !isKeySegment(s) {
+			sb.WriteString(".")
+		}
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
+
+// parseFieldPath splits a field path into segments. Keys in brackets may contain dots.
+func parseFieldPath(s string) []string {
+	var segments []string
+	var sb strings.Builder
+	inKey := false
+	flush := func() {
+		if sb.Len() > 0 {
+			segments = append(segments, sb.String())
+			sb.Reset()
+		}
+	}
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case inKey:
+			sb.WriteRune(r)
+			if r == ']' {
+				inKey = false
+				flush()
+			}
+		case r == '.':
+			flush()
+		case r == '[':
+			flush()
+			inKey = true
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	flush()
+	return segments
+}
+
+func parseFieldPaths(paths []string) [][]string {
+	var parsed [][]string
+	for _, p := range paths {
+		if segments := parseFieldPath(p); len(segments) > 0 {
+			parsed = append(parsed, segments)
+		}
+	}
+	return parsed
+}
+
+// keyField returns the name field used to key elements of a repeated message field.
+func keyField(fd protoreflect.FieldDescriptor) protoreflect.FieldDescriptor {
+	if fd.Message() == nil {
+		return nil
+	}
+	return messageKeyField(fd.Message())
+}
+
+// messageKeyField returns the name field of md used to key repeated elements.
+func messageKeyField(md protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	kfd := md.Fields().ByName("name")
+	if kfd == nil || kfd.Kind() != protoreflect.StringKind || kfd.IsList() {
+		return nil
+	}
+	return kfd
+}
+
+// isStructuredMessage returns whether fd is a message field merged per field.
+// Well-known types such as google.protobuf.Value are merged as a whole.
+func isStructuredMessage(fd protoreflect.FieldDescriptor) bool {
+	return fd.Message() != nil && !strings.HasPrefix(string(fd.Message().FullName()), "google.protobuf.")
+}
+
+func isEmptyMessage(m protoreflect.Message) bool {
+	empty := true
+	m.Range(func(protoreflect.FieldDescriptor, protoreflect.Value) bool {
+		empty = false
+		return false
+	})
+	return empty
+}
+
+// fieldFingerprint returns a short hash of the value of fd in m or an empty
+// string if the field is not set.
+func fieldFingerprint(m protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	if !m.Has(fd) {
+		return ""
+	}
+	h := sha256.New()
+	v := m.Get(fd)
+	switch {
+	case fd.IsList():
+		l := v.List()
+		for i := 0; i < l.Len(); i++ {
+			writeFingerprintValue(h, fd, l.Get(i))
+			h.Write([]byte{0})
+		}
+	case fd.IsMap():
+		var keys []protoreflect.MapKey
+		v.Map().Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			fmt.Fprintf(h, "%s=", k.String())
+			writeFingerprintValue(h, fd.MapValue(), v.Map().Get(k))
+			h.Write([]byte{0})
+		}
+	default:
+		writeFingerprintValue(h, fd, v)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:fingerprintLength]
+}
+
+func writeFingerprintValue(w io.Writer, fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	if fd.Message() == nil {
+		fmt.Fprint(w, v.Interface())
+		return
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(v.Message().Interface())
+	if err != nil {
+		fmt.Fprint(w, err)
+		return
+	}
+	w.Write(b)
+}
+
+// parseFieldFingerprints parses fingerprints with one <path>=<fingerprint> entry
+// per line. Lines starting with # are ignored.
+func parseFieldFingerprints(s string) map[string]string {
+	fingerprints := make(map[string]string)
+	for _, line := range strings.Split(s, "\n") {
+		i := strings.LastIndex(line, "=")
+		if i <= 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fingerprints[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	return fingerprints
+}
+
+func formatFieldFingerprints(fingerprints map[string]string) string {
+	var lines []string
+	for _, k := range sortedKeys(fingerprints) {
+		lines = append(lines, k+"="+fingerprints[k])
+	}
+	return strings.Join(lines, "\n")
+}
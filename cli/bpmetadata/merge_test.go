@@ -0,0 +1,272 @@
+package bpmetadata
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMergeGeneratedMetadata(t *testing.T) {
+	newGenerated := func(tagline string, vars ...*BlueprintVariable) *BlueprintMetadata {
+		return &BlueprintMetadata{
+			ApiVersion: metadataApiVersion,
+			Kind:       metadataKind,
+			Metadata:   &ResourceTypeMeta{Name: "terraform-google-foo"},
+			Spec: &BlueprintMetadataSpec{
+				Info: &BlueprintInfo{
+					Title:       "Foo",
+					Description: &BlueprintDescription{Tagline: tagline},
+				},
+				Interfaces: &BlueprintInterface{Variables: vars},
+			},
+		}
+	}
+	variable := func(name, description string) *BlueprintVariable {
+		return &BlueprintVariable{Name: name, Description: description, VarType: "string"}
+	}
+	withOutputs := func(m *BlueprintMetadata, names ...string) *BlueprintMetadata {
+		for _, name := range names {
+			m.Spec.Interfaces.Outputs = append(m.Spec.Interfaces.Outputs, &BlueprintOutput{Name: name})
+		}
+		return m
+	}
+
+	tests := []struct {
+		name string
+		// previous is the generated metadata of the last generation, if any.
+		previous *BlueprintMetadata
+		// noBase omits the merge base of previous e.g. for metadata generated before
+		// merge bases were recorded.
+		noBase bool
+		// edit applies hand edits to the metadata on disk.
+		edit          func(*BlueprintMetadata)
+		generated     *BlueprintMetadata
+		want          *BlueprintMetadata
+		wantConflicts []string
+	}{
+		{
+			name:      "no existing metadata",
+			generated: newGenerated("Tagline", variable("foo", "Foo")),
+			want:      newGenerated("Tagline", variable("foo", "Foo")),
+		},
+		{
+			name:      "generated change without edits",
+			previous:  newGenerated("Tagline", variable("foo", "Foo")),
+			generated: newGenerated("New tagline", variable("foo", "New foo")),
+			want:      newGenerated("New tagline", variable("foo", "New foo")),
+		},
+		{
+			name:     "edit without generated change",
+			previous: newGenerated("Tagline", variable("foo", "Foo")),
+			edit: func(m *BlueprintMetadata) {
+				m.Spec.Info.Description.Tagline = "Edited tagline"
+				m.Spec.Interfaces.Variables[0].Description = "Edited foo"
+			},
+			generated: newGenerated("Tagline", variable("foo", "Foo")),
+			want:      newGenerated("Edited tagline", variable("foo", "Edited foo")),
+		},
+		{
+			name:     "edit with generated change",
+			previous: newGenerated("Tagline", variable("foo", "Foo")),
+			edit: func(m *BlueprintMetadata) {
+				m.Spec.Info.Description.Tagline = "Edited tagline"
+			},
+			generated:     newGenerated("New tagline", variable("foo", "New foo")),
+			want:          newGenerated("Edited tagline", variable("foo", "New foo")),
+			wantConflicts: []string{"spec.info.description.tagline"},
+		},
+		{
+			name:     "deleted generated field",
+			previous: newGenerated("Tagline", variable("foo", "Foo")),
+			edit: func(m *BlueprintMetadata) {
+				m.Spec.Info.Description.Tagline = ""
+			},
+			generated: newGenerated("Tagline", variable("foo", "Foo")),
+			want:      newGenerated("", variable("foo", "Foo")),
+		},
+		{
+			name:     "pre-existing metadata without merge base",
+			previous: withOutputs(newGenerated("Tagline", variable("foo", "Foo"), variable("bar", "Bar")), "id"),
+			noBase:   true,
+			edit: func(m *BlueprintMetadata) {
+				m.Spec.Info.Description.Tagline = "Edited tagline"
+				m.Spec.Interfaces.Variables[1].Description = "Edited bar"
+				m.Spec.Info.SupportInfo = &BlueprintSupport{Description: "Support"}
+			},
+			generated: newGenerated("Tagline", variable("foo", "Foo"), variable("bar", "Bar"), variable("baz", "Baz")),
+			want: func() *BlueprintMetadata {
+				m := withOutputs(newGenerated("Edited tagline", variable("foo", "Foo"), variable("bar", "Edited bar"), variable("baz", "Baz")), "id")
+				m.Spec.Info.SupportInfo = &BlueprintSupport{Description: "Support"}
+				return m
+			}(),
+			wantConflicts: []string{"spec.info.description.tagline", "spec.interfaces.variables[bar].description", "spec.interfaces.outputs[id]"},
+		},
+		{
+			name:     "user-owned fields",
+			previous: newGenerated("Tagline", variable("foo", "Foo")),
+			edit: func(m *BlueprintMetadata) {
+				m.Metadata.Annotations[userOwnedFieldsAnnotation] = "spec.info.description.tagline, spec.interfaces.variables[foo]"
+				m.Spec.Info.Description.Tagline = "Edited tagline"
+				m.Spec.Interfaces.Variables[0].Description = "Edited foo"
+				m.Spec.Interfaces.Variables[0].Connections = []*BlueprintConnection{{Source: &ConnectionSource{Source: "bar"}}}
+				m.Spec.Info.SupportInfo = &BlueprintSupport{Description: "Support"}
+			},
+			generated: newGenerated("New tagline", variable("foo", "New foo")),
+			want: func() *BlueprintMetadata {
+				m := newGenerated("Edited tagline", variable("foo", "Edited foo"))
+				m.Metadata.Annotations = map[string]string{userOwnedFieldsAnnotation: "spec.info.description.tagline, spec.interfaces.variables[foo]"}
+				m.Spec.Interfaces.Variables[0].Connections = []*BlueprintConnection{{Source: &ConnectionSource{Source: "bar"}}}
+				m.Spec.Info.SupportInfo = &BlueprintSupport{Description: "Support"}
+				return m
+			}(),
+		},
+		{
+			name:     "keyed elements",
+			previous: newGenerated("Tagline", variable("foo", "Foo"), variable("bar", "Bar"), variable("baz", "Baz")),
+			edit: func(m *BlueprintMetadata) {
+				m.Spec.Interfaces.Variables[1].Description = "Edited bar"
+				m.Spec.Interfaces.Variables = append(m.Spec.Interfaces.Variables, variable("custom", "Custom"))
+			},
+			generated:     newGenerated("Tagline", variable("qux", "Qux"), variable("foo", "Foo")),
+			want:          newGenerated("Tagline", variable("qux", "Qux"), variable("foo", "Foo"), variable("bar", "Edited bar"), variable("custom", "Custom")),
+			wantConflicts: []string{"spec.interfaces.variables[bar]"},
+		},
+		{
+			name:      "removed elements without values",
+			previous:  withOutputs(newGenerated("Tagline"), "id", "name"),
+			generated: withOutputs(newGenerated("Tagline"), "id"),
+			want:      withOutputs(newGenerated("Tagline"), "id"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var existing *BlueprintMetadata
+			var base map[string]string
+			if tt.previous != nil {
+				existing = proto.Clone(tt.previous).(*BlueprintMetadata)
+				if !tt.noBase {
+					base, _ = mergeGeneratedMetadata(nil, proto.Clone(tt.previous).(*BlueprintMetadata), nil)
+				}
+				if existing.Metadata.Annotations == nil {
+					existing.Metadata.Annotations = make(map[string]string)
+				}
+				if tt.edit != nil {
+					tt.edit(existing)
+				}
+			}
+
+			_, conflicts := mergeGeneratedMetadata(existing, tt.generated, base)
+			var gotConflicts []string
+			for _, c := range conflicts {
+				gotConflicts = append(gotConflicts, c.Path)
+			}
+			assert.Equal(t, tt.wantConflicts, gotConflicts)
+			assert.True(t, proto.Equal(tt.want, tt.generated), "got %v, want %v", tt.generated, tt.want)
+		})
+	}
+}
+
+func TestMergeGeneratedMetadataEditsPersist(t *testing.T) {
+	newGenerated := func(title string, outputs ...string) *BlueprintMetadata {
+		m := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{
+			Info:       &BlueprintInfo{Title: title},
+			Interfaces: &BlueprintInterface{},
+		}}
+		for _, o := range outputs {
+			m.Spec.Interfaces.Outputs = append(m.Spec.Interfaces.Outputs, &BlueprintOutput{Name: o})
+		}
+		return m
+	}
+
+	previous := newGenerated("Foo", "id")
+	base, _ := mergeGeneratedMetadata(nil, previous, nil)
+
+	previous.Spec.Info.Title = "Edited"
+	previous.Spec.Interfaces.Outputs = append(previous.Spec.Interfaces.Outputs, &BlueprintOutput{Name: "custom"})
+	generated := newGenerated("Foo", "id")
+	base, conflicts := mergeGeneratedMetadata(previous, generated, base)
+	assert.Empty(t, conflicts)
+
+	// edits are kept on further regeneration
+	regenerated := newGenerated("Foo", "id")
+	base, conflicts = mergeGeneratedMetadata(proto.Clone(generated).(*BlueprintMetadata), regenerated, base)
+	assert.Empty(t, conflicts)
+	assert.True(t, proto.Equal(generated, regenerated))
+
+	// a generated change of an edited field is a conflict
+	changed := newGenerated("Bar", "id")
+	_, conflicts = mergeGeneratedMetadata(regenerated, changed, base)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "Edited", changed.Spec.Info.Title)
+}
+
+func TestMergeGeneratedMetadataConflictPersists(t *testing.T) {
+	previous := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Info: &BlueprintInfo{Title: "Foo"}}}
+	base, _ := mergeGeneratedMetadata(nil, previous, nil)
+	previous.Spec.Info.Title = "Edited"
+
+	generated := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Info: &BlueprintInfo{Title: "Bar"}}}
+	base, conflicts := mergeGeneratedMetadata(previous, generated, base)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "Edited", generated.Spec.Info.Title)
+
+	// the conflict is reported until resolved
+	regenerated := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Info: &BlueprintInfo{Title: "Bar"}}}
+	base, conflicts = mergeGeneratedMetadata(generated, regenerated, base)
+	assert.Len(t, conflicts, 1)
+
+	// accepting the generated value resolves the conflict
+	regenerated.Spec.Info.Title = "Bar"
+	resolved := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Info: &BlueprintInfo{Title: "Bar"}}}
+	_, conflicts = mergeGeneratedMetadata(regenerated, resolved, base)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, "Bar", resolved.Spec.Info.Title)
+}
+
+func TestMergeBaseFile(t *testing.T) {
+	bpPath := t.TempDir()
+	base, err := readMergeBase(bpPath, metadataFileName)
+	require.NoError(t, err)
+	assert.Nil(t, base)
+
+	want := map[string]string{
+		"spec.info.title":                 "1cbec737f863",
+		"spec.interfaces.outputs[a=b]":    elementFingerprint,
+		"spec.interfaces.outputs[a=b].id": "0123456789ab",
+	}
+	require.NoError(t, writeMergeBase(bpPath, metadataFileName, want))
+	got, err := readMergeBase(bpPath, metadataFileName)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// merge bases are kept per metadata file
+	got, err = readMergeBase(bpPath, metadataDisplayFileName)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	assert.FileExists(t, path.Join(bpPath, ".metadata.yaml.generated"))
+}
+
+func TestParseFieldPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "spec.info.title", want: []string{"spec", "info", "title"}},
+		{path: " spec.interfaces.variables[*].connections ", want: []string{"spec", "interfaces", "variables", "[*]", "connections"}},
+		{path: "spec.ui.input.variables[a.b]", want: []string{"spec", "ui", "input", "variables", "[a.b]"}},
+		{path: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := parseFieldPath(tt.path)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, strings.TrimSpace(tt.path), formatPath(got))
+		})
+	}
+}
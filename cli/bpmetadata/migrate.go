@@ -0,0 +1,238 @@
+package bpmetadata
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	yamlv3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+)
+
+// metadataConverter converts a metadata document from one API version to another.
+// Converters operate on the YAML node tree of the document.
+type metadataConverter struct {
+	from    string
+	to      string
+	convert func(doc *yamlv3.Node) error
+}
+
+var (
+	// metadataVersionTypes maps supported API versions to their proto types.
+	metadataVersionTypes = map[string]func() proto.Message{
+		metadataApiVersion: func() proto.Message { return &BlueprintMetadata{} },
+	}
+
+	// metadataConverters are the registered conversions between API versions.
+	metadataConverters []metadataConverter
+)
+
+// registerMetadataConverter registers a conversion of metadata documents between API versions.
+// The apiVersion field is updated after convert is applied.
+func registerMetadataConverter(from, to string, convert func(doc *yamlv3.Node) error) {
+	metadataConverters = append(metadataConverters, metadataConverter{from: from, to: to, convert: convert})
+}
+
+// migrationPath returns the shortest chain of converters from one API version to another.
+func migrationPath(from, to string) ([]metadataConverter, error) {
+	if from == to {
+		return nil, nil
+	}
+	paths := map[string][]metadataConverter{from: nil}
+	queue := []string{from}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, c := range metadataConverters {
+			if c.from != curr {
+				continue
+			}
+			if _, seen := paths[c.to]; seen {
+				continue
+			}
+			paths[c.to] = append(append([]metadataConverter{}, paths[curr]...), c)
+			if c.to == to {
+				return paths[c.to], nil
+			}
+			queue = append(queue, c.to)
+		}
+	}
+	return nil, fmt.Errorf("no migration found from %s to %s", from, to)
+}
+
+// migrateMetadataContent converts YAML metadata content to the API version to.
+// Converted content is formatted the same way as generated metadata.
+// Returns the converted content and whether it was changed.
+func migrateMetadataContent(content []byte, to string) ([]byte, bool, error) {
+	newType, ok := metadataVersionTypes[to]
+	if !ok {
+		return nil, false, fmt.Errorf("unsupported version %s - one of %+q expected", to, supportedMetadataVersions())
+	}
+
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(content, &doc); err != nil {
+		return nil, false, fmt.Errorf("error parsing metadata: %w", err)
+	}
+	versionNode := mappingValue(documentRoot(&doc), "apiVersion")
+	if versionNode == nil {
+		return nil, false, fmt.Errorf("metadata is missing apiVersion")
+	}
+
+	converters, err := migrationPath(versionNode.Value, to)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(converters) == 0 {
+		return content, false, nil
+	}
+
+	for _, c := range converters {
+		if err := c.convert(&doc); err != nil {
+			return nil, false, fmt.Errorf("error converting metadata from %s to %s: %w", c.from, c.to, err)
+		}
+		// converters may replace nodes so look up the version again
+		versionNode = mappingValue(documentRoot(&doc), "apiVersion")
+		if versionNode == nil {
+			return nil, false, fmt.Errorf("metadata converted from %s to %s is missing apiVersion", c.from, c.to)
+		}
+		versionNode.Value = c.to
+	}
+
+	converted, err := yamlv3.Marshal(&doc)
+	if err != nil {
+		return nil, false, err
+	}
+	j, err := yaml.YAMLToJSON(converted)
+	if err != nil {
+		return nil, false, err
+	}
+	md := newType()
+	if err := protojson.Unmarshal(j, md); err != nil {
+		return nil, false, fmt.Errorf("metadata converted to %s is invalid: %w", to, err)
+	}
+	b, err := marshalMetadata(md)
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+// upgradeMetadataContent converts YAML metadata content of an older API version to the
+// current version if a migration exists. Content is returned unchanged otherwise.
+func upgradeMetadataContent(content []byte) ([]byte, error) {
+	var md struct {
+		ApiVersion string `json:"apiVersion"`
+	}
+	if err := yaml.Unmarshal(content, &md); err != nil {
+		return nil, err
+	}
+	if md.ApiVersion == metadataApiVersion {
+		return content, nil
+	}
+	if _, err := migrationPath(md.ApiVersion, metadataApiVersion); err != nil {
+		return content, nil
+	}
+	upgraded, _, err := migrateMetadataContent(content, metadataApiVersion)
+	return upgraded, err
+}
+
+// migrateMetadata migrates all metadata files for the blueprint at bpPath,
+// including sub modules, to API version to and rewrites them in place.
+func migrateMetadata(bpPath, to string, w io.Writer) error {
+	if _, ok := metadataVersionTypes[to]; !ok {
+		return fmt.Errorf("unsupported version %s - one of %+q expected", to, supportedMetadataVersions())
+	}
+
+	metadataFiles, err := util.FindFilesWithPattern(bpPath, `^metadata(?:.display)?.yaml$`, []string{".terraform/"})
+	if err != nil {
+		return fmt.Errorf("error finding metadata files: %w", err)
+	}
+
+	var errs []error
+	for _, f := range metadataFiles {
+		info, err := os.Stat(f)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		content, err := os.ReadFile(f)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		migrated, changed, err := migrateMetadataContent(content, to)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f, err))
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if hasComments(content) {
+			errs = append(errs, fmt.Errorf("%s: comments can not be preserved by migration, remove them and migrate again", f))
+			continue
+		}
+		if err := os.WriteFile(f, migrated, info.Mode().Perm()); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintf(w, "migrated %s to %s\n", displayPath(f), to)
+	}
+	return errors.Join(errs...)
+}
+
+func supportedMetadataVersions() []string {
+	versions := make([]string, 0, len(metadataVersionTypes))
+	for v := range metadataVersionTypes {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// hasComments returns whether YAML content has any comments.
+func hasComments(content []byte) bool {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(content, &doc); err != nil {
+		return false
+	}
+	var walk func(n *yamlv3.Node) bool
+	walk = func(n *yamlv3.Node) bool {
+		if n.HeadComment != "" || n.LineComment != "" || n.FootComment != "" {
+			return true
+		}
+		for _, c := range n.Content {
+			if walk(c) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(&doc)
+}
+
+// documentRoot returns the root node of a YAML document.
+func documentRoot(doc *yamlv3.Node) *yamlv3.Node {
+	if doc.Kind == yamlv3.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return doc
+}
+
+// mappingValue returns the value node for key in a mapping node or nil if it does not exist.
+func mappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// testApiVersionV1alpha0 is a legacy API version in which variables declared their
+// type in a type field instead of varType.
+const testApiVersionV1alpha0 = "blueprints.cloud.google.com/v1alpha0"
+
+const testOldMetadata = `apiVersion: blueprints.cloud.google.com/v1alpha0
+kind: BlueprintMetadata
+metadata:
+    name: terraform-google-module
+spec:
+    info:
+        title: Terraform Google Module
+    interfaces:
+        variables:
+        - name: project_id
+          type: string
+          required: true
+`
+
+const testMigratedMetadata = `apiVersion: blueprints.cloud.google.com/v1alpha1
+kind: BlueprintMetadata
+metadata:
+  name: terraform-google-module
+spec:
+  info:
+    title: Terraform Google Module
+  interfaces:
+    variables:
+      - name: project_id
+        varType: string
+        required: true
+`
+
+// withTestConverters registers converters for the duration of a test.
+func withTestConverters(t *testing.T, converters ...metadataConverter) {
+	orig := metadataConverters
+	t.Cleanup(func() { metadataConverters = orig })
+	for _, c := range converters {
+		registerMetadataConverter(c.from, c.to, c.convert)
+	}
+}
+
+func noopConverter(*yamlv3.Node) error { return nil }
+
+// testV1alpha0Converter renames the type field of variables to varType.
+var testV1alpha0Converter = metadataConverter{
+	from: testApiVersionV1alpha0,
+	to:   metadataApiVersion,
+	convert: func(doc *yamlv3.Node) error {
+		vars := mappingValue(mappingValue(mappingValue(documentRoot(doc), "spec"), "interfaces"), "variables")
+		if vars == nil {
+			return nil
+		}
+		for _, v := range vars.Content {
+			for i := 0; i+1 < len(v.Content); i += 2 {
+				if v.Content[i].Value == "type" {
+					v.Content[i].Value = "varType"
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func TestMigrationPath(t *testing.T) {
+	withTestConverters(t,
+		metadataConverter{from: "a", to: "b", convert: noopConverter},
+		metadataConverter{from: "b", to: "c", convert: noopConverter},
+		metadataConverter{from: "a", to: "c", convert: noopConverter},
+		metadataConverter{from: "c", to: "d", convert: noopConverter},
+	)
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		want    []string
+		wantErr bool
+	}{
+		{name: "same version", from: "a", to: "a"},
+		{name: "direct", from: "a", to: "b", want: []string{"a->b"}},
+		{name: "shortest chain", from: "a", to: "d", want: []string{"a->c", "c->d"}},
+		{name: "chain", from: "b", to: "d", want: []string{"b->c", "c->d"}},
+		{name: "no downgrade", from: "d", to: "a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := migrationPath(tt.from, tt.to)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var steps []string
+			for _, c := range got {
+				steps = append(steps, c.from+"->"+c.to)
+			}
+			assert.Equal(t, tt.want, steps)
+		})
+	}
+}
+
+func TestMigrateMetadataContent(t *testing.T) {
+	withTestConverters(t, testV1alpha0Converter)
+
+	got, changed, err := migrateMetadataContent([]byte(testOldMetadata), metadataApiVersion)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, testMigratedMetadata, string(got))
+
+	got, changed, err = migrateMetadataContent([]byte(testMigratedMetadata), metadataApiVersion)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, testMigratedMetadata, string(got))
+}
+
+func TestMigrateMetadataContentErrors(t *testing.T) {
+	withTestConverters(t,
+		metadataConverter{from: "v0", to: metadataApiVersion, convert: func(*yamlv3.Node) error { return fmt.Errorf("boom") }},
+		metadataConverter{from: "v1", to: metadataApiVersion, convert: noopConverter},
+	)
+
+	tests := []struct {
+		name    string
+		content string
+		to      string
+		wantErr string
+	}{
+		{name: "missing version", content: "kind: BlueprintMetadata\n", wantErr: "metadata is missing apiVersion"},
+		{name: "unknown version", content: "apiVersion: v9\n", wantErr: "no migration found from v9 to " + metadataApiVersion},
+		{name: "converter error", content: "apiVersion: v0\n", wantErr: "error converting metadata from v0 to " + metadataApiVersion + ": boom"},
+		{name: "invalid result", content: "apiVersion: v1\nspec:\n  unknownField: true\n", wantErr: "metadata converted to " + metadataApiVersion + " is invalid"},
+		{name: "unsupported target", content: "apiVersion: v1\n", to: "v2", wantErr: "unsupported version v2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := tt.to
+			if to == "" {
+				to = metadataApiVersion
+			}
+			_, _, err := migrateMetadataContent([]byte(tt.content), to)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestUnmarshalMetadataUpgrade(t *testing.T) {
+	withTestConverters(t, testV1alpha0Converter)
+
+	bpPath := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(bpPath, metadataFileName), []byte(testOldMetadata), 0644))
+
+	got, err := UnmarshalMetadata(bpPath, metadataFileName)
+	require.NoError(t, err)
+	assert.Equal(t, metadataApiVersion, got.ApiVersion)
+	assert.Equal(t, "string", got.Spec.Interfaces.Variables[0].VarType)
+}
+
+func TestMigrateMetadata(t *testing.T) {
+	withTestConverters(t, testV1alpha0Converter)
+
+	commented := strings.Replace(testOldMetadata, "    info:\n", "    info:\n        # hand-authored title\n", 1)
+	bpPath := t.TempDir()
+	files := map[string]string{
+		metadataFileName:                       testOldMetadata,
+		"modules/sub/" + metadataFileName:      testMigratedMetadata,
+		".terraform/mod/" + metadataFileName:   testOldMetadata,
+		"modules/other/" + metadataFileName:    testOldMetadata,
+		"modules/comments/" + metadataFileName: commented,
+	}
+	for f, content := range files {
+		require.NoError(t, os.MkdirAll(path.Dir(path.Join(bpPath, f)), 0755))
+		require.NoError(t, os.WriteFile(path.Join(bpPath, f), []byte(content), 0644))
+	}
+
+	var b bytes.Buffer
+	err := migrateMetadata(bpPath, metadataApiVersion, &b)
+	assert.EqualError(t, err, path.Join(bpPath, "modules/comments", metadataFileName)+": comments can not be preserved by migration, remove them and migrate again")
+	assert.Equal(t,
+		"migrated "+displayPath(path.Join(bpPath, metadataFileName))+" to "+metadataApiVersion+"\n"+
+			"migrated "+displayPath(path.Join(bpPath, "modules/other", metadataFileName))+" to "+metadataApiVersion+"\n",
+		b.String())
+
+	want := map[string]string{
+		metadataFileName:                       testMigratedMetadata,
+		"modules/sub/" + metadataFileName:      testMigratedMetadata,
+		".terraform/mod/" + metadataFileName:   testOldMetadata,
+		"modules/other/" + metadataFileName:    testMigratedMetadata,
+		"modules/comments/" + metadataFileName: commented,
+	}
+	for f, content := range want {
+		got, err := os.ReadFile(path.Join(bpPath, f))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(got), f)
+	}
+
+	err = migrateMetadata(bpPath, "blueprints.cloud.google.com/v2", &b)
+	assert.EqualError(t, err, `unsupported version blueprints.cloud.google.com/v2 - one of ["blueprints.cloud.google.com/v1alpha1"] expected`)
+}
+
+func TestHasComments(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{name: "no comments", content: testOldMetadata},
+		{name: "head comment", content: "# header\nkind: BlueprintMetadata\n", want: true},
+		{name: "line comment", content: "kind: BlueprintMetadata # kind\n", want: true},
+		{name: "foot comment", content: "spec:\n  info: {}\n  # trailing\n", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hasComments([]byte(tt.content)))
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package bpmetadata
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Ids of org policies that prevent deploying resources of known patterns.
+const (
+	policyVMExternalIPAccess         = "compute-vmExternalIpAccess"
+	policyVMCanIPForward             = "compute-vmCanIpForward"
+	policyPublicAccessPrevention     = "storage-publicAccessPrevention"
+	policyAllowedPolicyMemberDomains = "iam-allowedPolicyMemberDomains"
+	policyDisableSAKeyCreation       = "iam-disableServiceAccountKeyCreation"
+)
+
+var (
+	// resources with network interfaces that may have external IPs
+	instanceResourceTypes = []string{
+		"google_compute_instance",
+		"google_compute_instance_template",
+		"google_compute_region_instance_template",
+	}
+
+	// bucket IAM and ACL resources mapped to the attribute holding the principals
+	bucketPrincipalAttrs = map[string]string{
+		"google_storage_bucket_iam_member":             "member",
+		"google_storage_bucket_iam_binding":            "members",
+		"google_storage_bucket_access_control":         "entity",
+		"google_storage_bucket_acl":                    "role_entity",
+		"google_storage_default_object_access_control": "entity",
+		"google_storage_object_access_control":         "entity",
+	}
+
+	// principals granting public access
+	publicPrincipals = []string{"allUsers", "allAuthenticatedUsers"}
+)
+
+// inferOrgPolicyChecks proposes org policy checks for resources of the TF config at
+// bpPath that can not be deployed if the org policy is enforced i.e. instances with
+// external IPs or IP forwarding, public buckets and service account keys.
+func inferOrgPolicyChecks(bpPath string) ([]*BlueprintOrgPolicyCheck, error) {
+	policies := make(map[string]bool)
+	err := forEachBlock(bpPath, "resource", 2, func(block *hclsyntax.Block, _ []byte) {
+		for _, p := range resourceOrgPolicies(block) {
+			if !policies[p] {
+				Log.Info("resource requires org policy exception", "resource", block.Labels[0]+"."+block.Labels[1], "policy", p)
+			}
+			policies[p] = true
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []*BlueprintOrgPolicyCheck
+	for _, p := range sortedKeys(policies) {
+		checks = append(checks, &BlueprintOrgPolicyCheck{PolicyId: p})
+	}
+	return checks, nil
+}
+
+// resourceOrgPolicies returns the org policies preventing deployment of a resource block.
+func resourceOrgPolicies(block *hclsyntax.Block) []string {
+	resourceType := block.Labels[0]
+	var policies []string
+	switch {
+	case slices.Contains(instanceResourceTypes, resourceType):
+		for _, ni := range block.Body.Blocks {
+			if ni.Type == "network_interface" && hasBlock(ni.Body, "access_config") {
+				policies = append(policies, policyVMExternalIPAccess)
+				break
+			}
+		}
+		if attr, ok := block.Body.Attributes["can_ip_forward"]; ok && !isFalseLiteral(attr.Expr) {
+			policies = append(policies, policyVMCanIPForward)
+		}
+	case resourceType == "google_service_account_key":
+		policies = append(policies, policyDisableSAKeyCreation)
+	default:
+		attrName, ok := bucketPrincipalAttrs[resourceType]
+		if !ok {
+			return nil
+		}
+		attr, ok := block.Body.Attributes[attrName]
+		if ok && hasPublicPrincipal(attr.Expr) {
+			policies = append(policies, policyPublicAccessPrevention, policyAllowedPolicyMemberDomains)
+		}
+	}
+	return policies
+}
+
+// hasBlock returns whether body has a nested block or dynamic block of blockType.
+func hasBlock(body *hclsyntax.Body, blockType string) bool {
+	return slices.ContainsFunc(body.Blocks, func(b *hclsyntax.Block) bool {
+		return b.Type == blockType || b.Type == "dynamic" && len(b.Labels) == 1 && b.Labels[0] == blockType
+	})
+}
+
+// isFalseLiteral returns whether expr is the literal false.
+func isFalseLiteral(expr hclsyntax.Expression) bool {
+	if len(expr.Variables()) > 0 {
+		return false
+	}
+	val, diags := expr.Value(nil)
+	return !diags.HasErrors() && val.Type() == cty.Bool && val.IsKnown() && val.False()
+}
+
+// hasPublicPrincipal returns whether expr contains a string literal of a public principal
+// e.g. "allUsers" or "READER:allUsers".
+func hasPublicPrincipal(expr hclsyntax.Expression) bool {
+	found := false
+	hclsyntax.VisitAll(expr, func(n hclsyntax.Node) hcl.Diagnostics {
+		lit, ok := n.(*hclsyntax.LiteralValueExpr)
+		if !ok || lit.Val.Type() != cty.String || !lit.Val.IsKnown() {
+			return nil
+		}
+		for _, p := range publicPrincipals {
+			if s := lit.Val.AsString(); s == p || strings.HasSuffix(s, ":"+p) {
+				found = true
+			}
+		}
+		return nil
+	})
+	return found
+}
@@ -0,0 +1,111 @@
+package bpmetadata
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orgPolicyTestConfig = `
+resource "google_compute_instance_template" "tpl" {
+  machine_type   = "e2-medium"
+  can_ip_forward = var.ip_forward
+
+  network_interface {
+    network = "default"
+  }
+
+  network_interface {
+    network = "other"
+    dynamic "access_config" {
+      for_each = var.external_ip ? [1] : []
+      content {}
+    }
+  }
+}
+
+resource "google_compute_instance" "private" {
+  name           = "private"
+  machine_type   = "e2-medium"
+  can_ip_forward = false
+
+  network_interface {
+    network = "default"
+  }
+}
+
+resource "google_storage_bucket_iam_binding" "public" {
+  bucket  = "bucket"
+  role    = "roles/storage.objectViewer"
+  members = ["user:jane@example.com", "allUsers"]
+}
+
+resource "google_storage_bucket_iam_member" "private" {
+  bucket = "bucket"
+  role   = "roles/storage.objectViewer"
+  member = "user:allUsers@example.com"
+}
+
+resource "google_service_account_key" "key" {
+  service_account_id = "sa"
+}
+`
+
+func TestInferOrgPolicyChecks(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{
+			name:   "all patterns",
+			config: orgPolicyTestConfig,
+			want: []string{
+				policyVMCanIPForward,
+				policyVMExternalIPAccess,
+				policyAllowedPolicyMemberDomains,
+				policyDisableSAKeyCreation,
+				policyPublicAccessPrevention,
+			},
+		},
+		{
+			name: "bucket ACL",
+			config: `
+resource "google_storage_bucket_acl" "acl" {
+  bucket      = "bucket"
+  role_entity = ["OWNER:project-owners-123", "READER:allAuthenticatedUsers"]
+}
+`,
+			want: []string{policyAllowedPolicyMemberDomains, policyPublicAccessPrevention},
+		},
+		{
+			name: "no exceptions",
+			config: `
+resource "google_compute_instance" "vm" {
+  machine_type = "e2-medium"
+  network_interface {
+    network = "default"
+  }
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bpPath := t.TempDir()
+			require.NoError(t, os.WriteFile(path.Join(bpPath, "main.tf"), []byte(tt.config), 0644))
+
+			got, err := inferOrgPolicyChecks(bpPath)
+			require.NoError(t, err)
+			var ids []string
+			for _, c := range got {
+				assert.Empty(t, c.RequiredValues)
+				ids = append(ids, c.PolicyId)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
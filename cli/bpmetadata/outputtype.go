@@ -0,0 +1,734 @@
+package bpmetadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	outputTypeSourceState  = "state"
+	outputTypeSourceStatic = "static"
+
+	// maxStaticModuleDepth limits how deep local module calls are followed
+	maxStaticModuleDepth = 5
+)
+
+var (
+	// functions returning a fixed type regardless of their arguments
+	staticFuncTypes = map[string]cty.Type{
+		"tostring": cty.String, "format": cty.String, "join": cty.String, "lower": cty.String, "upper": cty.String,
+		"replace": cty.String, "trimspace": cty.String, "trim": cty.String, "trimprefix": cty.String, "trimsuffix": cty.String,
+		"title": cty.String, "substr": cty.String, "jsonencode": cty.String, "yamlencode": cty.String, "base64encode": cty.String,
+		"base64decode": cty.String, "md5": cty.String, "sha256": cty.String, "uuid": cty.String, "templatefile": cty.String,
+		"file": cty.String, "cidrsubnet": cty.String, "cidrhost": cty.String, "formatdate": cty.String, "timestamp": cty.String,
+		"tonumber": cty.Number, "length": cty.Number, "max": cty.Number, "min": cty.Number, "abs": cty.Number,
+		"ceil": cty.Number, "floor": cty.Number, "parseint": cty.Number, "sum": cty.Number,
+		"tobool": cty.Bool, "contains": cty.Bool, "can": cty.Bool, "alltrue": cty.Bool, "anytrue": cty.Bool,
+		"startswith": cty.Bool, "endswith": cty.Bool, "fileexists": cty.Bool,
+		"keys": cty.List(cty.String), "split": cty.List(cty.String), "formatlist": cty.List(cty.String),
+		"range": cty.List(cty.Number), "transpose": cty.Map(cty.List(cty.String)),
+	}
+
+	// functions returning a value of the same type as their first argument
+	passthroughFuncs = []string{"coalesce", "try", "sort", "reverse", "distinct", "compact", "slice", "nonsensitive", "sensitive"}
+
+	// resource attributes that are strings regardless of resource type
+	staticStringResourceAttrs = []string{"id", "name", "self_link", "email", "project", "location", "region", "zone", "url", "uri", "etag", "network", "member", "unique_id", "fingerprint"}
+)
+
+// OutputTypeOptions configures InferOutputTypes.
+type OutputTypeOptions struct {
+	// Schemas are provider schemas, as generated by `terraform providers schema -json`, used to
+	// resolve resource attribute types. Without schemas only attributes common to all
+	// resources are resolved.
+	Schemas *tfjson.ProviderSchemas
+	// MetadataTypes prefers output types recorded in the metadata.yaml of a TF config, if any,
+	// over inferred types.
+	MetadataTypes bool
+}
+
+// InferOutputTypes infers the types of all outputs of the TF config in dir, following
+// calls to local modules, from their HCL expressions without deploying the config.
+// Output types set with a type argument take precedence. Types that can not be inferred,
+// or parts of them, are cty.DynamicPseudoType.
+func InferOutputTypes(dir string, opts OutputTypeOptions) (map[string]cty.Type, error) {
+	s := newStaticTypeInferrer(opts.Schemas)
+	s.metadataTypes = opts.MetadataTypes
+	return s.outputTypes(dir, 0)
+}
+
+// staticResource is a managed or data resource declared in a TF config.
+type staticResource struct {
+	resourceType string
+	data         bool
+	count        bool
+	forEach      bool
+}
+
+// staticModuleCall is a module call declared in a TF config.
+type staticModuleCall struct {
+	source  string
+	count   bool
+	forEach bool
+}
+
+// staticModule holds the parts of a TF config needed to infer output types.
+type staticModule struct {
+	dir         string
+	depth       int
+	variables   map[string]cty.Type
+	locals      map[string]hclsyntax.Expression
+	localTypes  map[string]cty.Type
+	resolving   map[string]bool
+	outputs     map[string]hclsyntax.Expression
+	outputTypes map[string]cty.Type
+	resources   map[string]staticResource
+	moduleCalls map[string]staticModuleCall
+}
+
+// staticTypeInferrer infers output types from HCL expressions without deploying the blueprint.
+type staticTypeInferrer struct {
+	schemas       *tfjson.ProviderSchemas
+	metadataTypes bool
+	cache         map[string]map[string]cty.Type
+}
+
+func newStaticTypeInferrer(schemas *tfjson.ProviderSchemas) *staticTypeInferrer {
+	return &staticTypeInferrer{schemas: schemas, cache: make(map[string]map[string]cty.Type)}
+}
+
+// updateOutputTypesStatic infers output types from the TF config at bpPath and updates
+// the output types in the provided BlueprintInterface. Resource attribute types are
+// resolved from the provider schema JSON at schemaPath if set, as generated by
+// `terraform providers schema -json`. Outputs that can not be inferred fall back to any.
+func updateOutputTypesStatic(bpPath, schemaPath string, bpInterfaces *BlueprintInterface) error {
+	var schemas *tfjson.ProviderSchemas
+	if schemaPath != "" {
+		var err error
+		schemas, err = loadProviderSchemas(schemaPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	outputTypes, err := InferOutputTypes(bpPath, OutputTypeOptions{Schemas: schemas})
+	if err != nil {
+		return fmt.Errorf("error inferring output types: %w", err)
+	}
+
+	for i, output := range bpInterfaces.Outputs {
+		t, ok := outputTypes[output.Name]
+		if !ok {
+			continue
+		}
+		if t == cty.DynamicPseudoType {
+			Log.Warn("unable to infer type for output, falling back to any", "output", output.Name)
+		} else if t.HasDynamicTypes() {
+			Log.Warn("unable to fully infer type for output, using any for unknown parts", "output", output.Name)
+		}
+		v, err := ctyTypeToValue(t)
+		if err != nil {
+			return fmt.Errorf("error converting type for output %s: %w", output.Name, err)
+		}
+		bpInterfaces.Outputs[i].Type = v
+	}
+	return nil
+}
+
+// loadProviderSchemas loads provider schemas from a JSON file.
+func loadProviderSchemas(schemaPath string) (*tfjson.ProviderSchemas, error) {
+	b, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading provider schema: %w", err)
+	}
+	schemas := &tfjson.ProviderSchemas{}
+	if err := json.Unmarshal(b, schemas); err != nil {
+		return nil, fmt.Errorf("error parsing provider schema %s: %w", schemaPath, err)
+	}
+	return schemas, nil
+}
+
+// ctyTypeToValue converts a cty type into its JSON representation as a structpb.Value.
+func ctyTypeToValue(t cty.Type) (*structpb.Value, error) {
+	b, err := ctyjson.MarshalType(t)
+	if err != nil {
+		return nil, err
+	}
+	v := &structpb.Value{}
+	if err := v.UnmarshalJSON(b); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// outputTypes returns the inferred types of all outputs for the TF config in dir.
+// Explicit output types take precedence, followed by metadata types if enabled.
+func (s *staticTypeInferrer) outputTypes(dir string, depth int) (map[string]cty.Type, error) {
+	dir = filepath.Clean(dir)
+	if types, ok := s.cache[dir]; ok {
+		return types, nil
+	}
+	mod, err := loadStaticModule(dir, depth)
+	if err != nil {
+		return nil, err
+	}
+	var mdTypes map[string]cty.Type
+	if s.metadataTypes {
+		mdTypes = metadataOutputTypes(dir)
+	}
+
+	types := make(map[string]cty.Type, len(mod.outputs))
+	// store early to guard against cyclic module calls
+	s.cache[dir] = types
+	for name, expr := range mod.outputs {
+		if t, ok := mod.outputTypes[name]; ok {
+			types[name] = t
+			continue
+		}
+		if t, ok := mdTypes[name]; ok {
+			types[name] = t
+			continue
+		}
+		types[name] = s.inferExprType(expr, mod)
+	}
+	return types, nil
+}
+
+// metadataOutputTypes returns the output types recorded in the metadata in dir, if any.
+func metadataOutputTypes(dir string) map[string]cty.Type {
+	types := make(map[string]cty.Type)
+	md, err := UnmarshalMetadata(dir, metadataFileName)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			Log.Warn("unable to read output types from metadata", "path", dir, "error", err)
+		}
+		return types
+	}
+	for _, o := range md.GetSpec().GetInterfaces().GetOutputs() {
+		if t, ok := outputCtyType(o); ok {
+			types[o.Name] = t
+		}
+	}
+	return types
+}
+
+// loadStaticModule parses the TF config in dir.
+func loadStaticModule(dir string, depth int) (*staticModule, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	mod := &staticModule{
+		dir:         dir,
+		depth:       depth,
+		variables:   make(map[string]cty.Type),
+		locals:      make(map[string]hclsyntax.Expression),
+		localTypes:  make(map[string]cty.Type),
+		resolving:   make(map[string]bool),
+		outputs:     make(map[string]hclsyntax.Expression),
+		outputTypes: make(map[string]cty.Type),
+		resources:   make(map[string]staticResource),
+		moduleCalls: make(map[string]staticModuleCall),
+	}
+	p := hclparse.NewParser()
+	for _, f := range files {
+		file, diags := p.ParseHCLFile(f)
+		if err := hasHclErrors(diags); err != nil {
+			return nil, err
+		}
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			mod.addBlock(block)
+		}
+	}
+	return mod, nil
+}
+
+// addBlock records a top level block of a TF config.
+func (m *staticModule) addBlock(block *hclsyntax.Block) {
+	attrs := block.Body.Attributes
+	_, count := attrs["count"]
+	_, forEach := attrs["for_each"]
+	switch {
+	case block.Type == "variable" && len(block.Labels) == 1:
+		m.variables[block.Labels[0]] = cty.DynamicPseudoType
+		if typeAttr, ok := attrs["type"]; ok {
+			if t, diags := typeexpr.TypeConstraint(typeAttr.Expr); !diags.HasErrors() {
+				m.variables[block.Labels[0]] = t
+			}
+		}
+	case block.Type == "locals":
+		for name, attr := range attrs {
+			m.locals[name] = attr.Expr
+		}
+	case block.Type == "output" && len(block.Labels) == 1:
+		if valueAttr, ok := attrs["value"]; ok {
+			m.outputs[block.Labels[0]] = valueAttr.Expr
+		}
+		if typeAttr, ok := attrs["type"]; ok {
+			if t, diags := typeexpr.TypeConstraint(typeAttr.Expr); !diags.HasErrors() {
+				m.outputTypes[block.Labels[0]] = t
+			}
+		}
+	case block.Type == "resource" && len(block.Labels) == 2:
+		m.resources[block.Labels[0]+"."+block.Labels[1]] = staticResource{resourceType: block.Labels[0], count: count, forEach: forEach}
+	case block.Type == "data" && len(block.Labels) == 2:
+		m.resources["data."+block.Labels[0]+"."+block.Labels[1]] = staticResource{resourceType: block.Labels[0], data: true, count: count, forEach: forEach}
+	case block.Type == "module" && len(block.Labels) == 1:
+		call := staticModuleCall{count: count, forEach: forEach}
+		if sourceAttr, ok := attrs["source"]; ok {
+			if v, diags := sourceAttr.Expr.Value(nil); !diags.HasErrors() && v.Type() == cty.String {
+				call.source = v.AsString()
+			}
+		}
+		m.moduleCalls[block.Labels[0]] = call
+	}
+}
+
+// inferExprType infers the type of an expression within mod.
+func (s *staticTypeInferrer) inferExprType(expr hclsyntax.Expression, mod *staticModule) cty.Type {
+	switch e := expr.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		return e.Val.Type()
+	case *hclsyntax.TemplateExpr:
+		return cty.String
+	case *hclsyntax.TemplateWrapExpr:
+		return s.inferExprType(e.Wrapped, mod)
+	case *hclsyntax.ParenthesesExpr:
+		return s.inferExprType(e.Expression, mod)
+	case *hclsyntax.BinaryOpExpr:
+		return e.Op.Type
+	case *hclsyntax.UnaryOpExpr:
+		return e.Op.Type
+	case *hclsyntax.TupleConsExpr:
+		elems := make([]cty.Type, 0, len(e.Exprs))
+		for _, ex := range e.Exprs {
+			elems = append(elems, s.inferExprType(ex, mod))
+		}
+		return cty.Tuple(elems)
+	case *hclsyntax.ObjectConsExpr:
+		attrs := make(map[string]cty.Type, len(e.Items))
+		for _, item := range e.Items {
+			key, ok := objectKey(item.KeyExpr)
+			if !ok {
+				return cty.DynamicPseudoType
+			}
+			attrs[key] = s.inferExprType(item.ValueExpr, mod)
+		}
+		return cty.Object(attrs)
+	case *hclsyntax.ConditionalExpr:
+		trueType := s.inferExprType(e.TrueResult, mod)
+		falseType := s.inferExprType(e.FalseResult, mod)
+		switch {
+		case trueType.Equals(falseType):
+			return trueType
+		case isNullLiteral(e.FalseResult):
+			return trueType
+		case isNullLiteral(e.TrueResult):
+			return falseType
+		}
+		return cty.DynamicPseudoType
+	case *hclsyntax.FunctionCallExpr:
+		return s.inferFuncType(e, mod)
+	case *hclsyntax.ForExpr:
+		valType := s.inferExprType(e.ValExpr, mod)
+		if e.KeyExpr != nil {
+			if e.Group {
+				return cty.Map(cty.List(valType))
+			}
+			return cty.Map(valType)
+		}
+		return cty.List(valType)
+	case *hclsyntax.SplatExpr:
+		elemType := collectionElemType(s.inferExprType(e.Source, mod))
+		return cty.List(s.inferEachType(e.Each, elemType, mod))
+	case *hclsyntax.ScopeTraversalExpr:
+		return s.inferTraversalType(e.Traversal, mod)
+	case *hclsyntax.RelativeTraversalExpr:
+		return applyTraversal(s.inferExprType(e.Source, mod), e.Traversal)
+	case *hclsyntax.IndexExpr:
+		collType := s.inferExprType(e.Collection, mod)
+		key, diags := e.Key.Value(nil)
+		if diags.HasErrors() {
+			return collectionElemType(collType)
+		}
+		return applyTraversal(collType, hcl.Traversal{hcl.TraverseIndex{Key: key}})
+	}
+	return cty.DynamicPseudoType
+}
+
+// inferEachType infers the type of a splat expression applied to each element of elemType.
+func (s *staticTypeInferrer) inferEachType(each hclsyntax.Expression, elemType cty.Type, mod *staticModule) cty.Type {
+	switch e := each.(type) {
+	case *hclsyntax.AnonSymbolExpr:
+		return elemType
+	case *hclsyntax.RelativeTraversalExpr:
+		return applyTraversal(s.inferEachType(e.Source, elemType, mod), e.Traversal)
+	case *hclsyntax.IndexExpr:
+		return collectionElemType(s.inferEachType(e.Collection, elemType, mod))
+	}
+	return cty.DynamicPseudoType
+}
+
+// inferFuncType infers the return type of a function call.
+func (s *staticTypeInferrer) inferFuncType(e *hclsyntax.FunctionCallExpr, mod *staticModule) cty.Type {
+	if t, ok := staticFuncTypes[e.Name]; ok {
+		return t
+	}
+	var argType cty.Type
+	if len(e.Args) > 0 {
+		argType = s.inferExprType(e.Args[0], mod)
+	} else {
+		argType = cty.DynamicPseudoType
+	}
+
+	switch e.Name {
+	case "tolist", "concat", "values":
+		return cty.List(collectionElemType(argType))
+	case "flatten":
+		return cty.List(cty.DynamicPseudoType)
+	case "toset", "setunion", "setintersection", "setsubtract":
+		return cty.Set(collectionElemType(argType))
+	case "chunklist":
+		return cty.List(cty.List(collectionElemType(argType)))
+	case "tomap", "zipmap":
+		if e.Name == "zipmap" && len(e.Args) > 1 {
+			return cty.Map(collectionElemType(s.inferExprType(e.Args[1], mod)))
+		}
+		return cty.Map(collectionElemType(argType))
+	case "merge":
+		// merging objects with known attributes results in an object with all attributes
+		// while merging any map results in a map
+		attrs := make(map[string]cty.Type)
+		isMap := false
+		for _, arg := range e.Args {
+			t := s.inferExprType(arg, mod)
+			switch {
+			case t.IsMapType():
+				isMap = true
+			case t.IsObjectType():
+				for name, at := range t.AttributeTypes() {
+					attrs[name] = at
+				}
+			default:
+				return cty.DynamicPseudoType
+			}
+		}
+		if isMap {
+			return cty.Map(cty.DynamicPseudoType)
+		}
+		return cty.Object(attrs)
+	case "element", "one", "lookup":
+		return collectionElemType(argType)
+	}
+	if slices.Contains(passthroughFuncs, e.Name) {
+		return argType
+	}
+	return cty.DynamicPseudoType
+}
+
+// inferTraversalType infers the type of a reference such as var.foo, local.bar,
+// module.baz.output or google_storage_bucket.bucket.name.
+func (s *staticTypeInferrer) inferTraversalType(traversal hcl.Traversal, mod *staticModule) cty.Type {
+	root := traversal.RootName()
+	rest := traversal.SimpleSplit().Rel
+	attrName := func() (string, bool) {
+		if len(rest) == 0 {
+			return "", false
+		}
+		a, ok := rest[0].(hcl.TraverseAttr)
+		return a.Name, ok
+	}
+
+	switch root {
+	case "var":
+		name, ok := attrName()
+		if !ok {
+			return cty.DynamicPseudoType
+		}
+		t, ok := mod.variables[name]
+		if !ok {
+			return cty.DynamicPseudoType
+		}
+		return applyTraversal(t, rest[1:])
+	case "local":
+		name, ok := attrName()
+		if !ok {
+			return cty.DynamicPseudoType
+		}
+		return applyTraversal(s.localType(name, mod), rest[1:])
+	case "module":
+		name, ok := attrName()
+		if !ok {
+			return cty.DynamicPseudoType
+		}
+		return applyTraversal(s.moduleCallType(name, mod), rest[1:])
+	case "path", "terraform":
+		return cty.String
+	case "count":
+		return cty.Number
+	case "data":
+		if len(rest) < 2 {
+			return cty.DynamicPseudoType
+		}
+		typeAttr, ok1 := rest[0].(hcl.TraverseAttr)
+		nameAttr, ok2 := rest[1].(hcl.TraverseAttr)
+		if !ok1 || !ok2 {
+			return cty.DynamicPseudoType
+		}
+		r, ok := mod.resources["data."+typeAttr.Name+"."+nameAttr.Name]
+		if !ok {
+			return cty.DynamicPseudoType
+		}
+		return s.resourceRefType(r, rest[2:])
+	}
+
+	name, ok := attrName()
+	if !ok {
+		return cty.DynamicPseudoType
+	}
+	r, ok := mod.resources[root+"."+name]
+	if !ok {
+		return cty.DynamicPseudoType
+	}
+	return s.resourceRefType(r, rest[1:])
+}
+
+// resourceRefType returns the type of a reference to resource r with the remaining traversal.
+func (s *staticTypeInferrer) resourceRefType(r staticResource, rest hcl.Traversal) cty.Type {
+	objType, ok := s.resourceType(r)
+	if !ok {
+		// without a schema only attributes common to all resources are known
+		if (r.count || r.forEach) && len(rest) > 0 {
+			if _, isIndex := rest[0].(hcl.TraverseIndex); !isIndex {
+				return cty.DynamicPseudoType
+			}
+			rest = rest[1:]
+		}
+		if len(rest) == 1 {
+			if attr, isAttr := rest[0].(hcl.TraverseAttr); isAttr && slices.Contains(staticStringResourceAttrs, attr.Name) {
+				return cty.String
+			}
+		}
+		return cty.DynamicPseudoType
+	}
+	switch {
+	case r.count:
+		return applyTraversal(cty.List(objType), rest)
+	case r.forEach:
+		return applyTraversal(cty.Map(objType), rest)
+	}
+	return applyTraversal(objType, rest)
+}
+
+// resourceType returns the object type of a resource from the provider schema
+// and whether the resource type was found.
+func (s *staticTypeInferrer) resourceType(r staticResource) (cty.Type, bool) {
+	if s.schemas == nil {
+		return cty.NilType, false
+	}
+	for _, p := range s.schemas.Schemas {
+		schemas := p.ResourceSchemas
+		if r.data {
+			schemas = p.DataSourceSchemas
+		}
+		if schema, ok := schemas[r.resourceType]; ok && schema.Block != nil {
+			return blockType(schema.Block), true
+		}
+	}
+	return cty.NilType, false
+}
+
+// localType infers the type of a local value while guarding against cycles.
+func (s *staticTypeInferrer) localType(name string, mod *staticModule) cty.Type {
+	if t, ok := mod.localTypes[name]; ok {
+		return t
+	}
+	expr, ok := mod.locals[name]
+	if !ok || mod.resolving[name] {
+		return cty.DynamicPseudoType
+	}
+	mod.resolving[name] = true
+	t := s.inferExprType(expr, mod)
+	mod.resolving[name] = false
+	mod.localTypes[name] = t
+	return t
+}
+
+// moduleCallType returns an object type with the outputs of a local module call.
+func (s *staticTypeInferrer) moduleCallType(name string, mod *staticModule) cty.Type {
+	call, ok := mod.moduleCalls[name]
+	if !ok || !util.IsLocalModuleSource(call.source) || mod.depth >= maxStaticModuleDepth {
+		return cty.DynamicPseudoType
+	}
+	outputs, err := s.outputTypes(filepath.Join(mod.dir, call.source), mod.depth+1)
+	if err != nil {
+		Log.Warn("unable to infer output types for module", "module", name, "error", err)
+		return cty.DynamicPseudoType
+	}
+	objType := cty.Object(outputs)
+	switch {
+	case call.count:
+		return cty.List(objType)
+	case call.forEach:
+		return cty.Map(objType)
+	}
+	return objType
+}
+
+// blockType returns the object type for a provider schema block.
+func blockType(b *tfjson.SchemaBlock) cty.Type {
+	attrs := make(map[string]cty.Type, len(b.Attributes)+len(b.NestedBlocks))
+	for name, a := range b.Attributes {
+		attrs[name] = attributeType(a)
+	}
+	for name, nb := range b.NestedBlocks {
+		if nb.Block == nil {
+			continue
+		}
+		attrs[name] = nestingType(nb.NestingMode, blockType(nb.Block))
+	}
+	return cty.Object(attrs)
+}
+
+// attributeType returns the type for a provider schema attribute including nested attributes.
+func attributeType(a *tfjson.SchemaAttribute) cty.Type {
+	if a.AttributeNestedType == nil {
+		if a.AttributeType == cty.NilType {
+			return cty.DynamicPseudoType
+		}
+		return a.AttributeType
+	}
+	attrs := make(map[string]cty.Type, len(a.AttributeNestedType.Attributes))
+	for name, na := range a.AttributeNestedType.Attributes {
+		attrs[name] = attributeType(na)
+	}
+	return nestingType(a.AttributeNestedType.NestingMode, cty.Object(attrs))
+}
+
+// nestingType wraps t based on the nesting mode of a nested block or attribute.
+func nestingType(mode tfjson.SchemaNestingMode, t cty.Type) cty.Type {
+	switch mode {
+	case tfjson.SchemaNestingModeList:
+		return cty.List(t)
+	case tfjson.SchemaNestingModeSet:
+		return cty.Set(t)
+	case tfjson.SchemaNestingModeMap:
+		return cty.Map(t)
+	}
+	return t
+}
+
+// applyTraversal returns the type resulting from traversing t with traversal.
+func applyTraversal(t cty.Type, traversal hcl.Traversal) cty.Type {
+	for _, step := range traversal {
+		if t == cty.DynamicPseudoType {
+			return t
+		}
+		switch st := step.(type) {
+		case hcl.TraverseAttr:
+			t = traverseKey(t, st.Name)
+		case hcl.TraverseIndex:
+			if st.Key.Type() == cty.String && st.Key.IsKnown() {
+				t = traverseKey(t, st.Key.AsString())
+			} else if t.IsTupleType() && st.Key.Type() == cty.Number && st.Key.IsKnown() {
+				i, _ := st.Key.AsBigFloat().Int64()
+				elems := t.TupleElementTypes()
+				if i < 0 || int(i) >= len(elems) {
+					return cty.DynamicPseudoType
+				}
+				t = elems[i]
+			} else {
+				t = collectionElemType(t)
+			}
+		case hcl.TraverseSplat:
+			return cty.DynamicPseudoType
+		}
+	}
+	return t
+}
+
+// traverseKey returns the type of attribute or map key name within t.
+func traverseKey(t cty.Type, name string) cty.Type {
+	switch {
+	case t.IsObjectType():
+		if t.HasAttribute(name) {
+			return t.AttributeType(name)
+		}
+	case t.IsMapType():
+		return t.ElementType()
+	}
+	return cty.DynamicPseudoType
+}
+
+// collectionElemType returns the element type of a collection type or any if unknown.
+// Tuples and objects have an element type if all their elements have the same type.
+func collectionElemType(t cty.Type) cty.Type {
+	switch {
+	case t.IsListType(), t.IsSetType(), t.IsMapType():
+		return t.ElementType()
+	case t.IsTupleType():
+		return commonType(t.TupleElementTypes())
+	case t.IsObjectType():
+		var attrTypes []cty.Type
+		for _, at := range t.AttributeTypes() {
+			attrTypes = append(attrTypes, at)
+		}
+		return commonType(attrTypes)
+	}
+	return cty.DynamicPseudoType
+}
+
+// commonType returns the type shared by all types or any if they differ.
+func commonType(types []cty.Type) cty.Type {
+	if len(types) == 0 {
+		return cty.DynamicPseudoType
+	}
+	for _, t := range types[1:] {
+		if !t.Equals(types[0]) {
+			return cty.DynamicPseudoType
+		}
+	}
+	return types[0]
+}
+
+// objectKey returns the static key of an object constructor item.
+func objectKey(expr hclsyntax.Expression) (string, bool) {
+	if k, ok := expr.(*hclsyntax.ObjectConsKeyExpr); ok {
+		if name := hcl.ExprAsKeyword(k.Wrapped); name != "" && !k.ForceNonLiteral {
+			return name, true
+		}
+		expr = k.Wrapped
+	}
+	v, diags := expr.Value(nil)
+	if diags.HasErrors() || !v.IsKnown() || v.Type() != cty.String {
+		return "", false
+	}
+	return v.AsString(), true
+}
+
+// isNullLiteral returns whether expr is the null keyword.
+func isNullLiteral(expr hclsyntax.Expression) bool {
+	l, ok := expr.(*hclsyntax.LiteralValueExpr)
+	return ok && l.Val.IsNull()
+}
@@ -0,0 +1,96 @@
+package bpmetadata
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const staticOutputTypesPath = "static-output-types"
+
+func TestStaticOutputTypes(t *testing.T) {
+	withSchema := map[string]cty.Type{
+		"bucket_name":       cty.String,
+		"bucket_url":        cty.String,
+		"bucket_names":      cty.List(cty.String),
+		"bucket_versioning": cty.List(cty.Object(map[string]cty.Type{"enabled": cty.Bool})),
+		"network_id":        cty.String,
+		"network_mtu":       cty.DynamicPseudoType,
+		"prefix":            cty.String,
+		"labels":            cty.Map(cty.String),
+		"names":             cty.Set(cty.String),
+		"size":              cty.Number,
+		"summary":           cty.Object(map[string]cty.Type{"project": cty.String, "count": cty.Number, "enabled": cty.Bool}),
+		"child_id":          cty.String,
+		"child":             cty.Object(map[string]cty.Type{"id": cty.String, "ids": cty.List(cty.String)}),
+		"remote_network":    cty.DynamicPseudoType,
+		"conditional":       cty.String,
+	}
+	withoutSchema := make(map[string]cty.Type, len(withSchema))
+	for k, v := range withSchema {
+		withoutSchema[k] = v
+	}
+	withoutSchema["bucket_names"] = cty.List(cty.DynamicPseudoType)
+	withoutSchema["bucket_versioning"] = cty.DynamicPseudoType
+
+	tests := []struct {
+		name       string
+		schemaFile string
+		want       map[string]cty.Type
+	}{
+		{
+			name:       "with provider schema",
+			schemaFile: "provider-schema.json",
+			want:       withSchema,
+		},
+		{
+			name: "without provider schema",
+			want: withoutSchema,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bpPath := path.Join(tfTestdataPath, staticOutputTypesPath)
+			inferrer := newStaticTypeInferrer(nil)
+			if tt.schemaFile != "" {
+				schemas, err := loadProviderSchemas(path.Join(bpPath, tt.schemaFile))
+				assert.NoError(t, err)
+				inferrer = newStaticTypeInferrer(schemas)
+			}
+			got, err := inferrer.outputTypes(bpPath, 0)
+			assert.NoError(t, err)
+			assert.Len(t, got, len(tt.want))
+			for name, want := range tt.want {
+				assert.Truef(t, want.Equals(got[name]), "output %s: want %s, got %s", name, want.GoString(), got[name].GoString())
+			}
+		})
+	}
+}
+
+func TestUpdateOutputTypesStatic(t *testing.T) {
+	bpPath := path.Join(tfTestdataPath, staticOutputTypesPath)
+	bpInterfaces := &BlueprintInterface{
+		Outputs: []*BlueprintOutput{
+			{Name: "bucket_name"},
+			{Name: "labels"},
+			{Name: "network_mtu"},
+			{Name: "not_in_tf", Type: structpb.NewStringValue("bool")},
+		},
+	}
+
+	err := updateOutputTypesStatic(bpPath, path.Join(bpPath, "provider-schema.json"), bpInterfaces)
+	assert.NoError(t, err)
+
+	var got []any
+	for _, o := range bpInterfaces.Outputs {
+		got = append(got, o.Type.AsInterface())
+	}
+	assert.Equal(t, []any{"string", []any{"map", "string"}, "dynamic", "bool"}, got)
+
+	err = updateOutputTypesStatic(bpPath, path.Join(bpPath, "missing.json"), bpInterfaces)
+	assert.ErrorContains(t, err, "error reading provider schema")
+}
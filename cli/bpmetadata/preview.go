@@ -0,0 +1,466 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var (
+	//go:embed templates/preview.html.tmpl
+	previewTemplateFiles embed.FS
+	previewTemplate      = template.Must(template.ParseFS(previewTemplateFiles, "templates/preview.html.tmpl"))
+)
+
+// Input types of previewed variables.
+const (
+	previewInputText     = "text"
+	previewInputPassword = "password"
+	previewInputNumber   = "number"
+	previewInputCheckbox = "checkbox"
+	previewInputSelect   = "select"
+	previewInputTextarea = "textarea"
+	previewInputHidden   = "hidden"
+)
+
+// previewPage is the view model of the display metadata preview.
+type previewPage struct {
+	Title string
+	// Root is the root section holding variables without a section and top level sections.
+	Root     *previewSection
+	Runtime  *previewRuntime
+	Warnings []string
+	// HasAdvanced is set if any variable is shown for an advanced level only.
+	HasAdvanced bool
+	// AltDefaultTypes are the types of alternate defaults set for any variable.
+	AltDefaultTypes []string
+}
+
+// previewSection is a section of the input form with its variables and nested sections.
+type previewSection struct {
+	Name     string
+	Title    string
+	Tooltip  string
+	Subtext  string
+	Items    []*previewItem
+	Children []*previewSection
+}
+
+// previewItem is a variable or a boolean group of variables in a section.
+type previewItem struct {
+	Variable     *previewVariable
+	BooleanGroup *BooleanGroup
+	Variables    []*previewVariable
+}
+
+// previewVariable is a form field of a variable.
+type previewVariable struct {
+	Name            string
+	Title           string
+	Tooltip         string
+	Subtext         string
+	Placeholder     string
+	Widget          string
+	Input           string
+	Value           string
+	Checked         bool
+	Required        bool
+	Options         []*ValueLabel
+	Level           int32
+	RegexValidation string
+	Validation      string
+	MinLength       int32
+	MaxLength       int32
+	MinItems        int32
+	MaxItems        int32
+	Min             float32
+	Max             float32
+	// Toggles is a JSON list of previewToggle conditions that must all be met for the
+	// variable to be visible.
+	Toggles string
+	// AltDefaults is a JSON map of alternate default types to values.
+	AltDefaults string
+}
+
+// previewToggle is a condition on the value of another variable evaluated by the preview.
+type previewToggle struct {
+	Variable string   `json:"variable"`
+	Values   []string `json:"values"`
+}
+
+// previewRuntime is the view model of the runtime outputs.
+type previewRuntime struct {
+	OutputMessage    string
+	SuggestedActions []*UIActionItem
+	Outputs          []*previewOutput
+}
+
+// previewOutput is an output shown after deployment.
+type previewOutput struct {
+	Name               string
+	Label              string
+	Description        string
+	OpenInNewTab       bool
+	ShowInNotification bool
+	Visibility         string
+}
+
+// servePreview serves a preview of the display metadata of the blueprint at bpPath
+// on addr until the server fails. Metadata is read on each request, so edits are
+// shown on reload.
+func servePreview(bpPath, addr string, w io.Writer) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", addr, err)
+	}
+	fmt.Fprintf(w, "Previewing display metadata of %s at http://%s\n", bpPath, l.Addr())
+	return http.Serve(l, newPreviewHandler(bpPath))
+}
+
+// newPreviewHandler returns a handler rendering the display metadata preview of the
+// blueprint at bpPath.
+func newPreviewHandler(bpPath string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		page, err := loadPreviewPage(bpPath)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var b bytes.Buffer
+		if err := previewTemplate.Execute(&b, page); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(b.Bytes())
+	})
+	return mux
+}
+
+// loadPreviewPage reads the metadata of the blueprint at bpPath and builds its preview.
+// Display metadata is derived from variables if it does not exist.
+func loadPreviewPage(bpPath string) (*previewPage, error) {
+	core, err := UnmarshalMetadata(bpPath, metadataFileName)
+	if err != nil {
+		return nil, fmt.Errorf("error loading metadata: %w", err)
+	}
+	display, err := UnmarshalMetadata(bpPath, metadataDisplayFileName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error loading display metadata: %w", err)
+	}
+	return buildPreviewPage(core, display)
+}
+
+// buildPreviewPage builds the preview of the display metadata of a blueprint. Issues
+// which prevent rendering display metadata as authored are reported as warnings.
+func buildPreviewPage(core, display *BlueprintMetadata) (*previewPage, error) {
+	input := display.GetSpec().GetUi().GetInput()
+	if input == nil {
+		input = &BlueprintUIInput{}
+		buildUIInputFromVariables(core.GetSpec().GetInterfaces().GetVariables(), input)
+	}
+
+	page := &previewPage{
+		Title: display.GetSpec().GetInfo().GetTitle(),
+		Root:  &previewSection{},
+	}
+	if page.Title == "" {
+		page.Title = core.GetSpec().GetInfo().GetTitle()
+	}
+
+	sections := page.buildSections(input.Sections)
+	groups := make(map[string]*BooleanGroup)
+	for _, g := range input.BooleanGroups {
+		groups[g.Name] = g
+	}
+
+	altDefaultTypes := make(map[string]bool)
+	for _, name := range previewVariableOrder(core, input) {
+		dv := input.Variables[name]
+		v, err := newPreviewVariable(dv, findVariable(core, name))
+		if err != nil {
+			return nil, err
+		}
+		if v.Level > 0 {
+			page.HasAdvanced = true
+		}
+		for _, ad := range dv.AltDefaults {
+			altDefaultTypes[altDefaultTypeName(ad.Type)] = true
+		}
+
+		s, ok := sections[dv.Section]
+		if !ok {
+			page.warn("variable %s references unknown section %s", name, dv.Section)
+			s = page.Root
+		}
+
+		if dv.BooleanGroup == "" {
+			s.Items = append(s.Items, &previewItem{Variable: v})
+			continue
+		}
+		g, ok := groups[dv.BooleanGroup]
+		if !ok {
+			page.warn("variable %s references unknown boolean group %s", name, dv.BooleanGroup)
+			s.Items = append(s.Items, &previewItem{Variable: v})
+			continue
+		}
+		s.addToBooleanGroup(g, v)
+	}
+	page.AltDefaultTypes = sortedKeys(altDefaultTypes)
+	page.Runtime = newPreviewRuntime(display.GetSpec().GetUi().GetRuntime(), core)
+
+	return page, nil
+}
+
+func (p *previewPage) warn(format string, a ...any) {
+	p.Warnings = append(p.Warnings, fmt.Sprintf(format, a...))
+}
+
+// buildSections nests sections under their parents in the order they are listed in
+// and returns all sections by name, including the root section as "".
+func (p *previewPage) buildSections(sections []*DisplaySection) map[string]*previewSection {
+	byName := map[string]*previewSection{"": p.Root}
+	for _, s := range sections {
+		if _, ok := byName[s.Name]; ok {
+			p.warn("section %s is defined more than once", s.Name)
+			continue
+		}
+		byName[s.Name] = &previewSection{
+			Name:    s.Name,
+			Title:   s.Title,
+			Tooltip: s.Tooltip,
+			Subtext: s.Subtext,
+		}
+	}
+
+	children := make(map[string][]*previewSection)
+	for _, s := range sections {
+		parent := s.Parent
+		if _, ok := byName[parent]; !ok {
+			p.warn("section %s references unknown parent section %s", s.Name, parent)
+			parent = ""
+		}
+		children[parent] = append(children[parent], byName[s.Name])
+	}
+
+	// attach sections reachable from the root, cycles of parents are attached to the root
+	attached := map[string]bool{"": true}
+	var attach func(s *previewSection)
+	attach = func(s *previewSection) {
+		for _, c := range children[s.Name] {
+			if attached[c.Name] {
+				continue
+			}
+			attached[c.Name] = true
+			s.Children = append(s.Children, c)
+			attach(c)
+		}
+	}
+	attach(p.Root)
+	for _, s := range sections {
+		if !attached[s.Name] {
+			p.warn("section %s is part of a cycle of parent sections", s.Name)
+			attached[s.Name] = true
+			p.Root.Children = append(p.Root.Children, byName[s.Name])
+			attach(byName[s.Name])
+		}
+	}
+
+	return byName
+}
+
+// addToBooleanGroup adds v to the item of boolean group g in the section.
+func (s *previewSection) addToBooleanGroup(g *BooleanGroup, v *previewVariable) {
+	for _, item := range s.Items {
+		if item.BooleanGroup == g {
+			item.Variables = append(item.Variables, v)
+			return
+		}
+	}
+	s.Items = append(s.Items, &previewItem{BooleanGroup: g, Variables: []*previewVariable{v}})
+}
+
+// previewVariableOrder returns the names of display variables in the order of the
+// blueprint variables followed by display variables without a blueprint variable.
+func previewVariableOrder(core *BlueprintMetadata, input *BlueprintUIInput) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, v := range core.GetSpec().GetInterfaces().GetVariables() {
+		if _, ok := input.Variables[v.Name]; ok {
+			names = append(names, v.Name)
+			seen[v.Name] = true
+		}
+	}
+	for _, name := range sortedKeys(input.Variables) {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// findVariable returns the blueprint variable with name or nil if there is none.
+func findVariable(core *BlueprintMetadata, name string) *BlueprintVariable {
+	for _, v := range core.GetSpec().GetInterfaces().GetVariables() {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// newPreviewVariable creates the form field of display variable dv for variable bv.
+func newPreviewVariable(dv *DisplayVariable, bv *BlueprintVariable) (*previewVariable, error) {
+	v := &previewVariable{
+		Name:            dv.Name,
+		Title:           dv.Title,
+		Tooltip:         dv.Tooltip,
+		Subtext:         dv.Subtext,
+		Placeholder:     dv.Placeholder,
+		Required:        bv.GetRequired(),
+		Options:         dv.EnumValueLabels,
+		Level:           dv.Level,
+		RegexValidation: dv.RegexValidation,
+		Validation:      dv.Validation,
+		MinLength:       dv.MinLength,
+		MaxLength:       dv.MaxLength,
+		MinItems:        dv.MinItems,
+		MaxItems:        dv.MaxItems,
+		Min:             dv.Min,
+		Max:             dv.Max,
+	}
+	if v.Title == "" {
+		v.Title = dv.Name
+	}
+	if dv.XGoogleProperty != nil {
+		v.Widget = dv.XGoogleProperty.Type.String()
+	}
+
+	varType := normalizeVarType(bv.GetVarType())
+	switch {
+	case dv.Invisible:
+		v.Input = previewInputHidden
+	case len(dv.EnumValueLabels) > 0:
+		v.Input = previewInputSelect
+	case varType == "bool":
+		v.Input = previewInputCheckbox
+	case varType == "number":
+		v.Input = previewInputNumber
+	case varType == "string" && bv.GetSensitive():
+		v.Input = previewInputPassword
+	case varType == "string":
+		v.Input = previewInputText
+	default:
+		v.Input = previewInputTextarea
+	}
+
+	value, err := previewValue(bv.GetDefaultValue().AsInterface())
+	if err != nil {
+		return nil, fmt.Errorf("error converting default value of variable %s: %w", dv.Name, err)
+	}
+	v.Value = value
+	v.Checked = value == "true"
+
+	var toggles []previewToggle
+	for _, t := range append([]*DisplayVariableToggle{dv.ToggleUsingVariable}, dv.ToggleUsingVariables...) {
+		if t == nil {
+			continue
+		}
+		values := t.VariableValues
+		if t.VariableValue != "" {
+			values = append([]string{t.VariableValue}, values...)
+		}
+		if len(values) == 0 && t.Type == DisplayVariableToggle_DISPLAY_VARIABLE_TOGGLE_TYPE_BOOLEAN {
+			values = []string{"true"}
+		}
+		toggles = append(toggles, previewToggle{Variable: t.VariableName, Values: values})
+	}
+	if len(toggles) > 0 {
+		b, err := json.Marshal(toggles)
+		if err != nil {
+			return nil, err
+		}
+		v.Toggles = string(b)
+	}
+
+	if len(dv.AltDefaults) > 0 {
+		altDefaults := make(map[string]string)
+		for _, ad := range dv.AltDefaults {
+			value, err := previewValue(ad.Value.AsInterface())
+			if err != nil {
+				return nil, fmt.Errorf("error converting alternate default of variable %s: %w", dv.Name, err)
+			}
+			altDefaults[altDefaultTypeName(ad.Type)] = value
+		}
+		b, err := json.Marshal(altDefaults)
+		if err != nil {
+			return nil, err
+		}
+		v.AltDefaults = string(b)
+	}
+
+	return v, nil
+}
+
+// previewValue formats a variable value as a form field value. Complex values are
+// formatted as JSON.
+func previewValue(value any) (string, error) {
+	switch val := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case bool, float64:
+		return fmt.Sprint(val), nil
+	default:
+		b, err := json.Marshal(val)
+		return string(b), err
+	}
+}
+
+// altDefaultTypeName returns a readable name of an alternate default type e.g. "security".
+func altDefaultTypeName(t DisplayVariable_AlternateDefault_AlternateType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "ALTERNATE_TYPE_"))
+}
+
+// newPreviewRuntime creates the preview of runtime outputs, if any.
+func newPreviewRuntime(runtime *BlueprintUIOutput, core *BlueprintMetadata) *previewRuntime {
+	if runtime == nil {
+		return nil
+	}
+
+	descriptions := make(map[string]string)
+	for _, o := range core.GetSpec().GetInterfaces().GetOutputs() {
+		descriptions[o.Name] = o.Description
+	}
+
+	r := &previewRuntime{
+		OutputMessage:    runtime.OutputMessage,
+		SuggestedActions: runtime.SuggestedActions,
+	}
+	for _, name := range sortedKeys(runtime.Outputs) {
+		o := runtime.Outputs[name]
+		po := &previewOutput{
+			Name:               name,
+			Label:              o.Label,
+			Description:        descriptions[name],
+			OpenInNewTab:       o.OpenInNewTab,
+			ShowInNotification: o.ShowInNotification,
+		}
+		if o.Visibility == DisplayOutput_VISIBILITY_ROOT {
+			po.Visibility = "root"
+		}
+		r.Outputs = append(r.Outputs, po)
+	}
+	return r
+}
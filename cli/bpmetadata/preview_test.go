@@ -0,0 +1,223 @@
+package bpmetadata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	previewCoreMetadata = `apiVersion: blueprints.cloud.google.com/v1alpha1
+kind: BlueprintMetadata
+metadata:
+  name: preview
+spec:
+  info:
+    title: Preview
+  interfaces:
+    variables:
+      - name: project_id
+        varType: string
+        required: true
+      - name: enable_ha
+        varType: bool
+        defaultValue: false
+      - name: replicas
+        varType: number
+        defaultValue: 1
+      - name: zones
+        varType: list(string)
+        defaultValue: [us-east1-b]
+      - name: enable_logs
+        varType: bool
+      - name: enable_metrics
+        varType: bool
+    outputs:
+      - name: url
+        description: Service URL
+`
+	previewDisplayMetadata = `apiVersion: blueprints.cloud.google.com/v1alpha1
+kind: BlueprintMetadata
+metadata:
+  name: preview-display
+spec:
+  info:
+    title: Preview Display
+  ui:
+    input:
+      sections:
+        - name: ha
+          title: High availability
+          parent: main
+        - name: main
+          title: Main
+        - name: orphan
+          parent: unknown
+      booleanGroups:
+        - name: observability
+          title: Observability
+      variables:
+        project_id:
+          name: project_id
+          title: Project ID
+          section: main
+          regexValidation: ^[a-z][a-z0-9-]{4,28}[a-z0-9]$
+          validation: Invalid project ID
+        enable_ha:
+          name: enable_ha
+          title: Enable HA
+          section: ha
+        replicas:
+          name: replicas
+          title: Replicas
+          section: ha
+          level: 1
+          toggleUsingVariables:
+            - variableName: enable_ha
+              type: DISPLAY_VARIABLE_TOGGLE_TYPE_BOOLEAN
+          altDefaults:
+            - type: ALTERNATE_TYPE_SECURITY
+              value: 3
+        zones:
+          name: zones
+          title: Zones
+          section: missing
+        enable_logs:
+          name: enable_logs
+          title: Logs
+          booleanGroup: observability
+        enable_metrics:
+          name: enable_metrics
+          title: Metrics
+          booleanGroup: observability
+    runtime:
+      outputMessage: Deployed
+      outputs:
+        url:
+          label: Open service
+          openInNewTab: true
+`
+)
+
+func loadPreviewTestMetadata(t *testing.T) (core, display *BlueprintMetadata) {
+	t.Helper()
+	core, display = &BlueprintMetadata{}, &BlueprintMetadata{}
+	require.NoError(t, unmarshalMetadataBytes([]byte(previewCoreMetadata), core))
+	require.NoError(t, unmarshalMetadataBytes([]byte(previewDisplayMetadata), display))
+	return core, display
+}
+
+func TestBuildPreviewPage(t *testing.T) {
+	core, display := loadPreviewTestMetadata(t)
+
+	page, err := buildPreviewPage(core, display)
+	require.NoError(t, err)
+	assert.Equal(t, "Preview Display", page.Title)
+	assert.True(t, page.HasAdvanced)
+	assert.Equal(t, []string{"security"}, page.AltDefaultTypes)
+	assert.Equal(t, []string{
+		"section orphan references unknown parent section unknown",
+		"variable zones references unknown section missing",
+	}, page.Warnings)
+
+	// sections are nested by parent in the order they are listed
+	require.Len(t, page.Root.Children, 2)
+	main := page.Root.Children[0]
+	assert.Equal(t, "main", main.Name)
+	assert.Equal(t, "orphan", page.Root.Children[1].Name)
+	require.Len(t, main.Children, 1)
+	ha := main.Children[0]
+	assert.Equal(t, "ha", ha.Name)
+
+	// variables are ordered by the blueprint variables
+	require.Len(t, ha.Items, 2)
+	assert.Equal(t, "enable_ha", ha.Items[0].Variable.Name)
+	assert.Equal(t, previewInputCheckbox, ha.Items[0].Variable.Input)
+	replicas := ha.Items[1].Variable
+	assert.Equal(t, previewInputNumber, replicas.Input)
+	assert.Equal(t, "1", replicas.Value)
+	assert.JSONEq(t, `[{"variable": "enable_ha", "values": ["true"]}]`, replicas.Toggles)
+	assert.JSONEq(t, `{"security": "3"}`, replicas.AltDefaults)
+
+	// variables with unknown sections and boolean groups are shown in the root section
+	require.Len(t, page.Root.Items, 2)
+	zones := page.Root.Items[0].Variable
+	assert.Equal(t, previewInputTextarea, zones.Input)
+	assert.Equal(t, `["us-east1-b"]`, zones.Value)
+	group := page.Root.Items[1]
+	assert.Equal(t, "Observability", group.BooleanGroup.Title)
+	require.Len(t, group.Variables, 2)
+	assert.Equal(t, "enable_logs", group.Variables[0].Name)
+	assert.Equal(t, "enable_metrics", group.Variables[1].Name)
+
+	project := main.Items[0].Variable
+	assert.True(t, project.Required)
+	assert.Equal(t, "Invalid project ID", project.Validation)
+
+	require.NotNil(t, page.Runtime)
+	require.Len(t, page.Runtime.Outputs, 1)
+	assert.Equal(t, &previewOutput{Name: "url", Label: "Open service", Description: "Service URL", OpenInNewTab: true}, page.Runtime.Outputs[0])
+}
+
+func TestBuildPreviewPageSectionCycle(t *testing.T) {
+	core, _ := loadPreviewTestMetadata(t)
+	display := &BlueprintMetadata{Spec: &BlueprintMetadataSpec{Ui: &BlueprintUI{Input: &BlueprintUIInput{
+		Sections: []*DisplaySection{{Name: "a", Parent: "b"}, {Name: "b", Parent: "a"}},
+	}}}}
+
+	page, err := buildPreviewPage(core, display)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"section a is part of a cycle of parent sections"}, page.Warnings)
+	require.Len(t, page.Root.Children, 1)
+	assert.Equal(t, "a", page.Root.Children[0].Name)
+	require.Len(t, page.Root.Children[0].Children, 1)
+	assert.Equal(t, "b", page.Root.Children[0].Children[0].Name)
+}
+
+func TestNewPreviewVariableSensitive(t *testing.T) {
+	v, err := newPreviewVariable(&DisplayVariable{Name: "password"}, &BlueprintVariable{Name: "password", VarType: "string", Sensitive: true})
+	require.NoError(t, err)
+	assert.Equal(t, previewInputPassword, v.Input)
+
+	v, err = newPreviewVariable(&DisplayVariable{Name: "keys"}, &BlueprintVariable{Name: "keys", VarType: "list(string)", Sensitive: true})
+	require.NoError(t, err)
+	assert.Equal(t, previewInputTextarea, v.Input)
+}
+
+func TestPreviewHandler(t *testing.T) {
+	bpPath := t.TempDir()
+	srv := httptest.NewServer(newPreviewHandler(bpPath))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	// display metadata is derived from variables if it does not exist
+	require.NoError(t, os.WriteFile(path.Join(bpPath, metadataFileName), []byte(previewCoreMetadata), 0644))
+	rec := httptest.NewRecorder()
+	newPreviewHandler(bpPath).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `<h1>Preview</h1>`)
+	assert.Contains(t, rec.Body.String(), `<label for="var-project_id">Project Id *</label>`)
+
+	// metadata is read on each request
+	require.NoError(t, os.WriteFile(path.Join(bpPath, metadataDisplayFileName), []byte(previewDisplayMetadata), 0644))
+	rec = httptest.NewRecorder()
+	newPreviewHandler(bpPath).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `<h1>Preview Display</h1>`)
+	assert.Contains(t, body, `<legend>High availability</legend>`)
+	assert.Contains(t, body, `data-regex="^[a-z][a-z0-9-]{4,28}[a-z0-9]$"`)
+	assert.Contains(t, body, `data-toggles="[{&#34;variable&#34;:&#34;enable_ha&#34;,&#34;values&#34;:[&#34;true&#34;]}]"`)
+	assert.Contains(t, body, `<option value="security">security</option>`)
+	assert.Contains(t, body, "variable zones references unknown section missing")
+	assert.Contains(t, body, "<td>Open service</td>")
+}
@@ -0,0 +1,225 @@
+package bpmetadata
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/protobuf/proto"
+)
+
+// Keys of BlueprintQuotaDetail.QuotaType.
+const (
+	quotaTypeMachineType = "MACHINE_TYPE"
+	quotaTypeCPUs        = "CPUs"
+	quotaTypeDiskType    = "DISK_TYPE"
+	quotaTypeSizeGB      = "SIZE_GB"
+	quotaTypeGPUType     = "GPU_TYPE"
+	quotaTypeGPUs        = "GPUs"
+)
+
+var (
+	// predefined machine types e.g. n2-standard-8 or c3-highmem-4-lssd
+	predefinedMachineTypeRegEx = regexp.MustCompile(`^[a-z][a-z0-9]*-[a-z]+-([0-9]+)(-[a-z]+)?$`)
+	// custom machine types e.g. custom-4-8192 or n2-custom-4-8192-ext
+	customMachineTypeRegEx = regexp.MustCompile(`^([a-z][a-z0-9]*-)?custom-([0-9]+)-[0-9]+(-ext)?$`)
+)
+
+// quotaValue is the value of a resource attribute relevant to quota.
+type quotaValue struct {
+	key   string
+	value string
+	// variable is the variable the attribute is set from, if any
+	variable string
+}
+
+// inferQuotasAndPolicies proposes quota details and org policy checks from the resources
+// of the TF config at bpPath. Quota details and org policy checks that are already
+// authored are not modified.
+func (i *BlueprintInfo) inferQuotasAndPolicies(bpPath string, vars []*BlueprintVariable) error {
+	if len(i.QuotaDetails) == 0 {
+		quotas, err := inferQuotaDetails(bpPath, vars)
+		if err != nil {
+			return err
+		}
+		i.QuotaDetails = quotas
+	}
+	if len(i.OrgPolicyChecks) == 0 {
+		checks, err := inferOrgPolicyChecks(bpPath)
+		if err != nil {
+			return err
+		}
+		i.OrgPolicyChecks = checks
+	}
+	return nil
+}
+
+// inferQuotaDetails proposes quota details for the compute instances and disks of the
+// TF config at bpPath. Attributes set from variables take the default value of the
+// variable, which is recorded as the dynamic variable of the quota detail.
+func inferQuotaDetails(bpPath string, vars []*BlueprintVariable) ([]*BlueprintQuotaDetail, error) {
+	defaults := make(map[string]*BlueprintVariable)
+	for _, v := range vars {
+		defaults[v.Name] = v
+	}
+
+	var details []*BlueprintQuotaDetail
+	add := func(d *BlueprintQuotaDetail) {
+		if d == nil {
+			return
+		}
+		for _, existing := range details {
+			if proto.Equal(existing, d) {
+				return
+			}
+		}
+		details = append(details, d)
+	}
+	err := forEachBlock(bpPath, "resource", 2, func(block *hclsyntax.Block, _ []byte) {
+		switch block.Labels[0] {
+		case "google_compute_instance":
+			add(instanceQuotaDetail(block.Body, defaults))
+			if disk := nestedBlock(block.Body, "boot_disk", "initialize_params"); disk != nil {
+				add(newQuotaDetail(QuotaResourceType_QRT_RESOURCE_TYPE_GCE_DISK,
+					quotaAttr(disk, "size", quotaTypeSizeGB, defaults),
+					quotaAttr(disk, "type", quotaTypeDiskType, defaults),
+				))
+			}
+		case "google_compute_disk", "google_compute_region_disk":
+			add(newQuotaDetail(QuotaResourceType_QRT_RESOURCE_TYPE_GCE_DISK,
+				quotaAttr(block.Body, "size", quotaTypeSizeGB, defaults),
+				quotaAttr(block.Body, "type", quotaTypeDiskType, defaults),
+			))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(details) > 0 {
+		Log.Info("inferred quota details from resources", "count", len(details))
+	}
+	return details, nil
+}
+
+// instanceQuotaDetail returns the quota detail of a compute instance with its machine
+// type, CPUs of predefined and custom machine types and GPUs, if any.
+func instanceQuotaDetail(body *hclsyntax.Body, defaults map[string]*BlueprintVariable) *BlueprintQuotaDetail {
+	machineType := quotaAttr(body, "machine_type", quotaTypeMachineType, defaults)
+	cpus := quotaValue{key: quotaTypeCPUs}
+	if n, ok := machineTypeCPUs(machineType.value); ok {
+		cpus.value = strconv.Itoa(n)
+	}
+	values := []quotaValue{machineType, cpus}
+	if gpu := nestedBlock(body, "guest_accelerator"); gpu != nil {
+		values = append(values,
+			quotaAttr(gpu, "type", quotaTypeGPUType, defaults),
+			quotaAttr(gpu, "count", quotaTypeGPUs, defaults),
+		)
+	}
+	return newQuotaDetail(QuotaResourceType_QRT_RESOURCE_TYPE_GCE_INSTANCE, values...)
+}
+
+// newQuotaDetail returns a quota detail of the known values with the first variable any
+// value is set from as the dynamic variable. Returns nil if neither is known.
+func newQuotaDetail(resourceType QuotaResourceType, values ...quotaValue) *BlueprintQuotaDetail {
+	d := &BlueprintQuotaDetail{ResourceType: resourceType, QuotaType: make(map[string]string)}
+	for _, v := range values {
+		if v.value != "" {
+			d.QuotaType[v.key] = v.value
+		}
+		if d.DynamicVariable == "" {
+			d.DynamicVariable = v.variable
+		}
+	}
+	if len(d.QuotaType) == 0 && d.DynamicVariable == "" {
+		return nil
+	}
+	return d
+}
+
+// quotaAttr returns the value of attribute name of body for quota type key. Attributes
+// set directly from a variable take its default value. Values of other expressions
+// referencing variables, locals or resources are unknown.
+func quotaAttr(body *hclsyntax.Body, name, key string, defaults map[string]*BlueprintVariable) quotaValue {
+	qv := quotaValue{key: key}
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return qv
+	}
+
+	if e, ok := attr.Expr.(*hclsyntax.ScopeTraversalExpr); ok && len(e.Traversal) == 2 && e.Traversal.RootName() == "var" {
+		varAttr, ok := e.Traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			return qv
+		}
+		qv.variable = varAttr.Name
+		if v, ok := defaults[varAttr.Name]; ok && v.DefaultValue != nil {
+			qv.value = quotaValueString(v.DefaultValue.AsInterface())
+		}
+		return qv
+	}
+
+	if len(attr.Expr.Variables()) > 0 {
+		return qv
+	}
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !val.IsKnown() || val.IsNull() {
+		return qv
+	}
+	switch val.Type() {
+	case cty.String:
+		qv.value = val.AsString()
+	case cty.Number:
+		qv.value = val.AsBigFloat().Text('f', -1)
+	}
+	return qv
+}
+
+// quotaValueString formats a string or number default value of a variable.
+func quotaValueString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// machineTypeCPUs returns the number of vCPUs of a predefined or custom machine type.
+// Shared core machine types e.g. e2-medium are not recognized.
+func machineTypeCPUs(machineType string) (int, bool) {
+	var cpus string
+	if m := customMachineTypeRegEx.FindStringSubmatch(machineType); m != nil {
+		cpus = m[2]
+	} else if m := predefinedMachineTypeRegEx.FindStringSubmatch(machineType); m != nil {
+		cpus = m[1]
+	} else {
+		return 0, false
+	}
+	n, err := strconv.Atoi(cpus)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// nestedBlock returns the first block nested in body along the path of block types.
+func nestedBlock(body *hclsyntax.Body, types ...string) *hclsyntax.Body {
+	for _, t := range types {
+		var next *hclsyntax.Body
+		for _, b := range body.Blocks {
+			if b.Type == t {
+				next = b.Body
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		body = next
+	}
+	return body
+}
@@ -0,0 +1,150 @@
+package bpmetadata
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const quotaTestConfig = `
+resource "google_compute_instance" "vm" {
+  name         = "vm"
+  machine_type = var.machine_type
+
+  boot_disk {
+    initialize_params {
+      type = "pd-ssd"
+      size = var.disk_size
+    }
+  }
+
+  guest_accelerator {
+    type  = "nvidia-tesla-t4"
+    count = 2
+  }
+}
+
+resource "google_compute_instance" "vm2" {
+  name         = "vm2"
+  machine_type = "custom-4-8192"
+}
+
+resource "google_compute_instance" "vm3" {
+  name         = "vm3"
+  machine_type = "custom-4-8192"
+}
+
+resource "google_compute_disk" "data" {
+  name = "data"
+  type = "pd-balanced"
+  size = 100
+}
+
+resource "google_compute_disk" "from_local" {
+  name = "from-local"
+  size = local.size
+}
+
+resource "google_storage_bucket" "bucket" {
+  name     = "bucket"
+  location = "US"
+}
+`
+
+func TestInferQuotaDetails(t *testing.T) {
+	bpPath := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(bpPath, "main.tf"), []byte(quotaTestConfig), 0644))
+	vars := []*BlueprintVariable{
+		{Name: "machine_type", VarType: "string", DefaultValue: structpb.NewStringValue("n2-standard-8")},
+		{Name: "disk_size", VarType: "number"},
+	}
+
+	got, err := inferQuotaDetails(bpPath, vars)
+	require.NoError(t, err)
+	want := &BlueprintInfo{QuotaDetails: []*BlueprintQuotaDetail{
+		{
+			DynamicVariable: "machine_type",
+			ResourceType:    QuotaResourceType_QRT_RESOURCE_TYPE_GCE_INSTANCE,
+			QuotaType:       map[string]string{"MACHINE_TYPE": "n2-standard-8", "CPUs": "8", "GPU_TYPE": "nvidia-tesla-t4", "GPUs": "2"},
+		},
+		{
+			DynamicVariable: "disk_size",
+			ResourceType:    QuotaResourceType_QRT_RESOURCE_TYPE_GCE_DISK,
+			QuotaType:       map[string]string{"DISK_TYPE": "pd-ssd"},
+		},
+		{
+			ResourceType: QuotaResourceType_QRT_RESOURCE_TYPE_GCE_INSTANCE,
+			QuotaType:    map[string]string{"MACHINE_TYPE": "custom-4-8192", "CPUs": "4"},
+		},
+		{
+			ResourceType: QuotaResourceType_QRT_RESOURCE_TYPE_GCE_DISK,
+			QuotaType:    map[string]string{"DISK_TYPE": "pd-balanced", "SIZE_GB": "100"},
+		},
+	}}
+	gotInfo := &BlueprintInfo{QuotaDetails: got}
+	assert.True(t, proto.Equal(want, gotInfo), "got %v, want %v", gotInfo, want)
+}
+
+func TestMachineTypeCPUs(t *testing.T) {
+	tests := []struct {
+		machineType string
+		want        int
+		wantOK      bool
+	}{
+		{machineType: "n1-standard-8", want: 8, wantOK: true},
+		{machineType: "c3-highmem-4-lssd", want: 4, wantOK: true},
+		{machineType: "a2-highgpu-1g"},
+		{machineType: "custom-6-23040", want: 6, wantOK: true},
+		{machineType: "n2-custom-4-8192-ext", want: 4, wantOK: true},
+		{machineType: "e2-medium"},
+		{machineType: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.machineType, func(t *testing.T) {
+			got, ok := machineTypeCPUs(tt.machineType)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGenerateInferQuotas(t *testing.T) {
+	bpPath := newTestBlueprint(t)
+	require.NoError(t, os.WriteFile(path.Join(bpPath, "main.tf"), []byte(quotaTestConfig+orgPolicyTestConfig), 0644))
+	opts := Options{
+		Path:        bpPath,
+		Name:        "terraform-google-sample",
+		SourceURL:   "https://github.com/GoogleCloudPlatform/terraform-google-sample",
+		InferQuotas: true,
+	}
+
+	results, err := Generate(opts)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	info := results[0].Metadata.Spec.Info
+	assert.Len(t, info.QuotaDetails, 5)
+	assert.Len(t, info.OrgPolicyChecks, 5)
+
+	// authored values are kept on regeneration
+	md, err := UnmarshalMetadata(bpPath, metadataFileName)
+	require.NoError(t, err)
+	md.Spec.Info.QuotaDetails = md.Spec.Info.QuotaDetails[:1]
+	md.Spec.Info.QuotaDetails[0].QuotaType["MACHINE_TYPE"] = "n2-standard-16"
+	md.Spec.Info.OrgPolicyChecks = []*BlueprintOrgPolicyCheck{{PolicyId: policyVMExternalIPAccess, RequiredValues: []string{"ALLOW"}}}
+	require.NoError(t, WriteMetadata(md, bpPath, metadataFileName))
+
+	results, err = Generate(opts)
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	info = results[0].Metadata.Spec.Info
+	require.Len(t, info.QuotaDetails, 1)
+	assert.Equal(t, "n2-standard-16", info.QuotaDetails[0].QuotaType["MACHINE_TYPE"])
+	assert.True(t, proto.Equal(md.Spec.Info.OrgPolicyChecks[0], info.OrgPolicyChecks[0]))
+	assert.Len(t, info.OrgPolicyChecks, 1)
+}
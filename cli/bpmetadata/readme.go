@@ -0,0 +1,242 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Markers of the README section with inputs and outputs, shared with terraform-docs
+// so that either can maintain it.
+const (
+	readmeDocsBeginMarker = "<!-- BEGINNING OF PRE-COMMIT-TERRAFORM DOCS HOOK -->"
+	readmeDocsEndMarker   = "<!-- END OF PRE-COMMIT-TERRAFORM DOCS HOOK -->"
+)
+
+// generateReadmes renders the inputs, outputs and optionally requirements from metadata.yaml
+// of the blueprint at bpPath and its sub modules into the README.md next to it.
+// In check mode READMEs are not written, a unified diff is written to w for outdated
+// READMEs and an error is returned if any README is outdated. READMEs of sub modules
+// without docs markers are skipped.
+func generateReadmes(bpPath string, requirements, check bool, w io.Writer) error {
+	metadataFiles, err := util.FindFilesWithPattern(bpPath, `^metadata.yaml$`, []string{".terraform/"})
+	if err != nil {
+		return fmt.Errorf("error finding metadata files: %w", err)
+	}
+	if len(metadataFiles) == 0 {
+		return fmt.Errorf("no metadata found at path %s, generate it first", bpPath)
+	}
+
+	var errs []error
+	stale := 0
+	for _, f := range metadataFiles {
+		dir := filepath.Dir(f)
+		changed, err := generateReadme(dir, filepath.Clean(dir) != filepath.Clean(bpPath), requirements, check, w)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if changed {
+			stale++
+		}
+	}
+	if check && stale > 0 {
+		errs = append(errs, fmt.Errorf("README of %d blueprint(s) is out of date, run `cft blueprint metadata readme` to update it", stale))
+	}
+	return errors.Join(errs...)
+}
+
+// generateReadme updates the README of the blueprint at bpPath and returns whether it changed.
+// If optional is set, a README that is missing or has no docs markers is skipped.
+func generateReadme(bpPath string, optional, requirements, check bool, w io.Writer) (bool, error) {
+	md, err := UnmarshalMetadata(bpPath, metadataFileName)
+	if err != nil {
+		return false, fmt.Errorf("unable to read metadata for blueprint at path %s: %w", bpPath, err)
+	}
+
+	readmePath := filepath.Join(bpPath, readmeFileName)
+	info, err := os.Stat(readmePath)
+	if optional && errors.Is(err, os.ErrNotExist) {
+		Log.Info("skipping blueprint without README", "path", bpPath)
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to read readme for blueprint at path %s: %w", bpPath, err)
+	}
+	content, err := os.ReadFile(readmePath)
+	if err != nil {
+		return false, err
+	}
+	if optional && !bytes.Contains(content, []byte(readmeDocsBeginMarker)) {
+		Log.Info("skipping README without docs markers", "path", displayPath(readmePath))
+		return false, nil
+	}
+
+	updated, err := replaceReadmeDocs(content, renderReadmeDocs(md, requirements))
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", displayPath(readmePath), err)
+	}
+	if bytes.Equal(content, updated) {
+		return false, nil
+	}
+
+	if check {
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(content)),
+			B:        difflib.SplitLines(string(updated)),
+			FromFile: "a/" + displayPath(readmePath),
+			ToFile:   "b/" + displayPath(readmePath),
+			Context:  3,
+		})
+		if err != nil {
+			return false, fmt.Errorf("error creating diff for %s: %w", readmePath, err)
+		}
+		_, err = io.WriteString(w, diff)
+		return true, err
+	}
+
+	if err := os.WriteFile(readmePath, updated, info.Mode().Perm()); err != nil {
+		return false, err
+	}
+	fmt.Fprintf(w, "updated %s\n", displayPath(readmePath))
+	return true, nil
+}
+
+// replaceReadmeDocs replaces the content between the docs markers of a README with docs.
+func replaceReadmeDocs(content []byte, docs string) ([]byte, error) {
+	s := string(content)
+	begin := strings.Index(s, readmeDocsBeginMarker)
+	if begin < 0 {
+		return nil, fmt.Errorf("marker %s not found", readmeDocsBeginMarker)
+	}
+	begin += len(readmeDocsBeginMarker)
+	end := strings.Index(s[begin:], readmeDocsEndMarker)
+	if end < 0 {
+		return nil, fmt.Errorf("marker %s not found after %s", readmeDocsEndMarker, readmeDocsBeginMarker)
+	}
+	end += begin
+	return []byte(s[:begin] + "\n" + docs + "\n" + s[end:]), nil
+}
+
+// renderReadmeDocs renders markdown tables of the variables and outputs of md sorted by
+// name in the format of terraform-docs, followed by the roles and services required if
+// requirements is set.
+func renderReadmeDocs(md *BlueprintMetadata, requirements bool) string {
+	var b strings.Builder
+	b.WriteString("## Inputs\n\n")
+	vars := md.GetSpec().GetInterfaces().GetVariables()
+	if len(vars) == 0 {
+		b.WriteString("No inputs.\n")
+	} else {
+		vars = append([]*BlueprintVariable(nil), vars...)
+		sort.SliceStable(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+		b.WriteString("| Name | Description | Type | Default | Required |\n")
+		b.WriteString("|------|-------------|------|---------|:--------:|\n")
+		for _, v := range vars {
+			varType := v.VarType
+			if varType == "" {
+				varType = "any"
+			}
+			defaultValue, required := "n/a", "yes"
+			if !v.Required {
+				defaultValue, required = markdownCode(readmeDefault(v)), "no"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", markdownName(v.Name), markdownText(v.Description), markdownCode(varType), defaultValue, required)
+		}
+	}
+
+	b.WriteString("\n## Outputs\n\n")
+	outputs := md.GetSpec().GetInterfaces().GetOutputs()
+	if len(outputs) == 0 {
+		b.WriteString("No outputs.\n")
+	} else {
+		outputs = append([]*BlueprintOutput(nil), outputs...)
+		sort.SliceStable(outputs, func(i, j int) bool { return outputs[i].Name < outputs[j].Name })
+		b.WriteString("| Name | Description |\n")
+		b.WriteString("|------|-------------|\n")
+		for _, o := range outputs {
+			fmt.Fprintf(&b, "| %s | %s |\n", markdownName(o.Name), markdownText(o.Description))
+		}
+	}
+
+	if requirements {
+		b.WriteString(renderReadmeRequirements(md.GetSpec().GetRequirements()))
+	}
+	return b.String()
+}
+
+// renderReadmeRequirements renders the roles by level and the services required by a blueprint.
+func renderReadmeRequirements(r *BlueprintRequirements) string {
+	var b strings.Builder
+	b.WriteString("\n## Requirements\n")
+	if len(r.GetRoles()) == 0 && len(r.GetServices()) == 0 {
+		b.WriteString("\nNo requirements.\n")
+		return b.String()
+	}
+	if len(r.GetRoles()) > 0 {
+		b.WriteString("\n### Roles\n")
+		for _, roles := range r.Roles {
+			fmt.Fprintf(&b, "\nThe following roles must be granted at the %s level:\n\n", roles.Level)
+			for _, role := range roles.Roles {
+				fmt.Fprintf(&b, "- `%s`\n", role)
+			}
+		}
+	}
+	if len(r.GetServices()) > 0 {
+		b.WriteString("\n### APIs\n\nThe following APIs must be enabled:\n\n")
+		for _, s := range r.Services {
+			fmt.Fprintf(&b, "- `%s`\n", s)
+		}
+	}
+	return b.String()
+}
+
+// readmeDefault returns the default value of an optional variable as indented JSON
+// as rendered by terraform-docs.
+func readmeDefault(v *BlueprintVariable) string {
+	if v.DefaultValue == nil {
+		return "null"
+	}
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v.DefaultValue.AsInterface()); err != nil {
+		return "null"
+	}
+	return b.String()
+}
+
+// markdownName escapes underscores of a name as done by terraform-docs.
+func markdownName(s string) string {
+	return strings.ReplaceAll(s, "_", `\_`)
+}
+
+// markdownText formats text for a table cell, defaulting to n/a if empty.
+func markdownText(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "n/a"
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// markdownCode formats code for a table cell as inline code or as a preformatted
+// block if it spans multiple lines.
+func markdownCode(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "|", `\|`)
+	if !strings.Contains(s, "\n") {
+		return "`" + s + "`"
+	}
+	return "<pre>" + strings.ReplaceAll(s, "\n", "<br>") + "</pre>"
+}
@@ -0,0 +1,180 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const readmeTestMetadata = `apiVersion: blueprints.cloud.google.com/v1alpha1
+kind: BlueprintMetadata
+metadata:
+  name: sample
+spec:
+  interfaces:
+    variables:
+      - name: project_id
+        description: The project ID
+        varType: string
+        required: true
+      - name: labels
+        description: |-
+          Labels applied to resources.
+          Keys must be lowercase.
+        varType: map(string)
+        defaultValue:
+          env: dev
+      - name: enabled
+        varType: bool
+        defaultValue: true
+      - name: config
+        varType: |-
+          object({
+            name = string
+          })
+      - name: selector
+        description: Either a | b
+    outputs:
+      - name: service_url
+        description: URL of the service
+      - name: id
+  requirements:
+    roles:
+      - level: Project
+        roles:
+          - roles/run.admin
+    services:
+      - run.googleapis.com
+`
+
+const readmeTestDocs = "## Inputs\n" +
+	"\n" +
+	"| Name | Description | Type | Default | Required |\n" +
+	"|------|-------------|------|---------|:--------:|\n" +
+	"| config | n/a | <pre>object({<br>  name = string<br>})</pre> | `null` | no |\n" +
+	"| enabled | n/a | `bool` | `true` | no |\n" +
+	"| labels | Labels applied to resources.<br>Keys must be lowercase. | `map(string)` | <pre>{<br>  \"env\": \"dev\"<br>}</pre> | no |\n" +
+	"| project\\_id | The project ID | `string` | n/a | yes |\n" +
+	"| selector | Either a \\| b | `any` | `null` | no |\n" +
+	"\n" +
+	"## Outputs\n" +
+	"\n" +
+	"| Name | Description |\n" +
+	"|------|-------------|\n" +
+	"| id | n/a |\n" +
+	"| service\\_url | URL of the service |\n"
+
+const readmeTestRequirements = "\n" +
+	"## Requirements\n" +
+	"\n" +
+	"### Roles\n" +
+	"\n" +
+	"The following roles must be granted at the Project level:\n" +
+	"\n" +
+	"- `roles/run.admin`\n" +
+	"\n" +
+	"### APIs\n" +
+	"\n" +
+	"The following APIs must be enabled:\n" +
+	"\n" +
+	"- `run.googleapis.com`\n"
+
+func TestRenderReadmeDocs(t *testing.T) {
+	md := &BlueprintMetadata{}
+	require.NoError(t, unmarshalMetadataBytes([]byte(readmeTestMetadata), md))
+
+	assert.Equal(t, readmeTestDocs, renderReadmeDocs(md, false))
+	assert.Equal(t, readmeTestDocs+readmeTestRequirements, renderReadmeDocs(md, true))
+	assert.Equal(t, "## Inputs\n\nNo inputs.\n\n## Outputs\n\nNo outputs.\n\n## Requirements\n\nNo requirements.\n", renderReadmeDocs(&BlueprintMetadata{}, true))
+}
+
+func TestReplaceReadmeDocs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "replaces content between markers",
+			content: "# Title\n\n" + readmeDocsBeginMarker + "\nold\n" + readmeDocsEndMarker + "\n\nFooter\n",
+			want:    "# Title\n\n" + readmeDocsBeginMarker + "\nnew\n\n" + readmeDocsEndMarker + "\n\nFooter\n",
+		},
+		{
+			name:    "empty section",
+			content: readmeDocsBeginMarker + readmeDocsEndMarker,
+			want:    readmeDocsBeginMarker + "\nnew\n\n" + readmeDocsEndMarker,
+		},
+		{
+			name:    "missing begin marker",
+			content: "# Title\n" + readmeDocsEndMarker,
+			wantErr: "marker " + readmeDocsBeginMarker + " not found",
+		},
+		{
+			name:    "end marker before begin marker",
+			content: readmeDocsEndMarker + "\n" + readmeDocsBeginMarker,
+			wantErr: "marker " + readmeDocsEndMarker + " not found after",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := replaceReadmeDocs([]byte(tt.content), "new\n")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestGenerateReadmes(t *testing.T) {
+	bpPath := t.TempDir()
+	subPath := path.Join(bpPath, "modules", "sub")
+	require.NoError(t, os.MkdirAll(subPath, 0755))
+	readme := "# Sample\n\n" + readmeDocsBeginMarker + "\n" + readmeDocsEndMarker + "\n"
+	for _, p := range []string{bpPath, subPath} {
+		require.NoError(t, os.WriteFile(path.Join(p, metadataFileName), []byte(readmeTestMetadata), 0644))
+		require.NoError(t, os.WriteFile(path.Join(p, readmeFileName), []byte(readme), 0644))
+	}
+
+	// check mode reports outdated READMEs without writing them
+	var out bytes.Buffer
+	err := generateReadmes(bpPath, false, true, &out)
+	assert.ErrorContains(t, err, "README of 2 blueprint(s) is out of date")
+	assert.Contains(t, out.String(), "+| project\\_id | The project ID | `string` | n/a | yes |")
+	content, err := os.ReadFile(path.Join(bpPath, readmeFileName))
+	require.NoError(t, err)
+	assert.Equal(t, readme, string(content))
+
+	out.Reset()
+	require.NoError(t, generateReadmes(bpPath, false, false, &out))
+	assert.Contains(t, out.String(), "updated ")
+	for _, p := range []string{bpPath, subPath} {
+		content, err := os.ReadFile(path.Join(p, readmeFileName))
+		require.NoError(t, err)
+		assert.Equal(t, "# Sample\n\n"+readmeDocsBeginMarker+"\n"+readmeTestDocs+"\n"+readmeDocsEndMarker+"\n", string(content))
+	}
+
+	// up to date READMEs are left as is
+	out.Reset()
+	require.NoError(t, generateReadmes(bpPath, false, true, &out))
+	assert.Empty(t, out.String())
+
+	// sub module READMEs without markers are skipped
+	require.NoError(t, os.WriteFile(path.Join(subPath, readmeFileName), []byte("# Sample\n"), 0644))
+	require.NoError(t, generateReadmes(bpPath, false, true, &out))
+	require.NoError(t, os.Remove(path.Join(subPath, readmeFileName)))
+	require.NoError(t, generateReadmes(bpPath, false, true, &out))
+	assert.Empty(t, out.String())
+
+	// a top-level README without markers is reported
+	require.NoError(t, os.WriteFile(path.Join(bpPath, readmeFileName), []byte("# Sample\n"), 0644))
+	err = generateReadmes(bpPath, false, true, &out)
+	assert.ErrorContains(t, err, "marker "+readmeDocsBeginMarker+" not found")
+}
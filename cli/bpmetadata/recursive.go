@@ -0,0 +1,217 @@
+package bpmetadata
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+	"github.com/gammazero/workerpool"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// repoGeneration is the outcome of generating metadata for a blueprint repo.
+type repoGeneration struct {
+	root    string
+	results []Result
+	err     error
+}
+
+// repoValidation is the outcome of validating metadata of a blueprint repo.
+type repoValidation struct {
+	root  string
+	files []string
+	errs  []*metadataValidationError
+}
+
+// discoverBlueprintRoots returns the roots of blueprint repos under dir. A blueprint
+// root is the root of a git repo with a readme and TF config or a Kptfile. Sub-directories of
+// blueprint roots and hidden directories are not searched.
+func discoverBlueprintRoots(dir string) ([]string, error) {
+	var roots []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !isBlueprintRoot(p) {
+			return nil
+		}
+		roots = append(roots, p)
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error discovering blueprints under %s: %w", dir, err)
+	}
+	return roots, nil
+}
+
+// isBlueprintRoot returns whether dir is the root of a git repo with a readme and TF config
+// or a Kptfile.
+func isBlueprintRoot(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, readmeFileName)); err != nil {
+		return false
+	}
+	tfFiles, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if (err != nil || len(tfFiles) == 0) && !isKptPackage(dir) {
+		return false
+	}
+	// avoid opening git repos for directories which can not be a repo root
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return false
+	}
+	_, repoRoot, err := util.GetRepoUrlAndRootPath(dir)
+	return err == nil && filepath.Clean(repoRoot) == filepath.Clean(dir)
+}
+
+// generateRecursive generates metadata with opts for all blueprint repos under dir
+// using a pool of workers and writes a summary per repo to w.
+func generateRecursive(dir string, workers int, opts Options, w io.Writer) error {
+	roots, err := findRecursiveRoots(dir, workers)
+	if err != nil {
+		return err
+	}
+
+	repos := make([]repoGeneration, len(roots))
+	wp := workerpool.New(workers)
+	for i, root := range roots {
+		i, root := i, root
+		wp.Submit(func() {
+			o := opts
+			o.Path = root
+			// prompts can not be answered for repos generated in parallel
+			o.Quiet = true
+			results, err := Generate(o)
+			repos[i] = repoGeneration{root: root, results: results, err: err}
+		})
+	}
+	wp.StopWait()
+
+	expectedFiles := 1
+	if opts.Display {
+		expectedFiles++
+	}
+
+	tw := newSummaryTable(w)
+	tw.AppendHeader(table.Row{"Repo", "Created", "Updated", "Unchanged", "Failed"})
+	var errs, stalePaths []string
+	for _, repo := range repos {
+		name := repoDisplayName(dir, repo.root)
+		if repo.err != nil {
+			errs = append(errs, fmt.Sprintf("path: %s\n %s", repo.root, repo.err.Error()))
+			tw.AppendRow(table.Row{name, 0, 0, 0, expectedFiles})
+			continue
+		}
+
+		counts := make(map[FileStatus]int)
+		failed := 0
+		for _, r := range repo.results {
+			for _, warning := range r.Warnings {
+				Log.Warn(warning, "path", r.Path)
+			}
+			fmt.Fprint(w, r.Diff)
+			for _, status := range r.Files {
+				counts[status]++
+			}
+			if r.Err != nil {
+				errs = append(errs, fmt.Sprintf("path: %s\n %s", r.Path, r.Err.Error()))
+				failed += max(expectedFiles-len(r.Files), 1)
+			}
+			if r.Stale {
+				stalePaths = append(stalePaths, r.Path)
+			}
+		}
+		tw.AppendRow(table.Row{name, counts[FileCreated], counts[FileUpdated], counts[FileUnchanged], failed})
+	}
+	tw.Render()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	if opts.Check && len(stalePaths) > 0 {
+		return fmt.Errorf("metadata is out of date for blueprint path(s):\n %s", strings.Join(stalePaths, "\n "))
+	}
+	return nil
+}
+
+// validateRecursive validates metadata of all blueprint repos under dir using a
+// pool of workers and writes validation errors followed by a summary per repo to w.
+// The summary is omitted for the JSON format.
+func validateRecursive(dir string, workers int, format string, w io.Writer) error {
+	if format != validateFormatText && format != validateFormatJSON {
+		return fmt.Errorf("invalid format %s - one of %+q expected", format, []string{validateFormatText, validateFormatJSON})
+	}
+
+	roots, err := findRecursiveRoots(dir, workers)
+	if err != nil {
+		return err
+	}
+
+	repos := make([]repoValidation, len(roots))
+	wp := workerpool.New(workers)
+	for i, root := range roots {
+		i, root := i, root
+		wp.Submit(func() {
+			files, errs := validateMetadataFiles(root)
+			repos[i] = repoValidation{root: root, files: files, errs: errs}
+		})
+	}
+	wp.StopWait()
+
+	vErrs := []*metadataValidationError{}
+	for _, repo := range repos {
+		vErrs = append(vErrs, repo.errs...)
+	}
+	if err := writeValidationErrors(vErrs, format, w); err != nil {
+		return err
+	}
+
+	if format == validateFormatText {
+		tw := newSummaryTable(w)
+		tw.AppendHeader(table.Row{"Repo", "Valid", "Invalid"})
+		for _, repo := range repos {
+			tw.AppendRow(table.Row{repoDisplayName(dir, repo.root), len(repo.files) - len(repo.errs), len(repo.errs)})
+		}
+		tw.Render()
+	}
+
+	return validationFailedError(vErrs)
+}
+
+// findRecursiveRoots returns the blueprint roots under dir or an error if there are none.
+func findRecursiveRoots(dir string, workers int) ([]string, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("invalid number of workers %d - at least 1 expected", workers)
+	}
+	roots, err := discoverBlueprintRoots(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no blueprint repos found under %s", dir)
+	}
+	return roots, nil
+}
+
+// repoDisplayName returns the path of a repo root relative to dir.
+func repoDisplayName(dir, root string) string {
+	if rel, err := filepath.Rel(dir, root); err == nil {
+		return rel
+	}
+	return root
+}
+
+func newSummaryTable(w io.Writer) table.Writer {
+	tw := table.NewWriter()
+	tw.Style().Options.DrawBorder = false
+	tw.SetOutputMirror(w)
+	return tw
+}
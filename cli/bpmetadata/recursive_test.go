@@ -0,0 +1,115 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRepos creates blueprint repos with the given names from the sample module
+// under a temp dir along with directories that are not blueprint repos.
+func newTestRepos(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		repoPath := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(repoPath), 0755))
+		require.NoError(t, os.Rename(newTestBlueprint(t), repoPath))
+		r, err := git.PlainInit(repoPath, false)
+		require.NoError(t, err)
+		_, err = r.CreateRemote(&config.RemoteConfig{
+			Name: "origin",
+			URLs: []string{"https://github.com/GoogleCloudPlatform/terraform-google-" + name},
+		})
+		require.NoError(t, err)
+	}
+
+	// not a git repo
+	require.NoError(t, os.Rename(newTestBlueprint(t), filepath.Join(dir, "not-a-repo")))
+	// hidden directories are not searched
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".cache"), 0755))
+	return dir
+}
+
+func TestDiscoverBlueprintRoots(t *testing.T) {
+	dir := newTestRepos(t, "foo", "nested/bar")
+
+	roots, err := discoverBlueprintRoots(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "foo"), filepath.Join(dir, "nested", "bar")}, roots)
+}
+
+func TestGenerateRecursive(t *testing.T) {
+	dir := newTestRepos(t, "foo", "bar")
+	opts := Options{Nested: true, Display: true}
+
+	var out bytes.Buffer
+	require.NoError(t, generateRecursive(dir, 2, opts, &out))
+	assert.Regexp(t, summaryRow("REPO", "CREATED", "UPDATED", "UNCHANGED", "FAILED"), out.String())
+	assert.Regexp(t, summaryRow("bar", "4", "0", "0", "0"), out.String())
+	assert.Regexp(t, summaryRow("foo", "4", "0", "0", "0"), out.String())
+	for _, name := range []string{"foo", "bar"} {
+		md, err := UnmarshalMetadata(filepath.Join(dir, name), metadataFileName)
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/GoogleCloudPlatform/terraform-google-"+name, md.Spec.Info.Source.Repo)
+		assert.FileExists(t, filepath.Join(dir, name, "modules", "sub", metadataDisplayFileName))
+	}
+	assert.NoFileExists(t, filepath.Join(dir, "not-a-repo", metadataFileName))
+
+	// regeneration leaves metadata unchanged
+	out.Reset()
+	require.NoError(t, generateRecursive(dir, 2, opts, &out))
+	assert.Regexp(t, summaryRow("foo", "0", "0", "4", "0"), out.String())
+
+	// changes are reported as stale in check mode
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo", readmeFileName), []byte("# Changed\n"), 0644))
+	opts.Check = true
+	out.Reset()
+	err := generateRecursive(dir, 2, opts, &out)
+	assert.ErrorContains(t, err, "metadata is out of date")
+	assert.Regexp(t, summaryRow("foo", "0", "2", "2", "0"), out.String())
+	assert.Contains(t, out.String(), "+    title: Changed")
+
+	// failures are reported per repo
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar", metadataFileName), []byte("kind: foo"), 0644))
+	opts.Check = false
+	out.Reset()
+	err = generateRecursive(dir, 2, opts, &out)
+	assert.ErrorContains(t, err, filepath.Join(dir, "bar"))
+	assert.Regexp(t, summaryRow("bar", "0", "0", "2", "2"), out.String())
+	assert.Regexp(t, summaryRow("foo", "0", "2", "2", "0"), out.String())
+}
+
+func TestValidateRecursive(t *testing.T) {
+	dir := newTestRepos(t, "foo", "bar")
+	require.NoError(t, generateRecursive(dir, 1, Options{}, &bytes.Buffer{}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar", metadataFileName), []byte("apiVersion: blueprints.cloud.google.com/v1alpha1\nkind: BlueprintMetadata\nfoo: bar\n"), 0644))
+
+	var out bytes.Buffer
+	err := validateRecursive(dir, 2, validateFormatText, &out)
+	assert.ErrorContains(t, err, "metadata validation failed for 1 file(s)")
+	assert.Contains(t, out.String(), filepath.Join("bar", metadataFileName))
+	assert.Regexp(t, summaryRow("REPO", "VALID", "INVALID"), out.String())
+	assert.Regexp(t, summaryRow("bar", "0", "1"), out.String())
+	assert.Regexp(t, summaryRow("foo", "1", "0"), out.String())
+}
+
+func TestRecursiveNoRepos(t *testing.T) {
+	err := generateRecursive(t.TempDir(), 1, Options{}, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "no blueprint repos found")
+
+	err = generateRecursive(t.TempDir(), 0, Options{}, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "invalid number of workers")
+}
+
+// summaryRow returns a pattern matching a row of a summary table.
+func summaryRow(cols ...string) string {
+	return strings.Join(cols, `\s*\|\s*`)
+}
@@ -98,9 +98,9 @@ func getRepoDetailsFromRootBp(bpPath string) repoDetail {
 
 	// There is metadata for root but does not have source info
 	// which means this is a non-git hosted blueprint
-	if b.Spec.Info.Source == nil {
+	if b.GetSpec().GetInfo().GetSource() == nil {
 		return repoDetail{
-			RepoName: b.Metadata.Name,
+			RepoName: b.GetMetadata().GetName(),
 			Source: &repoSource{
 				BlueprintRootPath: rootBp,
 			},
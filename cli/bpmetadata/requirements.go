@@ -0,0 +1,174 @@
+package bpmetadata
+
+import (
+	_ "embed"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	requirementsSourceSetup     = "setup"
+	requirementsSourceResources = "resources"
+)
+
+//go:embed requirements/resource-requirements.yaml
+var resourceRequirementsYAML []byte
+
+// resourceRequirementsTable maps resource and data source types to the
+// services and project roles required to manage them.
+type resourceRequirementsTable struct {
+	Version     string                          `json:"version"`
+	Resources   map[string]resourceRequirements `json:"resources"`
+	DataSources map[string]resourceRequirements `json:"dataSources"`
+}
+
+type resourceRequirements struct {
+	Services []string `json:"services,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+}
+
+// loadResourceRequirementsTable loads the embedded resource requirements table.
+func loadResourceRequirementsTable() (*resourceRequirementsTable, error) {
+	var t resourceRequirementsTable
+	if err := yaml.UnmarshalStrict(resourceRequirementsYAML, &t); err != nil {
+		return nil, fmt.Errorf("error parsing resource requirements table: %w", err)
+	}
+	return &t, nil
+}
+
+// inferBlueprintRequirements infers the services and project roles required for the
+// blueprint at bpPath from the resources and data sources it uses, including those of
+// nested local modules. Provider versions are parsed from versionsConfigPath, if present.
+func inferBlueprintRequirements(bpPath, versionsConfigPath string) (*BlueprintRequirements, error) {
+	t, err := loadResourceRequirementsTable()
+	if err != nil {
+		return nil, err
+	}
+
+	var services, roles []string
+	visited := make(map[string]bool)
+	var scan func(modPath string) error
+	scan = func(modPath string) error {
+		modPath = filepath.Clean(modPath)
+		if visited[modPath] {
+			return nil
+		}
+		visited[modPath] = true
+
+		mod, diags := tfconfig.LoadModule(modPath)
+		if err := hasTfconfigErrors(diags); err != nil {
+			return err
+		}
+		for _, r := range mod.ManagedResources {
+			if req, ok := t.lookup(t.Resources, r.Type, "resource"); ok {
+				services = append(services, req.Services...)
+				roles = append(roles, req.Roles...)
+			}
+		}
+		for _, d := range mod.DataResources {
+			if req, ok := t.lookup(t.DataSources, d.Type, "data source"); ok {
+				services = append(services, req.Services...)
+				roles = append(roles, req.Roles...)
+			}
+		}
+		for _, call := range mod.ModuleCalls {
+			if !util.IsLocalModuleSource(call.Source) {
+				continue
+			}
+			if err := scan(filepath.Join(modPath, call.Source)); err != nil {
+				return fmt.Errorf("error scanning module %s: %w", call.Name, err)
+			}
+		}
+		return nil
+	}
+	if err := scan(bpPath); err != nil {
+		return nil, err
+	}
+
+	Log.Info("inferred blueprint requirements from resources", "tableVersion", t.Version)
+	requirements := &BlueprintRequirements{
+		Services: sortedUnique(services),
+	}
+	if len(roles) > 0 {
+		requirements.Roles = []*BlueprintRoles{{Level: "Project", Roles: sortedUnique(roles)}}
+	}
+
+	requirements.ProviderVersions, err = getBlueprintProviderVersions(versionsConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return requirements, nil
+}
+
+// lookup returns the requirements for a type, logging types missing from the table.
+func (t *resourceRequirementsTable) lookup(entries map[string]resourceRequirements, typ, kind string) (resourceRequirements, bool) {
+	req, ok := entries[typ]
+	if !ok && strings.HasPrefix(typ, "google_") {
+		Log.Info("no requirements mapping found", kind, typ, "tableVersion", t.Version)
+	}
+	return req, ok
+}
+
+// compareRequirements returns discrepancies between requirements inferred from resources
+// and requirements declared in the setup files.
+func compareRequirements(inferred, setup *BlueprintRequirements) []string {
+	var discrepancies []string
+	setupServices := setOf(setup.Services)
+	for _, s := range inferred.Services {
+		if !setupServices[s] {
+			discrepancies = append(discrepancies, fmt.Sprintf("service %s is required by resources but not enabled in %s", s, tfServicesFileName))
+		}
+	}
+	inferredServices := setOf(inferred.Services)
+	for _, s := range sortedUnique(setup.Services) {
+		if !inferredServices[s] {
+			discrepancies = append(discrepancies, fmt.Sprintf("service %s is enabled in %s but not required by any resource", s, tfServicesFileName))
+		}
+	}
+
+	setupRoles := setOf(flattenRoles(setup.Roles))
+	for _, r := range flattenRoles(inferred.Roles) {
+		if !setupRoles[r] {
+			discrepancies = append(discrepancies, fmt.Sprintf("role %s is required by resources but not granted in %s", r, tfRolesFileName))
+		}
+	}
+	inferredRoles := setOf(flattenRoles(inferred.Roles))
+	for _, r := range flattenRoles(setup.Roles) {
+		if !inferredRoles[r] {
+			discrepancies = append(discrepancies, fmt.Sprintf("role %s is granted in %s but not required by any resource", r, tfRolesFileName))
+		}
+	}
+	return discrepancies
+}
+
+func flattenRoles(roles []*BlueprintRoles) []string {
+	var r []string
+	for _, br := range roles {
+		r = append(r, br.Roles...)
+	}
+	return sortedUnique(r)
+}
+
+func setOf(items []string) map[string]bool {
+	s := make(map[string]bool, len(items))
+	for _, i := range items {
+		s[i] = true
+	}
+	return s
+}
+
+func sortedUnique(items []string) []string {
+	if len(items) == 0 {
+		return nil
+	}
+	r := slices.Clone(items)
+	sort.Strings(r)
+	return slices.Compact(r)
+}
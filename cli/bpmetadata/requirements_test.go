@@ -0,0 +1,120 @@
+package bpmetadata
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadResourceRequirementsTable(t *testing.T) {
+	table, err := loadResourceRequirementsTable()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, table.Version)
+	assert.Contains(t, table.Resources, "google_compute_instance")
+	assert.Contains(t, table.DataSources, "google_compute_network")
+}
+
+func TestInferBlueprintRequirements(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		versionsFile string
+		want         *BlueprintRequirements
+		wantErr      bool
+	}{
+		{
+			name: "resources and nested local modules",
+			path: "resource-requirements",
+			want: &BlueprintRequirements{
+				Services: []string{"compute.googleapis.com", "storage.googleapis.com"},
+				Roles: []*BlueprintRoles{
+					{
+						Level: "Project",
+						Roles: []string{"roles/compute.instanceAdmin.v1", "roles/compute.networkViewer", "roles/iam.serviceAccountUser", "roles/storage.admin"},
+					},
+				},
+			},
+		},
+		{
+			name:         "provider versions",
+			path:         "resource-requirements",
+			versionsFile: "versions-beta.tf",
+			want: &BlueprintRequirements{
+				Services: []string{"compute.googleapis.com", "storage.googleapis.com"},
+				Roles: []*BlueprintRoles{
+					{
+						Level: "Project",
+						Roles: []string{"roles/compute.instanceAdmin.v1", "roles/compute.networkViewer", "roles/iam.serviceAccountUser", "roles/storage.admin"},
+					},
+				},
+				ProviderVersions: []*ProviderVersion{
+					{Source: "hashicorp/google", Version: ">= 4.4.0, < 8"},
+					{Source: "hashicorp/google-beta", Version: ">= 4.4.0, < 8"},
+				},
+			},
+		},
+		{
+			name: "no resources",
+			path: "empty-module",
+			want: &BlueprintRequirements{},
+		},
+		{
+			name:    "invalid module",
+			path:    "invalid-module",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inferBlueprintRequirements(path.Join(tfTestdataPath, tt.path), path.Join(tfTestdataPath, tt.versionsFile))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompareRequirements(t *testing.T) {
+	inferred := &BlueprintRequirements{
+		Services: []string{"compute.googleapis.com", "storage.googleapis.com"},
+		Roles:    []*BlueprintRoles{{Level: "Project", Roles: []string{"roles/compute.instanceAdmin.v1", "roles/storage.admin"}}},
+	}
+
+	tests := []struct {
+		name  string
+		setup *BlueprintRequirements
+		want  []string
+	}{
+		{
+			name: "consistent",
+			setup: &BlueprintRequirements{
+				Services: []string{"storage.googleapis.com", "compute.googleapis.com"},
+				Roles:    []*BlueprintRoles{{Level: "Project", Roles: []string{"roles/storage.admin", "roles/compute.instanceAdmin.v1"}}},
+			},
+		},
+		{
+			name: "discrepancies",
+			setup: &BlueprintRequirements{
+				Services: []string{"compute.googleapis.com", "iam.googleapis.com"},
+				Roles:    []*BlueprintRoles{{Level: "Project", Roles: []string{"roles/owner", "roles/compute.instanceAdmin.v1"}}},
+			},
+			want: []string{
+				"service storage.googleapis.com is required by resources but not enabled in test/setup/main.tf",
+				"service iam.googleapis.com is enabled in test/setup/main.tf but not required by any resource",
+				"role roles/storage.admin is required by resources but not granted in test/setup/iam.tf",
+				"role roles/owner is granted in test/setup/iam.tf but not required by any resource",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, compareRequirements(inferred, tt.setup))
+		})
+	}
+}
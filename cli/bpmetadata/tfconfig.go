@@ -9,6 +9,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata/parser"
 	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/infra/blueprint-test/pkg/tft"
@@ -16,9 +17,12 @@ import (
 	hcl "github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/terraform-config-inspect/tfconfig"
 	testingiface "github.com/mitchellh/go-testing-interface"
 	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -93,6 +97,10 @@ var moduleSchema = &hcl.BodySchema{
 // Create alias for generateTFStateFile so we can mock it in unit test.
 var tfState = generateTFState
 
+// tfStateMu serializes generateTFState which parses global flags and runs the
+// blueprint test framework.
+var tfStateMu sync.Mutex
+
 // getBlueprintVersion gets both the required core version and the
 // version of the blueprint
 func getBlueprintVersion(configPath string) (*blueprintVersion, error) {
@@ -260,6 +268,11 @@ func getBlueprintInterfaces(configPath string) (*BlueprintInterface, error) {
 		variables = append(variables, v)
 	}
 
+	// tfconfig does not load nullability and validation rules of variables
+	if err := addVariableConstraints(configPath, variables); err != nil {
+		return nil, err
+	}
+
 	// Get the varible orders from tf file.
 	variableOrders, sortErr := getBlueprintVariableOrders(configPath)
 	if sortErr != nil {
@@ -322,6 +335,7 @@ func getBlueprintVariable(modVar *tfconfig.Variable) *BlueprintVariable {
 		Description: modVar.Description,
 		Required:    modVar.Required,
 		VarType:     modVar.Type,
+		Sensitive:   modVar.Sensitive,
 	}
 	if modVar.Default == nil {
 		return v
@@ -340,7 +354,73 @@ func getBlueprintOutput(modOut *tfconfig.Output) *BlueprintOutput {
 	return &BlueprintOutput{
 		Name:        modOut.Name,
 		Description: modOut.Description,
+		Sensitive:   modOut.Sensitive,
+	}
+}
+
+// addVariableConstraints sets the declared nullability and the validation
+// rules of variables from the TF config at configPath.
+func addVariableConstraints(configPath string, variables []*BlueprintVariable) error {
+	nullables := make(map[string]bool)
+	err := forEachBlock(configPath, "variable", 1, func(block *hclsyntax.Block, _ []byte) {
+		attr, ok := block.Body.Attributes["nullable"]
+		if !ok {
+			return
+		}
+		if val, diags := attr.Expr.Value(nil); !diags.HasErrors() && val.Type() == cty.Bool && val.IsKnown() {
+			nullables[block.Labels[0]] = val.True()
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	validations, err := getVariableValidations(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range variables {
+		if nullable, ok := nullables[v.Name]; ok {
+			v.Nullable = proto.Bool(nullable)
+		}
+		for _, validation := range validations[v.Name] {
+			v.Validations = append(v.Validations, &BlueprintVariableValidation{
+				Condition:    validation.source,
+				ErrorMessage: validation.errorMessage,
+			})
+		}
+	}
+	return nil
+}
+
+// forEachBlock calls fn with each top-level block of blockType with the given number
+// of labels in the TF config at bpPath and the source of the file it is declared in.
+// Files are visited in lexical order.
+func forEachBlock(bpPath, blockType string, labels int, fn func(block *hclsyntax.Block, src []byte)) error {
+	files, err := filepath.Glob(filepath.Join(bpPath, "*.tf"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	p := hclparse.NewParser()
+	for _, f := range files {
+		file, diags := p.ParseHCLFile(f)
+		if err := hasHclErrors(diags); err != nil {
+			return err
+		}
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type == blockType && len(block.Labels) == labels {
+				fn(block, file.Bytes)
+			}
+		}
 	}
+	return nil
 }
 
 // getBlueprintRequirements gets the services and roles associated
@@ -372,23 +452,7 @@ func getBlueprintRequirements(rolesConfigPath, servicesConfigPath, versionsConfi
 		return nil, err
 	}
 
-	versionCfgFileExists, _ := fileExists(versionsConfigPath)
-
-	if !versionCfgFileExists {
-		return &BlueprintRequirements{
-			Roles:    r,
-			Services: s,
-		}, nil
-	}
-
-	//parse blueprint provider versions
-	versionsFile, diags := p.ParseHCLFile(versionsConfigPath)
-	err = hasHclErrors(diags)
-	if err != nil {
-		return nil, err
-	}
-
-	v, err := parseBlueprintProviderVersions(versionsFile)
+	v, err := getBlueprintProviderVersions(versionsConfigPath)
 	if err != nil {
 		return nil, err
 	}
@@ -401,6 +465,25 @@ func getBlueprintRequirements(rolesConfigPath, servicesConfigPath, versionsConfi
 
 }
 
+// getBlueprintProviderVersions gets the provider versions from the
+// versions config, if present
+func getBlueprintProviderVersions(versionsConfigPath string) ([]*ProviderVersion, error) {
+	versionCfgFileExists, _ := fileExists(versionsConfigPath)
+	if !versionCfgFileExists {
+		return nil, nil
+	}
+
+	//parse blueprint provider versions
+	p := hclparse.NewParser()
+	versionsFile, diags := p.ParseHCLFile(versionsConfigPath)
+	err := hasHclErrors(diags)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBlueprintProviderVersions(versionsFile)
+}
+
 // parseBlueprintRoles gets the roles required for the blueprint to be provisioned
 func parseBlueprintRoles(rolesFile *hcl.File, perModuleMode bool, moduleName string) ([]*BlueprintRoles, error) {
 	var r []*BlueprintRoles
@@ -606,6 +689,9 @@ func updateOutputTypes(bpPath string, bpInterfaces *BlueprintInterface) error {
 
 // generateTFState generates the terraform.tfstate by running terraform init and apply, and terraform show to capture the state.
 func generateTFState(bpPath string) ([]byte, error) {
+	tfStateMu.Lock()
+	defer tfStateMu.Unlock()
+
 	var stateData []byte
 	// Construct the path to the test/setup directory
 	tfDir := filepath.Join(bpPath)
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -116,6 +117,45 @@ func TestTFInterfaces(t *testing.T) {
 	}
 }
 
+func TestTFInterfacesConstraints(t *testing.T) {
+	got, err := getBlueprintInterfaces(path.Join(tfTestdataPath, "variable-constraints"))
+	require.NoError(t, err)
+
+	want := &BlueprintInterface{
+		Variables: []*BlueprintVariable{
+			{
+				Name:     "name",
+				VarType:  "string",
+				Required: true,
+				Nullable: proto.Bool(false),
+				Validations: []*BlueprintVariableValidation{
+					{Condition: "length(var.name) <= 30", ErrorMessage: "Name must be at most 30 characters."},
+				},
+			},
+			{
+				Name:      "password",
+				VarType:   "string",
+				Required:  true,
+				Sensitive: true,
+				Nullable:  proto.Bool(true),
+			},
+			{
+				Name:         "labels",
+				VarType:      "map(string)",
+				DefaultValue: structpb.NewStructValue(&structpb.Struct{}),
+				Validations: []*BlueprintVariableValidation{
+					{Condition: "alltrue([\n      for k, v in var.labels : can(regex(\"^[a-z]\", k))\n    ])"},
+				},
+			},
+		},
+		Outputs: []*BlueprintOutput{
+			{Name: "name"},
+			{Name: "password", Sensitive: true},
+		},
+	}
+	assert.True(t, proto.Equal(want, got), "got %v, want %v", got, want)
+}
+
 func TestTFVersions(t *testing.T) {
 	tests := []struct {
 		name                string
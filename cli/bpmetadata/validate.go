@@ -2,30 +2,73 @@ package bpmetadata
 
 import (
 	_ "embed"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
 	"github.com/xeipuuv/gojsonschema"
 	"sigs.k8s.io/yaml"
 )
 
+const (
+	validateFormatText = "text"
+	validateFormatJSON = "json"
+)
+
 //go:embed schema/gcp-blueprint-metadata.json
 var s []byte
 
+// schemaError is a schema validation error located within a metadata file.
+type schemaError struct {
+	// Line and Column are zero if the error could not be located.
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// metadataValidationError holds all schema validation errors for a metadata file.
+type metadataValidationError struct {
+	File   string        `json:"file"`
+	Errors []schemaError `json:"errors"`
+}
+
+func (e *metadataValidationError) Error() string {
+	return fmt.Sprintf("metadata validation failed for %s with %d error(s)", e.File, len(e.Errors))
+}
+
 // validateMetadata validates the metadata files for the provided
 // blueprint path. This validation occurs for top-level blueprint
-// metadata and blueprints in the modules/ folder, if present
-func validateMetadata(bpPath, wdPath string) error {
-	// load schema from the binary
-	schemaLoader := gojsonschema.NewStringLoader(string(s))
+// metadata and blueprints in the modules/ folder, if present.
+// Validation errors for all failing files are written to w in the provided format.
+func validateMetadata(bpPath, wdPath, format string, w io.Writer) error {
+	if format != validateFormatText && format != validateFormatJSON {
+		return fmt.Errorf("invalid format %s - one of %+q expected", format, []string{validateFormatText, validateFormatJSON})
+	}
 
 	// check if the provided output path is relative
 	if !path.IsAbs(bpPath) {
 		bpPath = path.Join(wdPath, bpPath)
 	}
 
+	_, vErrs := validateMetadataFiles(bpPath)
+	if err := writeValidationErrors(vErrs, format, w); err != nil {
+		return err
+	}
+
+	return validationFailedError(vErrs)
+}
+
+// validateMetadataFiles validates all metadata files under bpPath. Returns the
+// paths of validated files and errors for invalid files.
+func validateMetadataFiles(bpPath string) ([]string, []*metadataValidationError) {
+	// load schema from the binary
+	schemaLoader := gojsonschema.NewStringLoader(string(s))
+
 	// We don't need to validate metadata under .terraform folders
 	skipDirsToValidate := []string{".terraform/"}
 	metadataFiles, err := util.FindFilesWithPattern(bpPath, `^metadata(?:.display)?.yaml$`, skipDirsToValidate)
@@ -33,23 +76,61 @@ func validateMetadata(bpPath, wdPath string) error {
 		Log.Error("unable to read at: %s", bpPath, "err", err)
 	}
 
-	var vErrs []error
+	vErrs := []*metadataValidationError{}
 	for _, f := range metadataFiles {
 		err = validateMetadataYaml(f, schemaLoader)
-		if err != nil {
-			vErrs = append(vErrs, err)
-			Log.Error("core metadata validation failed", "err", err)
+		if err == nil {
+			continue
 		}
+		vErr, ok := err.(*metadataValidationError)
+		if !ok {
+			vErr = &metadataValidationError{File: f, Errors: []schemaError{{Message: err.Error()}}}
+		}
+		vErr.File = displayPath(vErr.File)
+		vErrs = append(vErrs, vErr)
+	}
+	return metadataFiles, vErrs
+}
+
+// validationFailedError returns an error listing invalid files, if any.
+func validationFailedError(vErrs []*metadataValidationError) error {
+	if len(vErrs) == 0 {
+		return nil
+	}
+	files := make([]string, 0, len(vErrs))
+	for _, e := range vErrs {
+		files = append(files, e.File)
 	}
+	return fmt.Errorf("metadata validation failed for %d file(s): %s", len(files), strings.Join(files, ", "))
+}
 
-	if len(vErrs) > 0 {
-		return fmt.Errorf("metadata validation failed for at least one blueprint")
+// writeValidationErrors writes validation errors grouped per file to w.
+func writeValidationErrors(vErrs []*metadataValidationError, format string, w io.Writer) error {
+	if format == validateFormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(vErrs)
 	}
 
+	for _, vErr := range vErrs {
+		fmt.Fprintf(w, "%s:\n", vErr.File)
+		for _, e := range vErr.Errors {
+			location := vErr.File
+			if e.Line > 0 {
+				location = fmt.Sprintf("%s:%d:%d", vErr.File, e.Line, e.Column)
+			}
+			if e.Field != "" {
+				fmt.Fprintf(w, "  %s: %s: %s\n", location, e.Field, e.Message)
+			} else {
+				fmt.Fprintf(w, "  %s: %s\n", location, e.Message)
+			}
+		}
+	}
 	return nil
 }
 
-// validateMetadata validates an individual yaml file present at path "m"
+// validateMetadata validates an individual yaml file present at path "m".
+// Schema violations are returned as a *metadataValidationError.
 func validateMetadataYaml(m string, schema gojsonschema.JSONLoader) error {
 	// prepare metadata for validation by converting it from YAML to JSON
 	mBytes, err := convertYamlToJson(m)
@@ -67,17 +148,49 @@ func validateMetadataYaml(m string, schema gojsonschema.JSONLoader) error {
 	}
 
 	if !result.Valid() {
+		content, err := os.ReadFile(m)
+		if err != nil {
+			return fmt.Errorf("unable to read metadata at path %s. error: %w", m, err)
+		}
+
+		vErr := &metadataValidationError{File: m}
 		for _, e := range result.Errors() {
-			Log.Error("validation error", "err", e)
+			vErr.Errors = append(vErr.Errors, newSchemaError(content, e))
 		}
+		return vErr
+	}
 
-		return fmt.Errorf("metdata validation failed for: %s", m)
+	// cross references in display metadata are validated once it conforms to the schema
+	if path.Base(m) == metadataDisplayFileName {
+		if err := validateDisplayMetadataFile(m); err != nil {
+			return err
+		}
 	}
 
 	Log.Info("metadata is valid", "path", m)
 	return nil
 }
 
+// newSchemaError locates a gojsonschema error within the YAML content it was found in.
+func newSchemaError(content []byte, e gojsonschema.ResultError) schemaError {
+	field := e.Field()
+	// errors about a specific property are reported on its parent so point to the property if it exists
+	if property, ok := e.Details()["property"].(string); ok && e.Type() == "additional_property_not_allowed" {
+		if field == "(root)" {
+			field = property
+		} else {
+			field += "." + property
+		}
+	}
+
+	se := schemaError{Field: field, Message: e.Description()}
+	line, column, err := util.FindYAMLPosition(content, field)
+	if err == nil {
+		se.Line, se.Column = line, column
+	}
+	return se
+}
+
 // prepares metadata bytes for validation since direct
 // validation of YAML is not possible
 func convertYamlToJson(m string) ([]byte, error) {
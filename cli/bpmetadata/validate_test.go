@@ -1,9 +1,14 @@
 package bpmetadata
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
 	"path"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/xeipuuv/gojsonschema"
 )
 
@@ -66,3 +71,101 @@ func TestValidateMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMetadataYamlErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want []schemaError
+	}{
+		{
+			name: "missing required property at root",
+			path: "invalid-metadata.yaml",
+			want: []schemaError{
+				{Line: 1, Column: 1, Field: "(root)", Message: "spec is required"},
+			},
+		},
+		{
+			name: "nested errors",
+			path: "invalid-metadata-w-enum.yaml",
+			want: []schemaError{
+				{Line: 12, Column: 7, Field: "spec.info.quotaDetails.0", Message: "resourceType is required"},
+				{Line: 12, Column: 7, Field: "spec.info.quotaDetails.0.type", Message: "Additional property type is not allowed"},
+			},
+		},
+	}
+
+	s := gojsonschema.NewReferenceLoader("file://schema/gcp-blueprint-metadata.json")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMetadataYaml(path.Join(yamlTestDirPath, tt.path), s)
+			var vErr *metadataValidationError
+			require.ErrorAs(t, err, &vErr)
+			assert.Equal(t, path.Join(yamlTestDirPath, tt.path), vErr.File)
+			assert.Equal(t, tt.want, vErr.Errors)
+		})
+	}
+}
+
+func TestValidateMetadataOutput(t *testing.T) {
+	bpPath := t.TempDir()
+	copyFile := func(src, dst string) {
+		b, err := os.ReadFile(path.Join(yamlTestDirPath, src))
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(path.Dir(path.Join(bpPath, dst)), 0755))
+		require.NoError(t, os.WriteFile(path.Join(bpPath, dst), b, 0644))
+	}
+	copyFile("valid-metadata.yaml", "metadata.yaml")
+	copyFile("invalid-metadata.yaml", "modules/a/metadata.yaml")
+	copyFile("empty-metadata.yaml", "modules/b/metadata.yaml")
+	copyFile("invalid-metadata.yaml", ".terraform/modules/c/metadata.yaml")
+	fileA, fileB := displayPath(path.Join(bpPath, "modules/a/metadata.yaml")), displayPath(path.Join(bpPath, "modules/b/metadata.yaml"))
+
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "text",
+			format: validateFormatText,
+			want: fileA + ":\n" +
+				"  " + fileA + ":1:1: (root): spec is required\n" +
+				fileB + ":\n" +
+				"  " + fileB + ": yaml to json conversion failed for metadata at path " + path.Join(bpPath, "modules/b/metadata.yaml") + ". error: metadata contents can not be empty\n",
+			wantErr: "metadata validation failed for 2 file(s): " + fileA + ", " + fileB,
+		},
+		{
+			name:    "json",
+			format:  validateFormatJSON,
+			wantErr: "metadata validation failed for 2 file(s): " + fileA + ", " + fileB,
+		},
+		{
+			name:    "invalid format",
+			format:  "sarif",
+			wantErr: "invalid format sarif - one of [\"text\" \"json\"] expected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := validateMetadata(bpPath, "", tt.format, &b)
+			assert.EqualError(t, err, tt.wantErr)
+			switch tt.format {
+			case validateFormatJSON:
+				var got []*metadataValidationError
+				require.NoError(t, json.Unmarshal(b.Bytes(), &got))
+				require.Len(t, got, 2)
+				assert.Equal(t, fileA, got[0].File)
+				assert.Equal(t, []schemaError{{Line: 1, Column: 1, Field: "(root)", Message: "spec is required"}}, got[0].Errors)
+				assert.Equal(t, fileB, got[1].File)
+				assert.Len(t, got[1].Errors, 1)
+			default:
+				assert.Equal(t, tt.want, b.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package bpmetadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/xeipuuv/gojsonschema"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// jsonSchema is the subset of JSON Schema used to describe tfvars.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Items                any                    `json:"items,omitempty"`
+	UniqueItems          bool                   `json:"uniqueItems,omitempty"`
+	Default              any                    `json:"default,omitempty"`
+	Enum                 []any                  `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	MinLength            *int32                 `json:"minLength,omitempty"`
+	MaxLength            *int32                 `json:"maxLength,omitempty"`
+	MinItems             *int32                 `json:"minItems,omitempty"`
+	MaxItems             *int32                 `json:"maxItems,omitempty"`
+	Minimum              *float32               `json:"minimum,omitempty"`
+	Maximum              *float32               `json:"maximum,omitempty"`
+}
+
+// inputViolation is a violation of the tfvars schema by an input file.
+type inputViolation struct {
+	// Line and Column are zero if the violation could not be located.
+	Line    int
+	Column  int
+	Field   string
+	Message string
+}
+
+// generateVarsSchema converts blueprint variables and display constraints, if any,
+// into a JSON Schema for tfvars.
+func generateVarsSchema(core, display *BlueprintMetadata) (*jsonSchema, error) {
+	s := &jsonSchema{
+		Schema:               jsonSchemaDraft,
+		Title:                core.GetSpec().GetInfo().GetTitle(),
+		Type:                 "object",
+		Properties:           make(map[string]*jsonSchema),
+		AdditionalProperties: false,
+	}
+	displayVars := display.GetSpec().GetUi().GetInput().GetVariables()
+	for _, v := range core.GetSpec().GetInterfaces().GetVariables() {
+		t, ok := variableCtyType(v)
+		if !ok {
+			t = cty.DynamicPseudoType
+		}
+		prop := typeSchema(t)
+		prop.Description = v.Description
+		if v.DefaultValue != nil {
+			prop.Default = v.DefaultValue.AsInterface()
+		}
+		if dv, ok := displayVars[v.Name]; ok {
+			if err := prop.applyDisplayConstraints(dv, t); err != nil {
+				return nil, fmt.Errorf("error applying display constraints for variable %s: %w", v.Name, err)
+			}
+		}
+		s.Properties[v.Name] = prop
+		if v.Required {
+			s.Required = append(s.Required, v.Name)
+		}
+	}
+	return s, nil
+}
+
+// typeSchema returns the JSON Schema for values of TF type t.
+func typeSchema(t cty.Type) *jsonSchema {
+	switch {
+	case t == cty.String:
+		return &jsonSchema{Type: "string"}
+	case t == cty.Number:
+		return &jsonSchema{Type: "number"}
+	case t == cty.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case t.IsListType():
+		return &jsonSchema{Type: "array", Items: typeSchema(t.ElementType())}
+	case t.IsSetType():
+		return &jsonSchema{Type: "array", Items: typeSchema(t.ElementType()), UniqueItems: true}
+	case t.IsMapType():
+		return &jsonSchema{Type: "object", AdditionalProperties: typeSchema(t.ElementType())}
+	case t.IsTupleType():
+		var items []*jsonSchema
+		for _, et := range t.TupleElementTypes() {
+			items = append(items, typeSchema(et))
+		}
+		n := int32(len(items))
+		return &jsonSchema{Type: "array", Items: items, MinItems: &n, MaxItems: &n}
+	case t.IsObjectType():
+		s := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema)}
+		for name, at := range t.AttributeTypes() {
+			s.Properties[name] = typeSchema(at)
+			if !t.AttributeOptional(name) {
+				s.Required = append(s.Required, name)
+			}
+		}
+		sort.Strings(s.Required)
+		return s
+	}
+	// any type
+	return &jsonSchema{}
+}
+
+// applyDisplayConstraints adds validation constraints from a display variable.
+func (s *jsonSchema) applyDisplayConstraints(dv *DisplayVariable, t cty.Type) error {
+	s.Title = dv.Title
+	if dv.RegexValidation != "" {
+		s.Pattern = dv.RegexValidation
+	}
+	if dv.MinLength != 0 {
+		s.MinLength = &dv.MinLength
+	}
+	if dv.MaxLength != 0 {
+		s.MaxLength = &dv.MaxLength
+	}
+	if dv.MinItems != 0 {
+		s.MinItems = &dv.MinItems
+	}
+	if dv.MaxItems != 0 {
+		s.MaxItems = &dv.MaxItems
+	}
+	if dv.Min != 0 {
+		s.Minimum = &dv.Min
+	}
+	if dv.Max != 0 {
+		s.Maximum = &dv.Max
+	}
+	for _, vl := range dv.EnumValueLabels {
+		if t != cty.Number {
+			s.Enum = append(s.Enum, vl.Value)
+			continue
+		}
+		n, err := strconv.ParseFloat(vl.Value, 64)
+		if err != nil {
+			return fmt.Errorf("enum value %q is not a number", vl.Value)
+		}
+		s.Enum = append(s.Enum, n)
+	}
+	return nil
+}
+
+// loadBlueprintVarsSchema generates the tfvars JSON Schema for the blueprint at bpPath.
+func loadBlueprintVarsSchema(bpPath string) (*jsonSchema, error) {
+	core, err := UnmarshalMetadata(bpPath, metadataFileName)
+	if err != nil {
+		return nil, fmt.Errorf("error loading metadata: %w", err)
+	}
+	display, err := UnmarshalMetadata(bpPath, metadataDisplayFileName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error loading display metadata: %w", err)
+	}
+	return generateVarsSchema(core, display)
+}
+
+// writeVarsSchema writes the tfvars JSON Schema for the blueprint at bpPath to w.
+func writeVarsSchema(bpPath string, w io.Writer) error {
+	s, err := loadBlueprintVarsSchema(bpPath)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// validateInputs validates a tfvars or tfvars.json file against the tfvars JSON Schema
+// of the blueprint at bpPath and writes all violations to w.
+func validateInputs(bpPath, varsFile string, w io.Writer) error {
+	s, err := loadBlueprintVarsSchema(bpPath)
+	if err != nil {
+		return err
+	}
+	violations, err := validateVarsFile(s, varsFile)
+	if err != nil {
+		return err
+	}
+	for _, v := range violations {
+		location := varsFile
+		if v.Line > 0 {
+			location = fmt.Sprintf("%s:%d:%d", varsFile, v.Line, v.Column)
+		}
+		fmt.Fprintf(w, "%s: %s: %s\n", location, v.Field, v.Message)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%s has %d input violation(s)", varsFile, len(violations))
+	}
+	Log.Info("inputs are valid", "path", varsFile)
+	return nil
+}
+
+// validateVarsFile returns all violations of schema s by the values in varsFile.
+func validateVarsFile(s *jsonSchema, varsFile string) ([]inputViolation, error) {
+	content, err := os.ReadFile(varsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading vars file: %w", err)
+	}
+
+	var values []byte
+	ranges := make(map[string]hcl.Range)
+	if strings.HasSuffix(varsFile, ".json") {
+		values = content
+	} else {
+		values, ranges, err = tfvarsToJSON(content, varsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	schemaBytes, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schemaBytes), gojsonschema.NewBytesLoader(values))
+	if err != nil {
+		return nil, fmt.Errorf("error validating %s: %w", varsFile, err)
+	}
+
+	var violations []inputViolation
+	for _, e := range result.Errors() {
+		v := inputViolation{Field: e.Field(), Message: e.Description()}
+		name, _, _ := strings.Cut(e.Field(), ".")
+		if property, ok := e.Details()["property"].(string); ok && e.Type() == "additional_property_not_allowed" && e.Field() == "(root)" {
+			name = property
+		}
+		if r, ok := ranges[name]; ok {
+			v.Line, v.Column = r.Start.Line, r.Start.Column
+		}
+		violations = append(violations, v)
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Line != violations[j].Line {
+			return violations[i].Line < violations[j].Line
+		}
+		if violations[i].Field != violations[j].Field {
+			return violations[i].Field < violations[j].Field
+		}
+		return violations[i].Message < violations[j].Message
+	})
+	return violations, nil
+}
+
+// tfvarsToJSON converts the contents of a tfvars file to JSON along with the
+// source range of each variable.
+func tfvarsToJSON(content []byte, filename string) ([]byte, map[string]hcl.Range, error) {
+	f, diags := hclsyntax.ParseConfig(content, filename, hcl.InitialPos)
+	if err := hasHclErrors(diags); err != nil {
+		return nil, nil, err
+	}
+	attrs, diags := f.Body.JustAttributes()
+	if err := hasHclErrors(diags); err != nil {
+		return nil, nil, err
+	}
+
+	values := make(map[string]cty.Value)
+	ranges := make(map[string]hcl.Range)
+	for name, attr := range attrs {
+		v, diags := attr.Expr.Value(nil)
+		if err := hasHclErrors(diags); err != nil {
+			return nil, nil, fmt.Errorf("error evaluating %s: %w", name, err)
+		}
+		values[name] = v
+		ranges[name] = attr.NameRange
+	}
+	obj := cty.ObjectVal(values)
+	b, err := ctyjson.Marshal(obj, obj.Type())
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, ranges, nil
+}
@@ -0,0 +1,147 @@
+package bpmetadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const varsSchemaTestdataPath = "../testdata/bpmetadata/vars-schema"
+
+func TestTypeSchema(t *testing.T) {
+	two := int32(2)
+	tests := []struct {
+		name string
+		t    cty.Type
+		want *jsonSchema
+	}{
+		{name: "string", t: cty.String, want: &jsonSchema{Type: "string"}},
+		{name: "number", t: cty.Number, want: &jsonSchema{Type: "number"}},
+		{name: "bool", t: cty.Bool, want: &jsonSchema{Type: "boolean"}},
+		{name: "list", t: cty.List(cty.String), want: &jsonSchema{Type: "array", Items: &jsonSchema{Type: "string"}}},
+		{name: "set", t: cty.Set(cty.Number), want: &jsonSchema{Type: "array", Items: &jsonSchema{Type: "number"}, UniqueItems: true}},
+		{name: "map", t: cty.Map(cty.Bool), want: &jsonSchema{Type: "object", AdditionalProperties: &jsonSchema{Type: "boolean"}}},
+		{
+			name: "tuple",
+			t:    cty.Tuple([]cty.Type{cty.String, cty.Number}),
+			want: &jsonSchema{Type: "array", Items: []*jsonSchema{{Type: "string"}, {Type: "number"}}, MinItems: &two, MaxItems: &two},
+		},
+		{
+			name: "object with optional attributes",
+			t:    cty.ObjectWithOptionalAttrs(map[string]cty.Type{"a": cty.String, "b": cty.Number}, []string{"b"}),
+			want: &jsonSchema{Type: "object", Properties: map[string]*jsonSchema{"a": {Type: "string"}, "b": {Type: "number"}}, Required: []string{"a"}},
+		},
+		{name: "any", t: cty.DynamicPseudoType, want: &jsonSchema{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, typeSchema(tt.t))
+		})
+	}
+}
+
+func TestGenerateVarsSchema(t *testing.T) {
+	s, err := loadBlueprintVarsSchema(varsSchemaTestdataPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, jsonSchemaDraft, s.Schema)
+	assert.Equal(t, "Vars Blueprint", s.Title)
+	assert.Equal(t, false, s.AdditionalProperties)
+	assert.Equal(t, []string{"project_id", "network"}, s.Required)
+	assert.Len(t, s.Properties, 8)
+
+	projectID := s.Properties["project_id"]
+	assert.Equal(t, "Project ID", projectID.Title)
+	assert.Equal(t, "^[a-z][a-z0-9-]{4,28}[a-z0-9]$", projectID.Pattern)
+
+	count := s.Properties["instance_count"]
+	assert.Equal(t, float32(1), *count.Minimum)
+	assert.Equal(t, float32(10), *count.Maximum)
+	assert.Equal(t, float64(1), count.Default)
+
+	assert.Equal(t, []any{"STANDARD", "PREMIUM"}, s.Properties["tier"].Enum)
+	assert.Equal(t, int32(1), *s.Properties["zones"].MinItems)
+	assert.Equal(t, int32(3), *s.Properties["zones"].MaxItems)
+	assert.Equal(t, []string{"name"}, s.Properties["network"].Required)
+	assert.Equal(t, &jsonSchema{Description: "Anything"}, s.Properties["extra"])
+
+	var b bytes.Buffer
+	require.NoError(t, writeVarsSchema(varsSchemaTestdataPath, &b))
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(b.Bytes(), &got))
+	assert.Equal(t, false, got["additionalProperties"])
+}
+
+func TestApplyDisplayConstraintsNumberEnum(t *testing.T) {
+	s := &jsonSchema{Type: "number"}
+	err := s.applyDisplayConstraints(&DisplayVariable{EnumValueLabels: []*ValueLabel{{Label: "One", Value: "1"}, {Label: "Two", Value: "2"}}}, cty.Number)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{float64(1), float64(2)}, s.Enum)
+
+	err = s.applyDisplayConstraints(&DisplayVariable{EnumValueLabels: []*ValueLabel{{Label: "One", Value: "one"}}}, cty.Number)
+	assert.EqualError(t, err, `enum value "one" is not a number`)
+}
+
+func TestValidateInputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		varsFile string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "valid tfvars",
+			varsFile: "valid.tfvars",
+		},
+		{
+			name:     "invalid tfvars",
+			varsFile: "invalid.tfvars",
+			want: `invalid.tfvars: (root): network is required
+invalid.tfvars:1:1: project_id: Does not match pattern '^[a-z][a-z0-9-]{4,28}[a-z0-9]$'
+invalid.tfvars:2:1: instance_count: Must be less than or equal to 10
+invalid.tfvars:3:1: tier: tier must be one of the following: "STANDARD", "PREMIUM"
+invalid.tfvars:4:1: zones: Array must have at least 1 items
+invalid.tfvars:5:1: (root): Additional property unknown is not allowed
+`,
+			wantErr: "has 6 input violation(s)",
+		},
+		{
+			name:     "invalid tfvars json",
+			varsFile: "invalid.tfvars.json",
+			want: `invalid.tfvars.json: enable_logging: Invalid type. Expected: boolean, given: string
+invalid.tfvars.json: labels.env: Invalid type. Expected: string, given: integer
+invalid.tfvars.json: network: name is required
+`,
+			wantErr: "has 3 input violation(s)",
+		},
+		{
+			name:     "missing file",
+			varsFile: "missing.tfvars",
+			wantErr:  "error reading vars file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			varsFile := path.Join(varsSchemaTestdataPath, tt.varsFile)
+			err := validateInputs(varsSchemaTestdataPath, varsFile, &b)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			want := ""
+			if tt.want != "" {
+				want = string(bytes.ReplaceAll([]byte(tt.want), []byte(tt.varsFile), []byte(varsFile)))
+			}
+			assert.Equal(t, want, b.String())
+		})
+	}
+}
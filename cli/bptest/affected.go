@@ -0,0 +1,212 @@
+package bptest
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+)
+
+const gitBin = "git"
+
+// nonModuleDirs are top level directories of a module that hold other configs or tooling
+// and changes within them do not affect the enclosing module.
+var nonModuleDirs = map[string]bool{
+	"modules":  true,
+	"examples": true,
+	"test":     true,
+	"build":    true,
+	".github":  true,
+}
+
+// getAffectedTests returns tests whose configs transitively depend on a module containing changedFiles.
+// changedFiles are expected to be relative to root.
+func getAffectedTests(root string, tests []bpTest, changedFiles []string) ([]bpTest, error) {
+	root, err := absPath(root)
+	if err != nil {
+		return nil, err
+	}
+	changed := make([]string, 0, len(changedFiles))
+	for _, f := range changedFiles {
+		changed = append(changed, filepath.Join(root, f))
+	}
+
+	// module refs are shared across tests, cache them to avoid reparsing configs
+	refsCache := make(map[string][]string)
+	affected := []bpTest{}
+	for _, t := range tests {
+		if t.bptestCfg.Spec.Skip {
+			Log.Info(fmt.Sprintf("skipping %s due to BlueprintTest config %s", t.name, t.bptestCfg.Name))
+			continue
+		}
+		deps, err := getModuleDeps(t.config, refsCache)
+		if err != nil {
+			return nil, fmt.Errorf("error finding modules referenced by %s: %w", t.name, err)
+		}
+		isAffected, err := testAffectedByFiles(t, deps, changed)
+		if err != nil {
+			return nil, err
+		}
+		if isAffected {
+			affected = append(affected, t)
+		}
+	}
+	sort.SliceStable(affected, func(i, j int) bool { return affected[i].name < affected[j].name })
+	return affected, nil
+}
+
+// testAffectedByFiles returns true if any of the changedFiles belong to the test itself
+// or to one of the module dirs the test config depends on.
+func testAffectedByFiles(t bpTest, deps []string, changedFiles []string) (bool, error) {
+	location, err := absPath(t.location)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range changedFiles {
+		// discovered tests share a single test file while explicit tests own their test package
+		if f == location || (filepath.Base(location) != discoverTestFilename && isWithinDir(f, filepath.Dir(location))) {
+			return true, nil
+		}
+		if findOwningModule(f, deps) != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// findOwningModule returns the most specific module dir in moduleDirs that contains file f
+// or an empty string if f does not belong to any of the moduleDirs.
+func findOwningModule(f string, moduleDirs []string) string {
+	owner := ""
+	for _, dir := range moduleDirs {
+		if !isWithinDir(f, dir) || len(dir) <= len(owner) {
+			continue
+		}
+		rel, err := filepath.Rel(dir, f)
+		if err != nil {
+			continue
+		}
+		parts := strings.Split(rel, string(filepath.Separator))
+		if len(parts) > 1 && nonModuleDirs[parts[0]] {
+			continue
+		}
+		owner = dir
+	}
+	return owner
+}
+
+// absPath returns an absolute representation of p with any symlinks resolved
+// so that it can be compared against paths reported by git.
+func absPath(p string) (string, error) {
+	p, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		// p may have been deleted, fallback to the unresolved path
+		return p, nil
+	}
+	return resolved, nil
+}
+
+// isWithinDir returns true if f is located within dir
+func isWithinDir(f, dir string) bool {
+	rel, err := filepath.Rel(dir, f)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// getModuleDeps does a graph search starting from configDir and returns
+// absolute paths of configDir and all transitively referenced local modules.
+// External modules are ignored.
+func getModuleDeps(configDir string, refsCache map[string][]string) ([]string, error) {
+	if configDir == "" {
+		return nil, nil
+	}
+	start, err := absPath(configDir)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{start: true}
+	toVisit := []string{start}
+	for len(toVisit) > 0 {
+		dir := toVisit[0]
+		toVisit = toVisit[1:]
+		refs, ok := refsCache[dir]
+		if !ok {
+			refs, err = findLocalModuleRefs(dir)
+			if err != nil {
+				return nil, err
+			}
+			refsCache[dir] = refs
+		}
+		for _, ref := range refs {
+			if !seen[ref] {
+				seen[ref] = true
+				toVisit = append(toVisit, ref)
+			}
+		}
+	}
+	deps := make([]string, 0, len(seen))
+	for dir := range seen {
+		deps = append(deps, dir)
+	}
+	sort.Strings(deps)
+	return deps, nil
+}
+
+// findLocalModuleRefs returns absolute paths of all local modules sourced from tfDir.
+func findLocalModuleRefs(tfDir string) ([]string, error) {
+	mod, diags := tfconfig.LoadModule(tfDir)
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+	refs := []string{}
+	for _, moduleCall := range mod.ModuleCalls {
+		if util.IsLocalModuleSource(moduleCall.Source) {
+			refs = append(refs, filepath.Clean(filepath.Join(tfDir, moduleCall.Source)))
+		}
+	}
+	return refs, nil
+}
+
+// getChangedFiles returns files changed between base and HEAD relative to the git repo root
+// along with the repo root.
+func getChangedFiles(dir, base string) (string, []string, error) {
+	root, err := gitOutput(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, err
+	}
+	diff, err := gitOutput(dir, "diff", "--name-only", fmt.Sprintf("%s...HEAD", base))
+	if err != nil {
+		return "", nil, err
+	}
+	files := []string{}
+	for _, f := range strings.Split(diff, "\n") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return root, files, nil
+}
+
+// gitOutput runs git with args in dir and returns trimmed stdout
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command(gitBin, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	op, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("error running git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(op)), nil
+}
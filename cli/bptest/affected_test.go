@@ -0,0 +1,125 @@
+package bptest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testDirAffected = "testdata/affected"
+)
+
+func TestGetAffectedTests(t *testing.T) {
+	discoverTestFile := path.Join(testDirAffected, intTestDir, discoverTestFilename)
+	tests := []bpTest{
+		getBPTest("TestAll/examples/simple", path.Join(testDirAffected, "examples/simple"), discoverTestFile, false),
+		getBPTest("TestAll/examples/external", path.Join(testDirAffected, "examples/external"), discoverTestFile, false),
+		getBPTest("TestBaz", path.Join(testDirAffected, "examples/baz"), path.Join(testDirAffected, intTestDir, "baz/baz_test.go"), false),
+		getBPTest("TestWrapped", path.Join(testDirAffected, "test/fixtures/wrapped"), path.Join(testDirAffected, intTestDir, "wrapped/wrapped_test.go"), false),
+		getBPTest("TestSkipped", path.Join(testDirAffected, "test/fixtures/wrapped"), path.Join(testDirAffected, intTestDir, "skipped/skipped_test.go"), true),
+	}
+	tcs := []struct {
+		name         string
+		changedFiles []string
+		want         []string
+	}{
+		{
+			name:         "root module",
+			changedFiles: []string{"main.tf"},
+			want:         []string{"TestAll/examples/simple"},
+		},
+		{
+			name:         "non tf file in root module",
+			changedFiles: []string{"README.md"},
+			want:         []string{"TestAll/examples/simple"},
+		},
+		{
+			name:         "transitively referenced module",
+			changedFiles: []string{"modules/bar/main.tf"},
+			want:         []string{"TestBaz", "TestWrapped"},
+		},
+		{
+			name:         "example",
+			changedFiles: []string{"examples/external/main.tf"},
+			want:         []string{"TestAll/examples/external"},
+		},
+		{
+			name:         "fixture",
+			changedFiles: []string{"test/fixtures/wrapped/main.tf"},
+			want:         []string{"TestWrapped"},
+		},
+		{
+			name:         "explicit test file",
+			changedFiles: []string{"test/integration/wrapped/wrapped_test.go"},
+			want:         []string{"TestWrapped"},
+		},
+		{
+			name:         "discover test file",
+			changedFiles: []string{"test/integration/discover_test.go"},
+			want:         []string{"TestAll/examples/external", "TestAll/examples/simple"},
+		},
+		{
+			name:         "multiple files",
+			changedFiles: []string{"main.tf", "modules/baz/main.tf"},
+			want:         []string{"TestAll/examples/simple", "TestBaz", "TestWrapped"},
+		},
+		{
+			name:         "unrelated files",
+			changedFiles: []string{".github/workflows/lint.yaml", "build/int.cloudbuild.yaml"},
+			want:         []string{},
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			got, err := getAffectedTests(testDirAffected, tests, tt.changedFiles)
+			assert.NoError(err)
+			gotNames := []string{}
+			for _, test := range got {
+				gotNames = append(gotNames, test.name)
+			}
+			assert.Equal(tt.want, gotNames)
+		})
+	}
+}
+
+func TestFindOwningModule(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		moduleDirs []string
+		want       string
+	}{
+		{
+			name:       "most specific module",
+			file:       "/bp/modules/foo/main.tf",
+			moduleDirs: []string{"/bp", "/bp/modules/foo"},
+			want:       "/bp/modules/foo",
+		},
+		{
+			name:       "nested non module dir",
+			file:       "/bp/modules/foo/templates/startup.sh",
+			moduleDirs: []string{"/bp/modules/foo"},
+			want:       "/bp/modules/foo",
+		},
+		{
+			name:       "sub module not in module dirs",
+			file:       "/bp/modules/foo/main.tf",
+			moduleDirs: []string{"/bp"},
+			want:       "",
+		},
+		{
+			name:       "outside module dirs",
+			file:       "/other/main.tf",
+			moduleDirs: []string{"/bp"},
+			want:       "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, findOwningModule(tt.file, tt.moduleDirs))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package bptest
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+)
+
+// outputExprNameRegex matches the output name at the start of an output expression e.g. network in network.id
+var outputExprNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*`)
+
+// BlueprintConnectionOutputRule checks connections reference outputs that exist in the source blueprint.
+// Only sources that can be resolved locally i.e. relative paths or sub modules of the blueprint repo are checked.
+type BlueprintConnectionOutputRule struct{}
+
+func (r *BlueprintConnectionOutputRule) name() string {
+	return "blueprint_connection_output_rule"
+}
+
+func (r *BlueprintConnectionOutputRule) enabled() bool {
+	return true
+}
+
+func (r *BlueprintConnectionOutputRule) severity() lintSeverity {
+	return severityError
+}
+
+func (r *BlueprintConnectionOutputRule) check(ctx lintContext) error {
+	if ctx.metadata == nil {
+		return nil
+	}
+
+	var errs []error
+	outputsCache := make(map[string]map[string]*tfconfig.Output)
+	repo := ctx.metadata.GetSpec().GetInfo().GetSource().GetRepo()
+	for i, v := range ctx.metadata.GetSpec().GetInterfaces().GetVariables() {
+		for j, conn := range v.GetConnections() {
+			source := conn.GetSource().GetSource()
+			outputExpr := conn.GetSpec().GetOutputExpr()
+			if source == "" || outputExpr == "" {
+				continue
+			}
+			dir, ok := resolveConnectionSource(source, repo, ctx.bpPath, ctx.rootPath)
+			if !ok {
+				Log.Info(fmt.Sprintf("skipping connection for variable %s with non local source %s", v.Name, source))
+				continue
+			}
+			connPath := fmt.Sprintf("spec.interfaces.variables[%d].connections[%d]", i, j)
+			outputs, ok := outputsCache[dir]
+			if !ok {
+				exists, err := util.Exists(dir)
+				if err != nil || !exists {
+					errs = append(errs, newLintError(connPath+".source.source", "source %s for variable %s not found at %s", source, v.Name, dir))
+					continue
+				}
+				mod, diags := tfconfig.LoadModule(dir)
+				if diags.HasErrors() {
+					errs = append(errs, newLintError(connPath+".source.source", "unable to parse source %s: %v", source, diags.Err()))
+					continue
+				}
+				outputs = mod.Outputs
+				outputsCache[dir] = outputs
+			}
+			outputName := outputExprNameRegex.FindString(outputExpr)
+			if _, ok := outputs[outputName]; !ok {
+				errs = append(errs, newLintError(connPath+".spec.outputExpr", "output %s referenced by variable %s is not defined in source %s", outputName, v.Name, source))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolveConnectionSource returns the local directory for a connection source.
+// Relative sources are resolved against bpPath and sources within repo are resolved against rootPath.
+func resolveConnectionSource(source, repo, bpPath, rootPath string) (string, bool) {
+	if util.IsLocalModuleSource(source) {
+		return filepath.Join(bpPath, source), true
+	}
+	if repo == "" {
+		return "", false
+	}
+	base, subDir, _ := strings.Cut(util.NormalizeModuleSource(source), "//")
+	if base != util.NormalizeModuleSource(repo) {
+		return "", false
+	}
+	return filepath.Join(rootPath, subDir), true
+}
@@ -0,0 +1,91 @@
+package bptest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlueprintConnectionOutputRule(t *testing.T) {
+	rootPath, err := filepath.Abs(lintTestdataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		source     string
+		outputExpr string
+		wantErr    string
+	}{
+		{
+			name:       "relative source",
+			source:     "./modules/sub",
+			outputExpr: "network_name",
+		},
+		{
+			name:       "repo sub module source with attribute",
+			source:     "github.com/GoogleCloudPlatform/terraform-google-lint//modules/sub",
+			outputExpr: "network_name.id",
+		},
+		{
+			name:       "registry sub module source",
+			source:     "GoogleCloudPlatform/lint/google//modules/sub",
+			outputExpr: "network_name",
+		},
+		{
+			name:       "missing output",
+			source:     "github.com/GoogleCloudPlatform/terraform-google-lint//modules/sub",
+			outputExpr: "network_id",
+			wantErr:    "spec.interfaces.variables[0].connections[0].spec.outputExpr: output network_id referenced by variable network_id is not defined in source github.com/GoogleCloudPlatform/terraform-google-lint//modules/sub",
+		},
+		{
+			name:       "missing source",
+			source:     "./modules/foo",
+			outputExpr: "network_name",
+			wantErr:    "spec.interfaces.variables[0].connections[0].source.source: source ./modules/foo for variable network_id not found at " + filepath.Join(rootPath, "modules/foo"),
+		},
+		{
+			name:       "external source is skipped",
+			source:     "github.com/terraform-google-modules/terraform-google-network",
+			outputExpr: "network_name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := lintContext{
+				metadata: &bpmetadata.BlueprintMetadata{
+					Spec: &bpmetadata.BlueprintMetadataSpec{
+						Info: &bpmetadata.BlueprintInfo{
+							Source: &bpmetadata.BlueprintRepoDetail{Repo: "https://github.com/GoogleCloudPlatform/terraform-google-lint.git"},
+						},
+						Interfaces: &bpmetadata.BlueprintInterface{
+							Variables: []*bpmetadata.BlueprintVariable{
+								{
+									Name: "network_id",
+									Connections: []*bpmetadata.BlueprintConnection{
+										{
+											Source: &bpmetadata.ConnectionSource{Source: tt.source},
+											Spec:   &bpmetadata.ConnectionSpec{OutputExpr: tt.outputExpr},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				bpPath:   rootPath,
+				rootPath: rootPath,
+			}
+			rule := &BlueprintConnectionOutputRule{}
+			err := rule.check(ctx)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
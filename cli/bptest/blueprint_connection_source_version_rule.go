@@ -1,10 +1,38 @@
 package bptest
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
 	"github.com/hashicorp/go-version"
 )
 
+const changelogFile = "CHANGELOG.md"
+
+var (
+	// registrySourceRegex matches registry module sources of form [hostname/]namespace/name/provider[//subdir]
+	registrySourceRegex = regexp.MustCompile(`^([a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)+/)?[a-zA-Z0-9][a-zA-Z0-9_-]*/[a-zA-Z0-9][a-zA-Z0-9_-]*/[a-z0-9]+(//[^/].*)?$`)
+	// vcsSourceRegex matches unprefixed github.com and bitbucket.org module sources
+	vcsSourceRegex = regexp.MustCompile(`^(github\.com|bitbucket\.org)/[a-zA-Z0-9_.-]+/[a-zA-Z0-9_.-]+(//[^/].*)?$`)
+	// changelogVersionRegex matches release headings in a CHANGELOG e.g. ## [1.2.3](https://...) (2023-01-01)
+	changelogVersionRegex = regexp.MustCompile(`(?m)^##\s*\[?v?(\d+\.\d+\.\d+[0-9A-Za-z.+-]*)\]?`)
+	// explicitSourcePrefixes are source prefixes for schemes supported by TF module installer
+	explicitSourcePrefixes = []string{"git::", "hg::", "s3::", "gcs::", "https://", "http://", "git@"}
+)
+
+// BlueprintConnectionSourceVersionRule checks connection sources are valid module sources
+// with valid version constraints that match released versions of locally available sources.
+// Sources are locally available if they are within the linted blueprint repo or within
+// another blueprint repo checked out next to it in a directory named after the repo e.g.
+// ../terraform-google-network for github.com/terraform-google-modules/terraform-google-network.
+// Versions of sources that are not locally available are not checked.
 type BlueprintConnectionSourceVersionRule struct{}
 
 func (r *BlueprintConnectionSourceVersionRule) name() string {
@@ -15,32 +43,159 @@ func (r *BlueprintConnectionSourceVersionRule) enabled() bool {
 	return true
 }
 
+func (r *BlueprintConnectionSourceVersionRule) severity() lintSeverity {
+	return severityError
+}
+
 func (r *BlueprintConnectionSourceVersionRule) check(ctx lintContext) error {
-	// Check if Spec or Interfaces is nil to avoid null pointer dereference
-	if ctx.metadata == nil || ctx.metadata.Spec == nil || ctx.metadata.Spec.Interfaces == nil {
-		fmt.Println("metadata, spec, or interfaces are nil")
+	if ctx.metadata == nil {
 		return nil
 	}
 
-	for _, variable := range ctx.metadata.Spec.Interfaces.Variables {
-		if variable == nil {
-			continue // Skip if variable is nil
-		}
+	var errs []error
+	// released versions by repo root, loaded once per repo
+	released := make(map[string][]*version.Version)
+	repo := ctx.metadata.GetSpec().GetInfo().GetSource().GetRepo()
+	for i, variable := range ctx.metadata.GetSpec().GetInterfaces().GetVariables() {
+		for j, conn := range variable.GetConnections() {
+			if conn.GetSource() == nil {
+				continue
+			}
+			sourcePath := fmt.Sprintf("spec.interfaces.variables[%d].connections[%d].source", i, j)
+			source := conn.Source.Source
+			if !isValidModuleSource(source) {
+				errs = append(errs, newLintError(sourcePath+".source", "variable %s has invalid connection source %q", variable.Name, source))
+				continue
+			}
 
-		for _, conn := range variable.Connections {
-			if conn == nil || conn.Source == nil {
-				continue // Skip if connection or source is nil
+			if conn.Source.Version == "" {
+				continue
+			}
+			constraints, err := version.NewConstraint(conn.Source.Version)
+			if err != nil {
+				errs = append(errs, newLintError(sourcePath+".version", "variable %s has invalid version: %v", variable.Name, err))
+				continue
 			}
 
-			if conn.Source.Version != "" {
-				_, err := version.NewConstraint(conn.Source.Version)
+			// released versions can only be checked for locally available sources
+			sourceRoot, ok := resolveSourceRepoRoot(source, repo, ctx.rootPath)
+			if !ok {
+				continue
+			}
+			versions, loaded := released[sourceRoot]
+			if !loaded {
+				versions, err = getReleasedVersions(sourceRoot)
 				if err != nil {
-					return fmt.Errorf("invalid version: %w", err)
+					Log.Warn(fmt.Sprintf("unable to find released versions in %s: %v", sourceRoot, err))
 				}
-				return nil
+				released[sourceRoot] = versions
+			}
+			if len(versions) > 0 && !anyVersionMatches(constraints, versions) {
+				errs = append(errs, newLintError(sourcePath+".version", "variable %s has version %s which matches no released version of %s", variable.Name, conn.Source.Version, source))
 			}
 		}
 	}
+	return errors.Join(errs...)
+}
 
-	return nil
+// isValidModuleSource returns whether source is a module source TF can resolve.
+func isValidModuleSource(source string) bool {
+	if source == "" || strings.TrimSpace(source) != source {
+		return false
+	}
+	if util.IsLocalModuleSource(source) {
+		return true
+	}
+	for _, prefix := range explicitSourcePrefixes {
+		if strings.HasPrefix(source, prefix) {
+			return len(source) > len(prefix)
+		}
+	}
+	return registrySourceRegex.MatchString(source) || vcsSourceRegex.MatchString(source)
+}
+
+// resolveSourceRepoRoot returns the root of the local checkout of the repo of a connection
+// source. Relative sources and sources within repo belong to the linted repo at rootPath.
+// Sources of other repos are resolved to a sibling directory of rootPath named after the repo
+// if the metadata or git remote of that directory refers to the same repo.
+func resolveSourceRepoRoot(source, repo, rootPath string) (string, bool) {
+	if rootPath == "" {
+		return "", false
+	}
+	if _, ok := resolveConnectionSource(source, repo, rootPath, rootPath); ok {
+		return rootPath, true
+	}
+	if util.IsLocalModuleSource(source) {
+		return "", false
+	}
+	base, _, _ := strings.Cut(util.NormalizeModuleSource(source), "//")
+	candidate := filepath.Join(filepath.Dir(rootPath), path.Base(base))
+	if samePath(candidate, rootPath) {
+		return "", false
+	}
+	if info, err := os.Stat(candidate); err != nil || !info.IsDir() {
+		return "", false
+	}
+	candidateRepo := ""
+	if md, err := bpmetadata.UnmarshalMetadata(candidate, metadataFile); err == nil {
+		candidateRepo = md.GetSpec().GetInfo().GetSource().GetRepo()
+	}
+	if candidateRepo == "" {
+		candidateRepo, _ = gitOutput(candidate, "remote", "get-url", "origin")
+	}
+	if candidateRepo == "" || util.NormalizeModuleSource(candidateRepo) != base {
+		return "", false
+	}
+	return candidate, true
+}
+
+// getReleasedVersions returns released versions of the blueprint at rootPath.
+// Versions are read from git tags if rootPath is a git repo root along with any CHANGELOG entries.
+func getReleasedVersions(rootPath string) ([]*version.Version, error) {
+	var versions []*version.Version
+	if top, err := gitOutput(rootPath, "rev-parse", "--show-toplevel"); err == nil && samePath(top, rootPath) {
+		tags, err := gitOutput(rootPath, "tag", "--list")
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range strings.Fields(tags) {
+			if v, err := version.NewSemver(tag); err == nil {
+				versions = append(versions, v)
+			}
+		}
+	}
+
+	changelog, err := os.ReadFile(filepath.Join(rootPath, changelogFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, m := range changelogVersionRegex.FindAllStringSubmatch(string(changelog), -1) {
+		if v, err := version.NewSemver(m[1]); err == nil {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// anyVersionMatches returns whether any of versions satisfies constraints.
+func anyVersionMatches(constraints version.Constraints, versions []*version.Version) bool {
+	for _, v := range versions {
+		if constraints.Check(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// samePath returns whether a and b refer to the same directory.
+func samePath(a, b string) bool {
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(aInfo, bInfo)
 }
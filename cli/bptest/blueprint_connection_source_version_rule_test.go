@@ -1,6 +1,8 @@
 package bptest
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
@@ -57,7 +59,7 @@ func TestBlueprintConnectionSourceVersionRule(t *testing.T) {
 								Connections: []*bpmetadata.BlueprintConnection{
 									{
 										Source: &bpmetadata.ConnectionSource{
-											Source:  "example/source",
+											Source:  "github.com/example/source",
 											Version: tt.version,
 										},
 									},
@@ -84,3 +86,164 @@ func TestBlueprintConnectionSourceVersionRule(t *testing.T) {
 		})
 	}
 }
+
+func TestBlueprintConnectionSourceVersionRuleConnections(t *testing.T) {
+	rootPath, err := filepath.Abs(lintTestdataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := func(source, version string) *bpmetadata.BlueprintConnection {
+		return &bpmetadata.BlueprintConnection{Source: &bpmetadata.ConnectionSource{Source: source, Version: version}}
+	}
+
+	tests := []struct {
+		name      string
+		variables []*bpmetadata.BlueprintVariable
+		wantErrs  []string
+	}{
+		{
+			name: "valid sources",
+			variables: []*bpmetadata.BlueprintVariable{
+				{
+					Name: "network",
+					Connections: []*bpmetadata.BlueprintConnection{
+						conn("github.com/terraform-google-modules/terraform-google-network//modules/vpc", "~> 9.0"),
+						conn("terraform-google-modules/network/google", ">= 9.0"),
+						conn("app.terraform.io/example/network/google//modules/vpc", ""),
+						conn("git::https://example.com/network.git", ""),
+						conn("./modules/sub", ""),
+						conn("github.com/GoogleCloudPlatform/terraform-google-lint//modules/sub", "~> 1.0"),
+					},
+				},
+			},
+		},
+		{
+			name: "all failures are reported",
+			variables: []*bpmetadata.BlueprintVariable{
+				{
+					Name: "network",
+					Connections: []*bpmetadata.BlueprintConnection{
+						conn("github.com/terraform-google-modules/terraform-google-network", ">= 1.0"),
+						conn("github.com/terraform-google-modules/terraform-google-network", "invalid"),
+					},
+				},
+				{
+					Name: "subnet",
+					Connections: []*bpmetadata.BlueprintConnection{
+						conn("not a source", ""),
+						conn("network", ""),
+						conn("github.com/GoogleCloudPlatform/terraform-google-lint//modules/sub", "~> 2.0"),
+					},
+				},
+			},
+			wantErrs: []string{
+				"spec.interfaces.variables[0].connections[1].source.version: variable network has invalid version: malformed constraint: invalid",
+				`spec.interfaces.variables[1].connections[0].source.source: variable subnet has invalid connection source "not a source"`,
+				`spec.interfaces.variables[1].connections[1].source.source: variable subnet has invalid connection source "network"`,
+				"spec.interfaces.variables[1].connections[2].source.version: variable subnet has version ~> 2.0 which matches no released version of github.com/GoogleCloudPlatform/terraform-google-lint//modules/sub",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := lintContext{
+				metadata: &bpmetadata.BlueprintMetadata{
+					Spec: &bpmetadata.BlueprintMetadataSpec{
+						Info: &bpmetadata.BlueprintInfo{
+							Source: &bpmetadata.BlueprintRepoDetail{Repo: "https://github.com/GoogleCloudPlatform/terraform-google-lint.git"},
+						},
+						Interfaces: &bpmetadata.BlueprintInterface{Variables: tt.variables},
+					},
+				},
+				bpPath:   rootPath,
+				rootPath: rootPath,
+			}
+			rule := &BlueprintConnectionSourceVersionRule{}
+			err := rule.check(ctx)
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var got []string
+			for _, e := range flattenErrors(err) {
+				got = append(got, e.Error())
+			}
+			assert.Equal(t, tt.wantErrs, got)
+		})
+	}
+}
+
+func TestBlueprintConnectionSourceVersionRuleOtherRepos(t *testing.T) {
+	workspace := t.TempDir()
+	rootPath := filepath.Join(workspace, "terraform-google-lint")
+	networkPath := filepath.Join(workspace, "terraform-google-network")
+	otherPath := filepath.Join(workspace, "terraform-google-vpc")
+	for _, p := range []string{rootPath, networkPath, otherPath} {
+		if err := os.MkdirAll(p, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(networkPath, changelogFile): "# Changelog\n\n## [9.1.0](https://github.com/terraform-google-modules/terraform-google-network/compare/v9.0.0...v9.1.0) (2024-03-01)\n",
+		filepath.Join(networkPath, metadataFile):  "apiVersion: blueprints.cloud.google.com/v1alpha1\nkind: BlueprintMetadata\nspec:\n  info:\n    source:\n      repo: https://github.com/terraform-google-modules/terraform-google-network.git\n",
+		// checkout of a different repo with the same name is not used
+		filepath.Join(otherPath, changelogFile): "# Changelog\n\n## [1.0.0](https://example.com) (2024-03-01)\n",
+		filepath.Join(otherPath, metadataFile):  "apiVersion: blueprints.cloud.google.com/v1alpha1\nkind: BlueprintMetadata\nspec:\n  info:\n    source:\n      repo: https://github.com/example/terraform-google-vpc.git\n",
+	}
+	for p, content := range files {
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conn := func(source, version string) *bpmetadata.BlueprintConnection {
+		return &bpmetadata.BlueprintConnection{Source: &bpmetadata.ConnectionSource{Source: source, Version: version}}
+	}
+	ctx := lintContext{
+		metadata: &bpmetadata.BlueprintMetadata{
+			Spec: &bpmetadata.BlueprintMetadataSpec{
+				Info: &bpmetadata.BlueprintInfo{
+					Source: &bpmetadata.BlueprintRepoDetail{Repo: "https://github.com/GoogleCloudPlatform/terraform-google-lint.git"},
+				},
+				Interfaces: &bpmetadata.BlueprintInterface{Variables: []*bpmetadata.BlueprintVariable{
+					{
+						Name: "network",
+						Connections: []*bpmetadata.BlueprintConnection{
+							conn("github.com/terraform-google-modules/terraform-google-network//modules/vpc", "~> 9.0"),
+							conn("terraform-google-modules/network/google", ">= 10.0"),
+							conn("github.com/terraform-google-modules/terraform-google-vpc", ">= 2.0"),
+							conn("github.com/terraform-google-modules/terraform-google-sql", ">= 2.0"),
+						},
+					},
+				}},
+			},
+		},
+		bpPath:   rootPath,
+		rootPath: rootPath,
+	}
+	rule := &BlueprintConnectionSourceVersionRule{}
+	var got []string
+	for _, e := range flattenErrors(rule.check(ctx)) {
+		got = append(got, e.Error())
+	}
+	assert.Equal(t, []string{
+		"spec.interfaces.variables[0].connections[1].source.version: variable network has version >= 10.0 which matches no released version of terraform-google-modules/network/google",
+	}, got)
+}
+
+func TestBlueprintConnectionSourceVersionRuleNilMetadata(t *testing.T) {
+	rule := &BlueprintConnectionSourceVersionRule{}
+	assert.NoError(t, rule.check(lintContext{}))
+	assert.NoError(t, rule.check(lintContext{metadata: &bpmetadata.BlueprintMetadata{}}))
+}
+
+func TestGetReleasedVersions(t *testing.T) {
+	versions, err := getReleasedVersions(lintTestdataPath)
+	assert.NoError(t, err)
+	var got []string
+	for _, v := range versions {
+		got = append(got, v.String())
+	}
+	assert.Equal(t, []string{"1.1.0", "1.0.0"}, got)
+}
@@ -0,0 +1,49 @@
+package bptest
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
+)
+
+// displaySectionsFieldPrefix is the field path prefix of display metadata sections.
+const displaySectionsFieldPrefix = "spec.ui.input.sections"
+
+// BlueprintDisplaySectionsRule checks display sections are unique and reference known parents
+// without forming cycles.
+type BlueprintDisplaySectionsRule struct{}
+
+func (r *BlueprintDisplaySectionsRule) name() string {
+	return "blueprint_display_sections_rule"
+}
+
+func (r *BlueprintDisplaySectionsRule) enabled() bool {
+	return true
+}
+
+func (r *BlueprintDisplaySectionsRule) severity() lintSeverity {
+	return severityError
+}
+
+func (r *BlueprintDisplaySectionsRule) check(ctx lintContext) error {
+	return displayReferenceErrors(ctx, func(field string) bool {
+		return strings.HasPrefix(field, displaySectionsFieldPrefix)
+	})
+}
+
+// displayReferenceErrors validates the display metadata of ctx against its core metadata,
+// if any, and returns the reference errors of fields matching include as lint errors.
+func displayReferenceErrors(ctx lintContext, include func(field string) bool) error {
+	if ctx.displayMetadata == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, e := range bpmetadata.ValidateDisplayMetadata(ctx.metadata, ctx.displayMetadata) {
+		if include(e.Field) {
+			errs = append(errs, newFileLintError(ctx.displayFilePath, e.Field, "%s", e.Message))
+		}
+	}
+	return errors.Join(errs...)
+}
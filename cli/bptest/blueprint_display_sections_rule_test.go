@@ -0,0 +1,65 @@
+package bptest
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlueprintDisplaySectionsRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections []*bpmetadata.DisplaySection
+		wantErrs []string
+	}{
+		{
+			name: "valid sections",
+			sections: []*bpmetadata.DisplaySection{
+				{Name: "general"},
+				{Name: "network", Parent: "general"},
+				{Name: "subnets", Parent: "network"},
+			},
+		},
+		{
+			name: "invalid sections",
+			sections: []*bpmetadata.DisplaySection{
+				{Name: "general"},
+				{Name: "network", Parent: "advanced"},
+				{Name: "general"},
+				{Name: "loop", Parent: "loop"},
+			},
+			wantErrs: []string{
+				"spec.ui.input.sections[2].name: section general is defined more than once",
+				"spec.ui.input.sections[1].parent: section network references unknown parent section advanced",
+				"spec.ui.input.sections[3].parent: section loop is part of a cycle of parent sections: loop -> loop",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := lintContext{
+				displayMetadata: &bpmetadata.BlueprintMetadata{
+					Spec: &bpmetadata.BlueprintMetadataSpec{
+						Ui: &bpmetadata.BlueprintUI{
+							Input: &bpmetadata.BlueprintUIInput{Sections: tt.sections},
+						},
+					},
+				},
+				displayFilePath: "metadata.display.yaml",
+			}
+			rule := &BlueprintDisplaySectionsRule{}
+			err := rule.check(ctx)
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var got []string
+			for _, e := range flattenErrors(err) {
+				got = append(got, e.Error())
+			}
+			assert.Equal(t, tt.wantErrs, got)
+		})
+	}
+}
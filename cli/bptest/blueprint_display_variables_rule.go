@@ -0,0 +1,26 @@
+package bptest
+
+import "strings"
+
+// BlueprintDisplayVariablesRule checks display variables reference variables
+// defined in metadata and sections, boolean groups and variables that exist and
+// are compatible.
+type BlueprintDisplayVariablesRule struct{}
+
+func (r *BlueprintDisplayVariablesRule) name() string {
+	return "blueprint_display_variables_rule"
+}
+
+func (r *BlueprintDisplayVariablesRule) enabled() bool {
+	return true
+}
+
+func (r *BlueprintDisplayVariablesRule) severity() lintSeverity {
+	return severityError
+}
+
+func (r *BlueprintDisplayVariablesRule) check(ctx lintContext) error {
+	return displayReferenceErrors(ctx, func(field string) bool {
+		return !strings.HasPrefix(field, displaySectionsFieldPrefix)
+	})
+}
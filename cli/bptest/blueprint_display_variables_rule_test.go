@@ -0,0 +1,93 @@
+package bptest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlueprintDisplayVariablesRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		displayVars map[string]*bpmetadata.DisplayVariable
+		sections    []*bpmetadata.DisplaySection
+		noDisplayMd bool
+		wantErrs    []string
+	}{
+		{
+			name: "valid display variables",
+			displayVars: map[string]*bpmetadata.DisplayVariable{
+				"project_id": {Name: "project_id", Section: "general"},
+				"region":     {Name: "region"},
+			},
+			sections: []*bpmetadata.DisplaySection{{Name: "general"}},
+		},
+		{
+			name:        "no display metadata",
+			noDisplayMd: true,
+		},
+		{
+			name: "unknown references",
+			displayVars: map[string]*bpmetadata.DisplayVariable{
+				"project_id": {Name: "project", Section: "advanced"},
+				"zone":       {Name: "zone"},
+			},
+			sections: []*bpmetadata.DisplaySection{{Name: "general"}},
+			wantErrs: []string{
+				"spec.ui.input.variables.project_id.name: variable project_id has mismatched name project",
+				"spec.ui.input.variables.project_id.section: variable project_id references unknown section advanced",
+				"spec.ui.input.variables.zone: variable zone is not defined in metadata",
+			},
+		},
+		{
+			name: "unknown boolean group",
+			displayVars: map[string]*bpmetadata.DisplayVariable{
+				"region": {Name: "region", BooleanGroup: "features"},
+			},
+			wantErrs: []string{
+				"spec.ui.input.variables.region.booleanGroup: variable region references unknown boolean group features",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := lintContext{
+				metadata: &bpmetadata.BlueprintMetadata{
+					Spec: &bpmetadata.BlueprintMetadataSpec{
+						Interfaces: &bpmetadata.BlueprintInterface{
+							Variables: []*bpmetadata.BlueprintVariable{{Name: "project_id"}, {Name: "region"}},
+						},
+					},
+				},
+				filePath:        "metadata.yaml",
+				displayFilePath: "metadata.display.yaml",
+			}
+			if !tt.noDisplayMd {
+				ctx.displayMetadata = &bpmetadata.BlueprintMetadata{
+					Spec: &bpmetadata.BlueprintMetadataSpec{
+						Ui: &bpmetadata.BlueprintUI{
+							Input: &bpmetadata.BlueprintUIInput{Variables: tt.displayVars, Sections: tt.sections},
+						},
+					},
+				}
+			}
+			rule := &BlueprintDisplayVariablesRule{}
+			err := rule.check(ctx)
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var got []string
+			for _, e := range flattenErrors(err) {
+				var lErr *lintError
+				assert.True(t, errors.As(e, &lErr))
+				assert.Equal(t, "metadata.display.yaml", lErr.file)
+				got = append(got, e.Error())
+			}
+			assert.Equal(t, tt.wantErrs, got)
+		})
+	}
+}
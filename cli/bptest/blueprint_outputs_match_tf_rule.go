@@ -0,0 +1,52 @@
+package bptest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// BlueprintOutputsMatchTFRule checks metadata outputs against the outputs defined in TF.
+type BlueprintOutputsMatchTFRule struct{}
+
+func (r *BlueprintOutputsMatchTFRule) name() string {
+	return "blueprint_outputs_match_tf_rule"
+}
+
+func (r *BlueprintOutputsMatchTFRule) enabled() bool {
+	return true
+}
+
+func (r *BlueprintOutputsMatchTFRule) severity() lintSeverity {
+	return severityError
+}
+
+func (r *BlueprintOutputsMatchTFRule) check(ctx lintContext) error {
+	if ctx.module == nil || ctx.metadata == nil {
+		return nil
+	}
+
+	var errs []error
+	found := make(map[string]bool)
+	for i, o := range ctx.metadata.GetSpec().GetInterfaces().GetOutputs() {
+		if o == nil {
+			continue
+		}
+		found[o.Name] = true
+		if _, ok := ctx.module.Outputs[o.Name]; !ok {
+			errs = append(errs, newLintError(fmt.Sprintf("spec.interfaces.outputs[%d].name", i), "output %s is not defined in TF", o.Name))
+		}
+	}
+
+	var missing []string
+	for name := range ctx.module.Outputs {
+		if !found[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		errs = append(errs, newLintError("spec.interfaces.outputs", "TF output %s is missing from metadata", name))
+	}
+	return errors.Join(errs...)
+}
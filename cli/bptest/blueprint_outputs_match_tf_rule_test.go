@@ -0,0 +1,58 @@
+package bptest
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlueprintOutputsMatchTFRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		outputs   []*bpmetadata.BlueprintOutput
+		tfOutputs map[string]*tfconfig.Output
+		wantErrs  []string
+	}{
+		{
+			name:      "matching outputs",
+			outputs:   []*bpmetadata.BlueprintOutput{{Name: "bucket"}, {Name: "network"}},
+			tfOutputs: map[string]*tfconfig.Output{"bucket": {Name: "bucket"}, "network": {Name: "network"}},
+		},
+		{
+			name:      "missing outputs",
+			outputs:   []*bpmetadata.BlueprintOutput{{Name: "bucket"}, {Name: "removed"}},
+			tfOutputs: map[string]*tfconfig.Output{"bucket": {Name: "bucket"}, "network": {Name: "network"}, "id": {Name: "id"}},
+			wantErrs: []string{
+				"spec.interfaces.outputs[1].name: output removed is not defined in TF",
+				"spec.interfaces.outputs: TF output id is missing from metadata",
+				"spec.interfaces.outputs: TF output network is missing from metadata",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := lintContext{
+				metadata: &bpmetadata.BlueprintMetadata{
+					Spec: &bpmetadata.BlueprintMetadataSpec{
+						Interfaces: &bpmetadata.BlueprintInterface{Outputs: tt.outputs},
+					},
+				},
+				module: &tfconfig.Module{Outputs: tt.tfOutputs},
+			}
+			rule := &BlueprintOutputsMatchTFRule{}
+			err := rule.check(ctx)
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var got []string
+			for _, e := range flattenErrors(err) {
+				got = append(got, e.Error())
+			}
+			assert.Equal(t, tt.wantErrs, got)
+		})
+	}
+}
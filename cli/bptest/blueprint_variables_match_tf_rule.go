@@ -0,0 +1,110 @@
+package bptest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// BlueprintVariablesMatchTFRule checks metadata variables against the variables defined in TF.
+type BlueprintVariablesMatchTFRule struct{}
+
+func (r *BlueprintVariablesMatchTFRule) name() string {
+	return "blueprint_variables_match_tf_rule"
+}
+
+func (r *BlueprintVariablesMatchTFRule) enabled() bool {
+	return true
+}
+
+func (r *BlueprintVariablesMatchTFRule) severity() lintSeverity {
+	return severityError
+}
+
+func (r *BlueprintVariablesMatchTFRule) check(ctx lintContext) error {
+	if ctx.module == nil || ctx.metadata == nil {
+		return nil
+	}
+
+	var errs []error
+	found := make(map[string]bool)
+	for i, v := range ctx.metadata.GetSpec().GetInterfaces().GetVariables() {
+		if v == nil {
+			continue
+		}
+		fieldPath := fmt.Sprintf("spec.interfaces.variables[%d]", i)
+		found[v.Name] = true
+		tfVar, ok := ctx.module.Variables[v.Name]
+		if !ok {
+			errs = append(errs, newLintError(fieldPath+".name", "variable %s is not defined in TF", v.Name))
+			continue
+		}
+		if normalizeTypeExpr(v.VarType) != normalizeTypeExpr(tfVar.Type) {
+			errs = append(errs, newLintError(fieldPath+".varType", "variable %s has varType %q but TF type is %q", v.Name, v.VarType, tfVar.Type))
+		}
+		if v.Required != tfVar.Required {
+			errs = append(errs, newLintError(fieldPath+".required", "variable %s has required %t but TF required is %t", v.Name, v.Required, tfVar.Required))
+		}
+		if err := compareDefaults(v.DefaultValue, tfVar.Default); err != nil {
+			errs = append(errs, newLintError(fieldPath+".defaultValue", "variable %s %v", v.Name, err))
+		}
+	}
+
+	var missing []string
+	for name := range ctx.module.Variables {
+		if !found[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		errs = append(errs, newLintError("spec.interfaces.variables", "TF variable %s is missing from metadata", name))
+	}
+	return errors.Join(errs...)
+}
+
+// normalizeTypeExpr removes whitespace from TF type expressions for comparison.
+func normalizeTypeExpr(t string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, t)
+}
+
+// compareDefaults returns an error if the metadata default does not match the TF default.
+func compareDefaults(mdDefault *structpb.Value, tfDefault interface{}) error {
+	if tfDefault == nil {
+		if !isNullValue(mdDefault) {
+			return fmt.Errorf("has defaultValue %v but TF has no default", mdDefault.AsInterface())
+		}
+		return nil
+	}
+	tfValue, err := structpb.NewValue(tfDefault)
+	if err != nil {
+		// defaults that can not be represented in metadata are not compared
+		return nil
+	}
+	if mdDefault == nil {
+		return fmt.Errorf("has no defaultValue but TF default is %v", tfDefault)
+	}
+	if !proto.Equal(mdDefault, tfValue) {
+		return fmt.Errorf("has defaultValue %v but TF default is %v", mdDefault.AsInterface(), tfDefault)
+	}
+	return nil
+}
+
+// isNullValue returns whether v is unset or an explicit null.
+func isNullValue(v *structpb.Value) bool {
+	if v == nil || v.Kind == nil {
+		return true
+	}
+	_, ok := v.Kind.(*structpb.Value_NullValue)
+	return ok
+}
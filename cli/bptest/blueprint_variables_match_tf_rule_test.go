@@ -0,0 +1,95 @@
+package bptest
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestBlueprintVariablesMatchTFRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables []*bpmetadata.BlueprintVariable
+		tfVars    map[string]*tfconfig.Variable
+		wantErrs  []string
+	}{
+		{
+			name: "matching variables",
+			variables: []*bpmetadata.BlueprintVariable{
+				{Name: "project_id", VarType: "string", Required: true},
+				{Name: "labels", VarType: "map(string)", DefaultValue: structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{"env": structpb.NewStringValue("dev")}})},
+				{Name: "rules", VarType: "list(object({ name = string }))", DefaultValue: structpb.NewListValue(&structpb.ListValue{})},
+			},
+			tfVars: map[string]*tfconfig.Variable{
+				"project_id": {Name: "project_id", Type: "string", Required: true},
+				"labels":     {Name: "labels", Type: "map(string)", Default: map[string]interface{}{"env": "dev"}},
+				"rules":      {Name: "rules", Type: "list(object({\n  name = string\n}))", Default: []interface{}{}},
+			},
+		},
+		{
+			name: "missing variables",
+			variables: []*bpmetadata.BlueprintVariable{
+				{Name: "project_id", VarType: "string", Required: true},
+				{Name: "removed", VarType: "string", Required: true},
+			},
+			tfVars: map[string]*tfconfig.Variable{
+				"project_id": {Name: "project_id", Type: "string", Required: true},
+				"region":     {Name: "region", Type: "string", Required: true},
+			},
+			wantErrs: []string{
+				"spec.interfaces.variables[1].name: variable removed is not defined in TF",
+				"spec.interfaces.variables: TF variable region is missing from metadata",
+			},
+		},
+		{
+			name: "mismatched fields",
+			variables: []*bpmetadata.BlueprintVariable{
+				{Name: "name", VarType: "number", Required: true},
+				{Name: "count", VarType: "number", DefaultValue: structpb.NewNumberValue(2)},
+				{Name: "region", VarType: "string", DefaultValue: structpb.NewStringValue("us-central1")},
+				{Name: "zone", VarType: "string"},
+			},
+			tfVars: map[string]*tfconfig.Variable{
+				"name":   {Name: "name", Type: "string", Required: false, Default: "foo"},
+				"count":  {Name: "count", Type: "number", Default: 1.0},
+				"region": {Name: "region", Type: "string", Default: nil},
+				"zone":   {Name: "zone", Type: "string", Default: "us-central1-a"},
+			},
+			wantErrs: []string{
+				`spec.interfaces.variables[0].varType: variable name has varType "number" but TF type is "string"`,
+				"spec.interfaces.variables[0].required: variable name has required true but TF required is false",
+				"spec.interfaces.variables[0].defaultValue: variable name has no defaultValue but TF default is foo",
+				"spec.interfaces.variables[1].defaultValue: variable count has defaultValue 2 but TF default is 1",
+				"spec.interfaces.variables[2].defaultValue: variable region has defaultValue us-central1 but TF has no default",
+				"spec.interfaces.variables[3].defaultValue: variable zone has no defaultValue but TF default is us-central1-a",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := lintContext{
+				metadata: &bpmetadata.BlueprintMetadata{
+					Spec: &bpmetadata.BlueprintMetadataSpec{
+						Interfaces: &bpmetadata.BlueprintInterface{Variables: tt.variables},
+					},
+				},
+				module: &tfconfig.Module{Variables: tt.tfVars},
+			}
+			rule := &BlueprintVariablesMatchTFRule{}
+			err := rule.check(ctx)
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var got []string
+			for _, e := range flattenErrors(err) {
+				got = append(got, e.Error())
+			}
+			assert.Equal(t, tt.wantErrs, got)
+		})
+	}
+}
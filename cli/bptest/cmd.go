@@ -15,6 +15,12 @@ var flags struct {
 	testDir   string
 	testStage string
 	setupVars map[string]string
+	base      string
+	lint      struct {
+		path   string
+		config string
+		format string
+	}
 }
 
 func init() {
@@ -24,10 +30,15 @@ func init() {
 	Cmd.AddCommand(convertCmd)
 	Cmd.AddCommand(initCmd)
 	Cmd.AddCommand(lintCmd)
+	Cmd.AddCommand(affectedCmd)
 
 	Cmd.PersistentFlags().StringVar(&flags.testDir, "test-dir", "", "Path to directory containing integration tests (default is computed by scanning current working directory)")
 	runCmd.Flags().StringVar(&flags.testStage, "stage", "", "Test stage to execute (default is running all stages in order - init, plan, apply, verify, teardown)")
 	runCmd.Flags().StringToStringVar(&flags.setupVars, "setup-var", map[string]string{}, "Specify outputs from the setup phase (useful with --stage=verify)")
+	lintCmd.Flags().StringVar(&flags.lint.path, "path", ".", "Path to the blueprint to lint")
+	lintCmd.Flags().StringVar(&flags.lint.config, "config", "", "Path to lint config (default is "+lintConfigFilename+" within the blueprint path if present)")
+	lintCmd.Flags().StringVar(&flags.lint.format, "format", lintFormatText, "Output format of lint results - one of text, json or sarif")
+	affectedCmd.Flags().StringVar(&flags.base, "base", "origin/main", "Git ref to compare HEAD against when detecting changed files")
 }
 
 var Cmd = &cobra.Command{
@@ -69,6 +80,41 @@ var listCmd = &cobra.Command{
 	},
 }
 
+var affectedCmd = &cobra.Command{
+	Use:   "affected",
+	Short: "list affected tests",
+	Long:  "Lists tests whose configs transitively depend on modules changed between base and HEAD. Each test name is printed on a separate line and can be passed to the run command.",
+
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tests, err := getTests(flags.testDir)
+		if err != nil {
+			return err
+		}
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		root, changedFiles, err := getChangedFiles(cwd, flags.base)
+		if err != nil {
+			return fmt.Errorf("error finding changed files: %w", err)
+		}
+		Log.Info(fmt.Sprintf("found %d changed files compared to %s", len(changedFiles), flags.base))
+		affected, err := getAffectedTests(root, tests, changedFiles)
+		if err != nil {
+			return err
+		}
+		if len(affected) < 1 {
+			Log.Warn("no affected tests discovered")
+			return nil
+		}
+		for _, t := range affected {
+			fmt.Println(t.name)
+		}
+		return nil
+	},
+}
+
 var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "run tests",
@@ -150,10 +196,14 @@ var initCmd = &cobra.Command{
 var lintCmd = &cobra.Command{
 	Use:   "lint",
 	Short: "Lints blueprint",
-	Long:  "Lints TF blueprint",
+	Long:  "Lints TF blueprint metadata for the blueprint and any sub modules",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		RunLintCommand()
-		return nil
+		cmd.SilenceUsage = true
+		return RunLintCommand(lintOptions{
+			path:       flags.lint.path,
+			configPath: flags.lint.config,
+			format:     flags.lint.format,
+		}, cmd.OutOrStdout())
 	},
 }
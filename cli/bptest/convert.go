@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	textTemplate "text/template"
 
 	"github.com/iancoleman/strcase"
 	cb "google.golang.org/api/cloudbuild/v1"
@@ -22,6 +23,10 @@ const (
 	tmplSuffix           = ".tmpl"
 	goModFilename        = "go.mod"
 	bptTestFilename      = "blueprint_test.go"
+	typedBptTestFilename = "typed_blueprint_test.go"
+	bptImportPrefix      = "github.com/GoogleCloudPlatform/cloud-foundation-toolkit/infra/blueprint-test/pkg/"
+	assertImport         = "github.com/stretchr/testify/assert"
+	projectIDOutputName  = "project_id"
 )
 
 var (
@@ -190,6 +195,67 @@ func getBPTestFromTmpl(testName string, inputs []string) (string, error) {
 	return tpl.String(), nil
 }
 
+// getTypedBPTestFromTmpl returns a blueprint test with assertions based on output types
+// and verification checks for resources managed by the test config
+func getTypedBPTestFromTmpl(testName string, outputs []testOutput, checks []testResourceCheck) (string, error) {
+	tmpl, err := getTmplFileContents(typedBptTestFilename)
+	if err != nil {
+		return "", err
+	}
+	t, err := textTemplate.New("test").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	stdImports := []string{"testing"}
+	imports := []string{bptImportPrefix + "gcloud", bptImportPrefix + "tft", assertImport}
+	// use project_id output if available, otherwise fallback to setup output
+	projectVar := "projectID"
+	projectFromSetup := true
+	usedVarNames := make(map[string]bool)
+	for _, op := range outputs {
+		usedVarNames[op.VarName] = true
+		if op.Name == projectIDOutputName && op.Kind == outputKindString {
+			projectVar = op.VarName
+			projectFromSetup = false
+		}
+	}
+	if len(checks) > 0 {
+		stdImports = []string{"fmt", "testing"}
+		imports = append([]string{bptImportPrefix + "cai"}, imports...)
+		for i := range checks {
+			if usedVarNames[checks[i].VarName] {
+				checks[i].VarName = checks[i].VarName + "Check"
+			}
+		}
+	}
+
+	var tpl bytes.Buffer
+	err = t.Execute(&tpl, struct {
+		PkgName          string
+		FnName           string
+		StdImports       []string
+		Imports          []string
+		Outputs          []testOutput
+		Checks           []testResourceCheck
+		ProjectVar       string
+		ProjectFromSetup bool
+	}{
+		PkgName:          strcase.ToSnake(testName),
+		FnName:           getTestFnName(testName),
+		StdImports:       stdImports,
+		Imports:          imports,
+		Outputs:          outputs,
+		Checks:           checks,
+		ProjectVar:       projectVar,
+		ProjectFromSetup: projectFromSetup,
+	})
+	if err != nil {
+		return "", err
+	}
+	return tpl.String(), nil
+}
+
 // writeFile writes content to file path
 func writeFile(p string, content string) error {
 	return os.WriteFile(p, []byte(content), os.ModePerm)
@@ -7,7 +7,6 @@ import (
 
 	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
 	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/infra/blueprint-test/pkg/discovery"
-	"github.com/hashicorp/terraform-config-inspect/tfconfig"
 	"github.com/iancoleman/strcase"
 )
 
@@ -49,19 +48,20 @@ func initTest(name string) error {
 		return fmt.Errorf("unable to discover test configs for %s: %w", testDir, err)
 	}
 
-	// Parse config to expose outputs within test
-	mod, diags := tfconfig.LoadModule(testCfg)
-	if diags.HasErrors() {
-		return fmt.Errorf("error parsing outputs: %w", diags)
+	// Parse config to expose outputs within test along with their types
+	outputs, err := getTestOutputs(testCfg)
+	if err != nil {
+		return err
 	}
-	outputs := make([]string, 0, len(mod.Outputs))
-	for _, op := range mod.Outputs {
-		// todo(bharathkkb): make templates type aware
-		outputs = append(outputs, op.Name)
+
+	// detect resources managed by config to scaffold verification checks
+	checks, err := getTestResourceChecks(testCfg)
+	if err != nil {
+		return err
 	}
 
 	// render and write test
-	testFile, err := getBPTestFromTmpl(name, outputs)
+	testFile, err := getTypedBPTestFromTmpl(name, outputs, checks)
 	if err != nil {
 		return fmt.Errorf("error creating blueprint test: %w", err)
 	}
@@ -28,7 +28,6 @@ func TestInitTest(t *testing.T) {
 				"test/integration/foo/foo_test.go": `package foo
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/infra/blueprint-test/pkg/gcloud"
@@ -41,10 +40,11 @@ func TestFoo(t *testing.T) {
 
 	bpt.DefineVerify(func(assert *assert.Assertions) {
 		bpt.DefaultVerify(assert)
-		
+
 		foo := bpt.GetStringOutput("foo")
+		assert.NotEmpty(foo, "foo should not be empty")
 
-		op := gcloud.Run(t,"")
+		op := gcloud.Run(t, "")
 		assert.Contains(op.Get("result").String(), "foo", "contains foo")
 	})
 
@@ -67,7 +67,6 @@ func TestFoo(t *testing.T) {
 				"test/integration/foo/foo_test.go": `package foo
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/infra/blueprint-test/pkg/gcloud"
@@ -80,10 +79,11 @@ func TestFoo(t *testing.T) {
 
 	bpt.DefineVerify(func(assert *assert.Assertions) {
 		bpt.DefaultVerify(assert)
-		
+
 		foo := bpt.GetStringOutput("foo")
+		assert.NotEmpty(foo, "foo should not be empty")
 
-		op := gcloud.Run(t,"")
+		op := gcloud.Run(t, "")
 		assert.Contains(op.Get("result").String(), "foo", "contains foo")
 	})
 
@@ -98,6 +98,66 @@ require (
 	github.com/GoogleCloudPlatform/cloud-foundation-toolkit/infra/blueprint-test v0.4.0
 	github.com/stretchr/testify v1.8.1
 )
+`,
+			},
+		},
+		{
+			name:    "typed outputs and resources",
+			bptName: "typed",
+			expectedFilesContents: map[string]string{
+				"test/integration/typed/typed_test.go": `package typed
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/infra/blueprint-test/pkg/cai"
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/infra/blueprint-test/pkg/gcloud"
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/infra/blueprint-test/pkg/tft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTyped(t *testing.T) {
+	bpt := tft.NewTFBlueprintTest(t)
+
+	bpt.DefineVerify(func(assert *assert.Assertions) {
+		bpt.DefaultVerify(assert)
+
+		bucketLabels := bpt.GetJsonOutput("bucket_labels")
+		assert.NotEmpty(bucketLabels.Map(), "bucket_labels should not be empty")
+		bucketName := bpt.GetStringOutput("bucket_name")
+		assert.NotEmpty(bucketName, "bucket_name should not be empty")
+		bucketUrls := bpt.GetJsonOutput("bucket_urls").Array()
+		assert.NotEmpty(bucketUrls, "bucket_urls should not be empty")
+		config := bpt.GetJsonOutput("config")
+		assert.True(config.Get("enabled").Exists(), "config should contain enabled")
+		assert.True(config.Get("name").Exists(), "config should contain name")
+		projectId := bpt.GetStringOutput("project_id")
+		assert.NotEmpty(projectId, "project_id should not be empty")
+		typeOutput := bpt.GetJsonOutput("type")
+		assert.True(typeOutput.Exists(), "type should be set")
+		zoneCount := bpt.GetJsonOutput("zone_count")
+		assert.True(zoneCount.Exists(), "zone_count should be set")
+		zones := bpt.GetJsonOutput("zones").Array()
+		assert.Len(zones, 2, "zones should have 2 elements")
+
+		topicList := gcloud.Runf(t, "pubsub topics list --project %s", projectId).Array()
+		assert.NotEmpty(topicList, "should have at least one topic")
+		bucketList := gcloud.Runf(t, "storage buckets list --project %s", projectId).Array()
+		assert.NotEmpty(bucketList, "should have at least one bucket")
+
+		assetTypes := []string{
+			"pubsub.googleapis.com/Topic",
+			"storage.googleapis.com/Bucket",
+		}
+		assets := cai.GetProjectResources(t, projectId, cai.WithAssetTypes(assetTypes))
+		for _, assetType := range assetTypes {
+			assert.True(assets.Get(fmt.Sprintf("#(assetType==%q)", assetType)).Exists(), "should have a %s asset", assetType)
+		}
+	})
+
+	bpt.Test()
+}
 `,
 			},
 		},
@@ -1,47 +1,181 @@
 package bptest
 
 import (
+	"errors"
 	"fmt"
-	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
 )
 
-const metadataFile = "metadata.yaml"
+const (
+	metadataFile        = "metadata.yaml"
+	metadataDisplayFile = "metadata.display.yaml"
+	lintModulesDir      = "modules"
+	lintDisableEnv      = "BLUEPRINT_LINT_DISABLE"
+)
 
-// RunLintCommand is the entry function that will run the metadata.yml lint checks.
-func RunLintCommand() {
-	dir, err := os.Getwd()
+// lintRules returns all known lint rules.
+func lintRules() []lintRule {
+	return []lintRule{
+		&BlueprintConnectionSourceVersionRule{},
+		&BlueprintVariablesMatchTFRule{},
+		&BlueprintOutputsMatchTFRule{},
+		&BlueprintConnectionOutputRule{},
+		&BlueprintDisplayVariablesRule{},
+		&BlueprintDisplaySectionsRule{},
+	}
+}
+
+// lintOptions configures a lint run.
+type lintOptions struct {
+	path       string // Path to the blueprint to lint
+	configPath string // Path to the lint config, defaults to lintConfigFilename within path
+	format     string // Output format for results
+}
+
+// RunLintCommand is the entry function that will run the metadata.yml lint checks
+// for the blueprint at path and any sub modules.
+func RunLintCommand(opts lintOptions, w io.Writer) error {
+	formatter, ok := lintFormatters[opts.format]
+	if !ok {
+		return fmt.Errorf("invalid format %s - one of %+q expected", opts.format, lintFormatNames())
+	}
+	// no checks run, so results are not reported as passed
+	if lintDisabled() {
+		Log.Warn(fmt.Sprintf("%s is set to 1. Skipping lint checks.", lintDisableEnv))
+		if opts.format == lintFormatText {
+			_, err := fmt.Fprintf(w, "Lint checks disabled by %s.\n", lintDisableEnv)
+			return err
+		}
+		return formatter(w, nil, nil)
+	}
+
+	bpPath, err := filepath.Abs(opts.path)
+	if err != nil {
+		return fmt.Errorf("error resolving path %s: %w", opts.path, err)
+	}
+
+	// config is optional unless explicitly specified
+	configPath := opts.configPath
+	optionalConfig := configPath == ""
+	if optionalConfig {
+		configPath = filepath.Join(bpPath, lintConfigFilename)
+	}
+	cfg, err := loadLintConfig(configPath, optionalConfig)
 	if err != nil {
-		fmt.Printf("Error getting current directory: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Parse medata.yaml to proto
-	metadata, err := bpmetadata.UnmarshalMetadata(dir, "/"+metadataFile)
-	metadataFile := dir + "/" + metadataFile
+	runner := &lintRunner{config: cfg}
+	for _, rule := range lintRules() {
+		runner.RegisterRule(rule)
+	}
+	if err := cfg.validateRules(runner.rules); err != nil {
+		return err
+	}
 
+	bpPaths, err := findLintBlueprintPaths(bpPath)
 	if err != nil {
-		fmt.Printf("Error parsing metadata file: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
-	ctx := lintContext{
-		metadata: metadata,
-		filePath: metadataFile,
+	results := []lintResult{}
+	for _, p := range bpPaths {
+		// Parse medata.yaml to proto
+		metadata, err := bpmetadata.UnmarshalMetadata(p, metadataFile)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				Log.Info(fmt.Sprintf("skipping %s with no %s", p, metadataFile))
+				continue
+			}
+			return fmt.Errorf("error parsing metadata file in %s: %w", p, err)
+		}
+
+		ctx := lintContext{
+			metadata: metadata,
+			filePath: filepath.Join(p, metadataFile),
+			bpPath:   p,
+			rootPath: bpPath,
+		}
+		mod, diags := tfconfig.LoadModule(p)
+		if diags.HasErrors() {
+			return fmt.Errorf("error parsing TF config in %s: %w", p, diags.Err())
+		}
+		ctx.module = mod
+
+		// display metadata is optional
+		displayMetadata, err := bpmetadata.UnmarshalMetadata(p, metadataDisplayFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error parsing display metadata file in %s: %w", p, err)
+		}
+		if err == nil {
+			ctx.displayMetadata = displayMetadata
+			ctx.displayFilePath = filepath.Join(p, metadataDisplayFile)
+		}
+		results = append(results, runner.Run(ctx)...)
 	}
 
-	runner := &lintRunner{}
-	runner.RegisterRule(&BlueprintConnectionSourceVersionRule{})
+	// report files relative to the working directory where possible
+	if cwd, err := os.Getwd(); err == nil {
+		for i := range results {
+			if rel, err := filepath.Rel(cwd, results[i].File); err == nil {
+				results[i].File = rel
+			}
+		}
+	}
+	sortLintResults(results)
+	if err := formatter(w, results, runner.rules); err != nil {
+		return fmt.Errorf("error writing lint results: %w", err)
+	}
 
-	// Run lint checks
-	errs := runner.Run(ctx)
-	if len(errs) > 0 {
-		fmt.Println("Linting failed with the following errors:")
-		for _, err := range errs {
-			fmt.Println("- ", err)
+	errCount := 0
+	for _, r := range results {
+		if r.Severity == severityError {
+			errCount++
 		}
-		os.Exit(1)
-	} else {
-		fmt.Println("All lint checks passed!")
 	}
+	if errCount > 0 {
+		return fmt.Errorf("linting failed with %d error(s)", errCount)
+	}
+	return nil
+}
+
+// lintDisabled returns whether lint checks are disabled with the lintDisableEnv env var.
+func lintDisabled() bool {
+	return os.Getenv(lintDisableEnv) == "1"
+}
+
+// findLintBlueprintPaths returns bpPath along with paths to any sub modules within modules/.
+func findLintBlueprintPaths(bpPath string) ([]string, error) {
+	paths := []string{bpPath}
+	modulesPath := filepath.Join(bpPath, lintModulesDir)
+	exists, err := util.Exists(modulesPath)
+	if err != nil || !exists {
+		return paths, err
+	}
+	moduleDirs, err := util.WalkTerraformDirs(modulesPath)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(moduleDirs)
+	return append(paths, moduleDirs...), nil
+}
+
+// sortLintResults sorts results by file and location
+func sortLintResults(results []lintResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].File != results[j].File {
+			return results[i].File < results[j].File
+		}
+		if results[i].Line != results[j].Line {
+			return results[i].Line < results[j].Line
+		}
+		return results[i].Column < results[j].Column
+	})
 }
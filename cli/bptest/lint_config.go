@@ -0,0 +1,91 @@
+package bptest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// lintConfigFilename is the default lint config file name looked up in the blueprint root.
+const lintConfigFilename = ".blueprint-lint.yaml"
+
+// lintConfig allows rules to be enabled, disabled or have their severity overridden.
+//
+//	rules:
+//	  blueprint_connection_source_version_rule:
+//	    enabled: false
+//	    severity: warning
+type lintConfig struct {
+	Rules map[string]lintRuleConfig `json:"rules,omitempty"`
+}
+
+// lintRuleConfig holds overrides for a single rule.
+type lintRuleConfig struct {
+	Enabled  *bool        `json:"enabled,omitempty"`
+	Severity lintSeverity `json:"severity,omitempty"`
+}
+
+// loadLintConfig loads lint config from configPath.
+// An empty config is returned if optional is set and configPath does not exist.
+func loadLintConfig(configPath string, optional bool) (*lintConfig, error) {
+	cfg := &lintConfig{}
+	b, err := os.ReadFile(configPath)
+	if err != nil {
+		if optional && errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("error reading lint config: %w", err)
+	}
+	if err := yaml.UnmarshalStrict(b, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing lint config %s: %w", configPath, err)
+	}
+	for name, ruleCfg := range cfg.Rules {
+		if ruleCfg.Severity != "" && !isValidSeverity(ruleCfg.Severity) {
+			return nil, fmt.Errorf("invalid severity %s for rule %s - one of %+q expected", ruleCfg.Severity, name, []lintSeverity{severityError, severityWarning, severityInfo})
+		}
+	}
+	return cfg, nil
+}
+
+// validateRules ensures all configured rules are known.
+func (c *lintConfig) validateRules(rules []lintRule) error {
+	if c == nil {
+		return nil
+	}
+	known := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		known[rule.name()] = true
+	}
+	for name := range c.Rules {
+		if !known[name] {
+			return fmt.Errorf("unknown lint rule %s in config", name)
+		}
+	}
+	return nil
+}
+
+// isEnabled returns whether rule should be run, falling back to the rule default.
+func (c *lintConfig) isEnabled(rule lintRule) bool {
+	if c != nil {
+		if ruleCfg, ok := c.Rules[rule.name()]; ok && ruleCfg.Enabled != nil {
+			return *ruleCfg.Enabled
+		}
+	}
+	return rule.enabled()
+}
+
+// severityFor returns the severity for results of rule, falling back to the rule default.
+func (c *lintConfig) severityFor(rule lintRule) lintSeverity {
+	if c != nil {
+		if ruleCfg, ok := c.Rules[rule.name()]; ok && ruleCfg.Severity != "" {
+			return ruleCfg.Severity
+		}
+	}
+	return rule.severity()
+}
+
+func isValidSeverity(s lintSeverity) bool {
+	return s == severityError || s == severityWarning || s == severityInfo
+}
@@ -1,27 +1,82 @@
 package bptest
 
 import (
+	"errors"
 	"fmt"
-	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
 	"os"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/util"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+)
+
+// lintSeverity is the severity level of a lint result.
+type lintSeverity string
+
+const (
+	severityError   lintSeverity = "error"
+	severityWarning lintSeverity = "warning"
+	severityInfo    lintSeverity = "info"
 )
 
 // lintRule defines the common interface for all metadata lint rules.
 type lintRule interface {
 	name() string            // Unique name of the rule
 	enabled() bool           // Indicates if the rule is enabled by default
+	severity() lintSeverity  // Default severity of results reported by the rule
 	check(lintContext) error // Main entrypoint for rule validation
 }
 
 // LintContext holds the metadata and other contextual information for a rule.
 type lintContext struct {
-	metadata *bpmetadata.BlueprintMetadata // Parsed metadata for the blueprint
-	filePath string                        // Path of the metadata file being checked
+	metadata        *bpmetadata.BlueprintMetadata // Parsed metadata for the blueprint
+	filePath        string                        // Path of the metadata file being checked
+	bpPath          string                        // Path of the blueprint the metadata belongs to
+	rootPath        string                        // Path of the root blueprint being linted
+	module          *tfconfig.Module              // Parsed TF config for the blueprint, nil if unavailable
+	displayMetadata *bpmetadata.BlueprintMetadata // Parsed display metadata for the blueprint, nil if absent
+	displayFilePath string                        // Path of the display metadata file
+}
+
+// lintError is a rule failure for a specific field in the metadata file.
+// Rules can return multiple failures using errors.Join.
+type lintError struct {
+	file      string // Path of the file containing the field, defaults to the metadata file being checked
+	fieldPath string // Dot separated path to the field e.g. spec.interfaces.variables[0].name
+	msg       string
+}
+
+func (e *lintError) Error() string {
+	if e.fieldPath == "" {
+		return e.msg
+	}
+	return fmt.Sprintf("%s: %s", e.fieldPath, e.msg)
+}
+
+// newLintError returns a lintError for fieldPath.
+func newLintError(fieldPath string, format string, a ...any) *lintError {
+	return &lintError{fieldPath: fieldPath, msg: fmt.Sprintf(format, a...)}
+}
+
+// newFileLintError returns a lintError for fieldPath within file.
+func newFileLintError(file, fieldPath string, format string, a ...any) *lintError {
+	return &lintError{file: file, fieldPath: fieldPath, msg: fmt.Sprintf(format, a...)}
+}
+
+// lintResult is a single failure reported by a rule.
+type lintResult struct {
+	Rule     string       `json:"rule"`
+	Severity lintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+	File     string       `json:"file"`
+	Line     int          `json:"line,omitempty"`
+	Column   int          `json:"column,omitempty"`
 }
 
 // LintRunner is responsible for running all registered lint rules.
 type lintRunner struct {
-	rules []lintRule
+	rules  []lintRule
+	config *lintConfig
 }
 
 // RegisterRule adds a new rule to the runner.
@@ -29,21 +84,64 @@ func (r *lintRunner) RegisterRule(rule lintRule) {
 	r.rules = append(r.rules, rule)
 }
 
-// Run runs all the registered rules on the provided context.
-func (r *lintRunner) Run(ctx lintContext) []error {
-	var errs []error
-	if os.Getenv("BLUEPRINT_LINT_DISABLE") == "1" {
-		fmt.Println("BLUEPRINT_LINT_DISABLE is set to 1. Skipping lint checks.")
-		return errs
+// Run runs all the enabled rules on the provided context.
+func (r *lintRunner) Run(ctx lintContext) []lintResult {
+	var results []lintResult
+
+	// read files once to map field paths to locations
+	contents := make(map[string][]byte)
+	readContent := func(file string) []byte {
+		if _, ok := contents[file]; !ok {
+			contents[file], _ = os.ReadFile(file)
+		}
+		return contents[file]
 	}
 
 	for _, rule := range r.rules {
-		if rule.enabled() {
-			err := rule.check(ctx)
-			if err != nil {
-				errs = append(errs, err)
+		if !r.config.isEnabled(rule) {
+			continue
+		}
+		err := rule.check(ctx)
+		if err == nil {
+			continue
+		}
+		for _, e := range flattenErrors(err) {
+			result := lintResult{
+				Rule:     rule.name(),
+				Severity: r.config.severityFor(rule),
+				Message:  e.Error(),
+				File:     ctx.filePath,
+			}
+			var lErr *lintError
+			if !errors.As(e, &lErr) {
+				results = append(results, result)
+				continue
 			}
+			if lErr.file != "" {
+				result.File = lErr.file
+			}
+			if content := readContent(result.File); lErr.fieldPath != "" && len(content) > 0 {
+				line, column, err := util.FindYAMLPosition(content, lErr.fieldPath)
+				if err == nil {
+					result.Line = line
+					result.Column = column
+				}
+			}
+			results = append(results, result)
 		}
 	}
+	return results
+}
+
+// flattenErrors returns individual errors joined with errors.Join.
+func flattenErrors(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, flattenErrors(e)...)
+	}
 	return errs
 }
@@ -2,19 +2,17 @@ package bptest
 
 import (
 	"errors"
-	"os"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
 	"github.com/stretchr/testify/assert"
 )
 
-const BlueprintLintDisableEnv = "BLUEPRINT_LINT_DISABLE"
-
 type mockLintRule struct {
-	Name    string
-	Enabled bool
-	Err     error
+	Name     string
+	Enabled  bool
+	Severity lintSeverity
+	Err      error
 }
 
 func (m *mockLintRule) name() string {
@@ -25,6 +23,13 @@ func (m *mockLintRule) enabled() bool {
 	return m.Enabled
 }
 
+func (m *mockLintRule) severity() lintSeverity {
+	if m.Severity == "" {
+		return severityError
+	}
+	return m.Severity
+}
+
 func (m *mockLintRule) check(ctx lintContext) error {
 	return m.Err
 }
@@ -47,7 +52,9 @@ func TestLintRunner(t *testing.T) {
 
 		errs := runner.Run(ctx)
 		assert.Len(t, errs, 1, "Only one rule should return an error")
-		assert.Equal(t, "lint error", errs[0].Error(), "Error message should match the expected lint error")
+		assert.Equal(t, "lint error", errs[0].Message, "Error message should match the expected lint error")
+		assert.Equal(t, "MockRule2", errs[0].Rule)
+		assert.Equal(t, severityError, errs[0].Severity)
 	})
 
 	t.Run("run without registered rules", func(t *testing.T) {
@@ -60,27 +67,59 @@ func TestLintRunner(t *testing.T) {
 		errs := runner.Run(ctx)
 		assert.Empty(t, errs, "No errors should be returned when no rules are registered")
 	})
-	t.Run("skip lint rules when BLUEPRINT_LINT_DISABLE is set", func(t *testing.T) {
-		err := os.Setenv(BlueprintLintDisableEnv, "1")
-		assert.NoError(t, err)
-		defer func() {
-			err := os.Unsetenv(BlueprintLintDisableEnv)
-			assert.NoError(t, err)
-		}()
-
+	t.Run("config overrides enabled and severity", func(t *testing.T) {
+		enabled, disabled := true, false
 		mockRule1 := &mockLintRule{Name: "MockRule1", Enabled: true, Err: errors.New("lint error")}
-		mockRule2 := &mockLintRule{Name: "MockRule2", Enabled: true, Err: errors.New("another lint error")}
-
-		runner := lintRunner{}
+		mockRule2 := &mockLintRule{Name: "MockRule2", Enabled: false, Err: errors.New("another lint error")}
+		mockRule3 := &mockLintRule{Name: "MockRule3", Enabled: true, Err: errors.New("disabled lint error")}
+
+		runner := lintRunner{config: &lintConfig{Rules: map[string]lintRuleConfig{
+			"MockRule1": {Severity: severityWarning},
+			"MockRule2": {Enabled: &enabled},
+			"MockRule3": {Enabled: &disabled},
+		}}}
 		runner.RegisterRule(mockRule1)
 		runner.RegisterRule(mockRule2)
+		runner.RegisterRule(mockRule3)
 
 		ctx := lintContext{
 			metadata: &bpmetadata.BlueprintMetadata{ApiVersion: "v1", Kind: "Blueprint"},
 			filePath: "/path/to/metadata/file.yaml",
 		}
 
-		errs := runner.Run(ctx)
-		assert.Empty(t, errs, "No errors should be returned when BLUEPRINT_LINT_DISABLE is set")
+		results := runner.Run(ctx)
+		assert.Equal(t, []lintResult{
+			{Rule: "MockRule1", Severity: severityWarning, Message: "lint error", File: "/path/to/metadata/file.yaml"},
+			{Rule: "MockRule2", Severity: severityError, Message: "another lint error", File: "/path/to/metadata/file.yaml"},
+		}, results)
+	})
+
+	t.Run("joined lint errors are reported with locations", func(t *testing.T) {
+		mockRule := &mockLintRule{Name: "MockRule", Enabled: true, Err: errors.Join(
+			newLintError("spec.interfaces.variables[1].connections[0].source.version", "invalid version"),
+			newLintError("spec.interfaces.variables[2].name", "bad name"),
+			errors.New("generic error"),
+			newFileLintError("testdata/lint/modules/sub/metadata.yaml", "spec.interfaces.outputs[0].name", "other file"),
+		)}
+
+		runner := lintRunner{}
+		runner.RegisterRule(mockRule)
+
+		ctx := lintContext{
+			metadata: &bpmetadata.BlueprintMetadata{ApiVersion: "v1", Kind: "Blueprint"},
+			filePath: "testdata/lint/metadata.yaml",
+		}
+
+		results := runner.Run(ctx)
+		assert.Len(t, results, 4)
+		assert.Equal(t, "spec.interfaces.variables[1].connections[0].source.version: invalid version", results[0].Message)
+		assert.Equal(t, 24, results[0].Line)
+		assert.Equal(t, 15, results[0].Column)
+		assert.Equal(t, "spec.interfaces.variables[2].name: bad name", results[1].Message)
+		assert.Equal(t, 25, results[1].Line)
+		assert.Equal(t, "generic error", results[2].Message)
+		assert.Zero(t, results[2].Line)
+		assert.Equal(t, "testdata/lint/modules/sub/metadata.yaml", results[3].File)
+		assert.Equal(t, 19, results[3].Line)
 	})
 }
@@ -0,0 +1,157 @@
+package bptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	lintFormatText  = "text"
+	lintFormatJSON  = "json"
+	lintFormatSARIF = "sarif"
+
+	sarifVersion  = "2.1.0"
+	sarifSchema   = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName = "cft-blueprint-lint"
+)
+
+// lintFormatter writes lint results for rules to w.
+type lintFormatter func(w io.Writer, results []lintResult, rules []lintRule) error
+
+var lintFormatters = map[string]lintFormatter{
+	lintFormatText:  writeLintText,
+	lintFormatJSON:  writeLintJSON,
+	lintFormatSARIF: writeLintSARIF,
+}
+
+// lintFormatNames returns names of all supported formats
+func lintFormatNames() []string {
+	names := make([]string, 0, len(lintFormatters))
+	for name := range lintFormatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeLintText writes results in a file:line:column: severity: message [rule] format.
+func writeLintText(w io.Writer, results []lintResult, _ []lintRule) error {
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(w, "All lint checks passed!")
+		return err
+	}
+	for _, r := range results {
+		location := r.File
+		if r.Line > 0 {
+			location = fmt.Sprintf("%s:%d:%d", r.File, r.Line, r.Column)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s: %s [%s]\n", location, r.Severity, r.Message, r.Rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLintJSON writes results as a JSON array.
+func writeLintJSON(w io.Writer, results []lintResult, _ []lintRule) error {
+	if results == nil {
+		results = []lintResult{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+// SARIF types cover the subset of https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html used for reporting.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// writeLintSARIF writes results as a SARIF log which can be consumed by code scanning tools.
+func writeLintSARIF(w io.Writer, results []lintResult, rules []lintRule) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: sarifToolName, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	for _, rule := range rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: rule.name()})
+	}
+	for _, r := range results {
+		loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(r.File)}}
+		if r.Line > 0 {
+			loc.Region = &sarifRegion{StartLine: r.Line, StartColumn: r.Column}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    r.Rule,
+			Level:     sarifLevel(r.Severity),
+			Message:   sarifMessage{Text: r.Message},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}})
+}
+
+// sarifLevel maps a lint severity to a SARIF level
+func sarifLevel(s lintSeverity) string {
+	switch s {
+	case severityWarning:
+		return "warning"
+	case severityInfo:
+		return "note"
+	}
+	return "error"
+}
@@ -0,0 +1,147 @@
+package bptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const lintTestdataPath = "testdata/lint"
+
+func TestRunLintCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		format   string
+		wantOut  string
+		errMsg   string
+		validate func(*testing.T, []byte)
+	}{
+		{
+			name:    "text",
+			format:  lintFormatText,
+			wantOut: "testdata/lint/metadata.yaml:24:15: error: spec.interfaces.variables[1].connections[0].source.version: variable network_id has invalid version: malformed constraint: invalid-version [blueprint_connection_source_version_rule]\n",
+			errMsg:  "linting failed with 1 error(s)",
+		},
+		{
+			name:    "warning severity does not fail",
+			config:  "rules:\n  blueprint_connection_source_version_rule:\n    severity: warning\n",
+			format:  lintFormatText,
+			wantOut: "testdata/lint/metadata.yaml:24:15: warning: spec.interfaces.variables[1].connections[0].source.version: variable network_id has invalid version: malformed constraint: invalid-version [blueprint_connection_source_version_rule]\n",
+		},
+		{
+			name:    "disabled rule",
+			config:  "rules:\n  blueprint_connection_source_version_rule:\n    enabled: false\n",
+			format:  lintFormatText,
+			wantOut: "All lint checks passed!\n",
+		},
+		{
+			name:   "unknown rule in config",
+			config: "rules:\n  foo:\n    enabled: false\n",
+			format: lintFormatText,
+			errMsg: "unknown lint rule foo in config",
+		},
+		{
+			name:   "invalid severity in config",
+			config: "rules:\n  blueprint_connection_source_version_rule:\n    severity: fatal\n",
+			format: lintFormatText,
+			errMsg: "invalid severity fatal",
+		},
+		{
+			name:   "invalid format",
+			format: "xml",
+			errMsg: "invalid format xml",
+		},
+		{
+			name:   "json",
+			format: lintFormatJSON,
+			errMsg: "linting failed with 1 error(s)",
+			validate: func(t *testing.T, out []byte) {
+				var results []lintResult
+				assert.NoError(t, json.Unmarshal(out, &results))
+				assert.Len(t, results, 1)
+				assert.Equal(t, "blueprint_connection_source_version_rule", results[0].Rule)
+				assert.Equal(t, "testdata/lint/metadata.yaml", results[0].File)
+				assert.Equal(t, "spec.interfaces.variables[1].connections[0].source.version: variable network_id has invalid version: malformed constraint: invalid-version", results[0].Message)
+			},
+		},
+		{
+			name:   "sarif",
+			format: lintFormatSARIF,
+			errMsg: "linting failed with 1 error(s)",
+			validate: func(t *testing.T, out []byte) {
+				var log sarifLog
+				assert.NoError(t, json.Unmarshal(out, &log))
+				assert.Equal(t, sarifVersion, log.Version)
+				assert.Len(t, log.Runs, 1)
+				assert.Contains(t, log.Runs[0].Tool.Driver.Rules, sarifRule{ID: "blueprint_connection_source_version_rule"})
+				assert.Len(t, log.Runs[0].Results, 1)
+				r := log.Runs[0].Results[0]
+				assert.Equal(t, "error", r.Level)
+				assert.Equal(t, "testdata/lint/metadata.yaml", r.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+				assert.Equal(t, "spec.interfaces.variables[1].connections[0].source.version: variable network_id has invalid version: malformed constraint: invalid-version", r.Message.Text)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := lintOptions{path: lintTestdataPath, format: tt.format}
+			if tt.config != "" {
+				opts.configPath = filepath.Join(t.TempDir(), lintConfigFilename)
+				if err := os.WriteFile(opts.configPath, []byte(tt.config), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var out bytes.Buffer
+			err := RunLintCommand(opts, &out)
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantOut != "" {
+				assert.Equal(t, tt.wantOut, out.String())
+			}
+			if tt.validate != nil {
+				tt.validate(t, out.Bytes())
+			}
+		})
+	}
+}
+
+func TestRunLintCommandDisabled(t *testing.T) {
+	t.Setenv(lintDisableEnv, "1")
+	tests := []struct {
+		format  string
+		wantOut string
+	}{
+		{format: lintFormatText, wantOut: "Lint checks disabled by BLUEPRINT_LINT_DISABLE.\n"},
+		{format: lintFormatJSON, wantOut: "[]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+			err := RunLintCommand(lintOptions{path: lintTestdataPath, format: tt.format}, &out)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
+
+func TestFindLintBlueprintPaths(t *testing.T) {
+	bpPath, err := filepath.Abs(lintTestdataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := findLintBlueprintPaths(bpPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		bpPath,
+		filepath.Join(bpPath, "modules/nometa"),
+		filepath.Join(bpPath, "modules/sub"),
+	}, paths)
+}
@@ -0,0 +1,98 @@
+package bptest
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/cloud-foundation-toolkit/cli/bpmetadata"
+	"github.com/iancoleman/strcase"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	outputKindString  = "string"
+	outputKindNumber  = "number"
+	outputKindBool    = "bool"
+	outputKindList    = "list"
+	outputKindMap     = "map"
+	outputKindUnknown = "unknown"
+)
+
+var (
+	// identifiers that are already in use by the generated test
+	reservedIdentifiers = []string{"t", "bpt", "assert", "gcloud", "cai", "tft", "testing", "fmt", "op", "assets", "assetTypes"}
+)
+
+// testOutput represents a TF output and how it should be asserted within a generated test.
+type testOutput struct {
+	Name    string
+	VarName string
+	Kind    string
+	// Length is the expected number of elements for a list output or -1 if unknown
+	Length int
+	// Keys are the known keys for a map output
+	Keys []string
+}
+
+// getTestOutputs returns outputs for the TF config in dir sorted by name along with the inferred kind.
+func getTestOutputs(dir string) ([]testOutput, error) {
+	types, err := bpmetadata.InferOutputTypes(dir, bpmetadata.OutputTypeOptions{MetadataTypes: true})
+	if err != nil {
+		return nil, fmt.Errorf("error inferring output types: %w", err)
+	}
+	outputs := make([]testOutput, 0, len(types))
+	for name, t := range types {
+		outputs = append(outputs, newTestOutput(name, t))
+	}
+	sort.SliceStable(outputs, func(i, j int) bool { return outputs[i].Name < outputs[j].Name })
+
+	// outputs such as foo_bar and fooBar map to the same identifier
+	used := make(map[string]bool)
+	for i := range outputs {
+		v := outputs[i].VarName
+		for n := 2; used[v]; n++ {
+			v = fmt.Sprintf("%s%d", outputs[i].VarName, n)
+		}
+		used[v] = true
+		outputs[i].VarName = v
+	}
+	return outputs, nil
+}
+
+// newTestOutput creates a testOutput for output name of type t.
+func newTestOutput(name string, t cty.Type) testOutput {
+	o := testOutput{Name: name, VarName: getOutputVarName(name), Kind: outputKindUnknown, Length: -1}
+	switch {
+	case t.Equals(cty.String):
+		o.Kind = outputKindString
+	case t.Equals(cty.Number):
+		o.Kind = outputKindNumber
+	case t.Equals(cty.Bool):
+		o.Kind = outputKindBool
+	case t.IsListType() || t.IsSetType():
+		o.Kind = outputKindList
+	case t.IsTupleType():
+		o.Kind = outputKindList
+		o.Length = len(t.TupleElementTypes())
+	case t.IsMapType():
+		o.Kind = outputKindMap
+	case t.IsObjectType():
+		o.Kind = outputKindMap
+		for k := range t.AttributeTypes() {
+			o.Keys = append(o.Keys, k)
+		}
+		sort.Strings(o.Keys)
+	}
+	return o
+}
+
+// getOutputVarName returns a go identifier for an output that does not clash with the generated test.
+func getOutputVarName(name string) string {
+	v := strcase.ToLowerCamel(name)
+	if token.IsKeyword(v) || slices.Contains(reservedIdentifiers, v) {
+		return v + "Output"
+	}
+	return v
+}
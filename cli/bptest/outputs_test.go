@@ -0,0 +1,197 @@
+package bptest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const outputsTestConfig = `
+variable "labels" {
+  type = map(string)
+}
+
+variable "config" {
+  type = object({ name = string, size = number })
+}
+
+resource "google_storage_bucket" "bucket" {
+  name     = "bucket"
+  location = "US"
+}
+
+output "foo" {
+  value = %s
+}
+`
+
+func TestGetTestOutputs(t *testing.T) {
+	tests := []struct {
+		name       string
+		expr       string
+		wantKind   string
+		wantLength int
+		wantKeys   []string
+	}{
+		{
+			name:       "string literal",
+			expr:       `"foo"`,
+			wantKind:   outputKindString,
+			wantLength: -1,
+		},
+		{
+			name:       "string template",
+			expr:       `"${var.foo}-bar"`,
+			wantKind:   outputKindString,
+			wantLength: -1,
+		},
+		{
+			name:       "bool literal",
+			expr:       `true`,
+			wantKind:   outputKindBool,
+			wantLength: -1,
+		},
+		{
+			name:       "tuple",
+			expr:       `["a", "b", "c"]`,
+			wantKind:   outputKindList,
+			wantLength: 3,
+		},
+		{
+			name:       "list function",
+			expr:       `tolist(var.foo)`,
+			wantKind:   outputKindList,
+			wantLength: -1,
+		},
+		{
+			name:       "object",
+			expr:       `{ b = 1, a = "a" }`,
+			wantKind:   outputKindMap,
+			wantLength: -1,
+			wantKeys:   []string{"a", "b"},
+		},
+		{
+			name:       "map function",
+			expr:       `merge(var.labels, { foo = "bar" })`,
+			wantKind:   outputKindMap,
+			wantLength: -1,
+		},
+		{
+			name:       "for expression with key",
+			expr:       `{ for k, v in var.labels : k => v }`,
+			wantKind:   outputKindMap,
+			wantLength: -1,
+		},
+		{
+			name:       "map variable",
+			expr:       `var.labels`,
+			wantKind:   outputKindMap,
+			wantLength: -1,
+		},
+		{
+			name:       "object variable",
+			expr:       `var.config`,
+			wantKind:   outputKindMap,
+			wantLength: -1,
+			wantKeys:   []string{"name", "size"},
+		},
+		{
+			name:       "resource attribute",
+			expr:       `google_storage_bucket.bucket.self_link`,
+			wantKind:   outputKindString,
+			wantLength: -1,
+		},
+		{
+			name:       "local",
+			expr:       `local.foo`,
+			wantKind:   outputKindUnknown,
+			wantLength: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			config := fmt.Sprintf(outputsTestConfig, tt.expr)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644))
+
+			outputs, err := getTestOutputs(dir)
+			require.NoError(t, err)
+			require.Len(t, outputs, 1)
+			got := outputs[0]
+			assert.Equal(t, tt.wantKind, got.Kind)
+			assert.Equal(t, tt.wantLength, got.Length)
+			assert.Equal(t, tt.wantKeys, got.Keys)
+		})
+	}
+}
+
+func TestGetTestOutputsMetadataTypes(t *testing.T) {
+	dir := t.TempDir()
+	config := fmt.Sprintf(outputsTestConfig, "local.foo")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644))
+	metadata := `apiVersion: blueprints.cloud.google.com/v1alpha1
+kind: BlueprintMetadata
+spec:
+  interfaces:
+    outputs:
+      - name: foo
+        type: [list, string]
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, metadataFile), []byte(metadata), 0644))
+
+	outputs, err := getTestOutputs(dir)
+	require.NoError(t, err)
+	require.Len(t, outputs, 1)
+	assert.Equal(t, outputKindList, outputs[0].Kind)
+}
+
+func TestGetTestOutputsVarNames(t *testing.T) {
+	dir := t.TempDir()
+	config := `
+output "foo_bar" {
+  value = "a"
+}
+
+output "fooBar" {
+  value = "b"
+}
+
+output "foo_bar2" {
+  value = "c"
+}
+
+output "asset_types" {
+  value = "d"
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644))
+
+	outputs, err := getTestOutputs(dir)
+	require.NoError(t, err)
+	var got []string
+	for _, o := range outputs {
+		got = append(got, o.Name+"="+o.VarName)
+	}
+	assert.Equal(t, []string{"asset_types=assetTypesOutput", "fooBar=fooBar", "foo_bar=fooBar2", "foo_bar2=fooBar22"}, got)
+}
+
+func TestGetOutputVarName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "project_id", want: "projectId"},
+		{name: "type", want: "typeOutput"},
+		{name: "assert", want: "assertOutput"},
+		{name: "fmt", want: "fmtOutput"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getOutputVarName(tt.name))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package bptest
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/iancoleman/strcase"
+)
+
+// resourceCheck describes how resources of a TF resource type can be verified.
+type resourceCheck struct {
+	// Desc is a human readable description of the resource
+	Desc string
+	// Cmd is a gcloud command listing resources of this type within a project
+	Cmd string
+	// AssetType is the Cloud Asset Inventory asset type for this resource
+	AssetType string
+}
+
+// knownResourceChecks maps common TF resource types to verification snippets.
+var knownResourceChecks = map[string]resourceCheck{
+	"google_storage_bucket":               {Desc: "bucket", Cmd: "storage buckets list", AssetType: "storage.googleapis.com/Bucket"},
+	"google_compute_network":              {Desc: "network", Cmd: "compute networks list", AssetType: "compute.googleapis.com/Network"},
+	"google_compute_subnetwork":           {Desc: "subnetwork", Cmd: "compute networks subnets list", AssetType: "compute.googleapis.com/Subnetwork"},
+	"google_compute_firewall":             {Desc: "firewall rule", Cmd: "compute firewall-rules list", AssetType: "compute.googleapis.com/Firewall"},
+	"google_compute_router":               {Desc: "router", Cmd: "compute routers list", AssetType: "compute.googleapis.com/Router"},
+	"google_compute_instance":             {Desc: "instance", Cmd: "compute instances list", AssetType: "compute.googleapis.com/Instance"},
+	"google_compute_instance_template":    {Desc: "instance template", Cmd: "compute instance-templates list", AssetType: "compute.googleapis.com/InstanceTemplate"},
+	"google_compute_disk":                 {Desc: "disk", Cmd: "compute disks list", AssetType: "compute.googleapis.com/Disk"},
+	"google_container_cluster":            {Desc: "cluster", Cmd: "container clusters list", AssetType: "container.googleapis.com/Cluster"},
+	"google_service_account":              {Desc: "service account", Cmd: "iam service-accounts list", AssetType: "iam.googleapis.com/ServiceAccount"},
+	"google_pubsub_topic":                 {Desc: "topic", Cmd: "pubsub topics list", AssetType: "pubsub.googleapis.com/Topic"},
+	"google_pubsub_subscription":          {Desc: "subscription", Cmd: "pubsub subscriptions list", AssetType: "pubsub.googleapis.com/Subscription"},
+	"google_sql_database_instance":        {Desc: "sql instance", Cmd: "sql instances list", AssetType: "sqladmin.googleapis.com/Instance"},
+	"google_cloud_run_v2_service":         {Desc: "cloud run service", Cmd: "run services list", AssetType: "run.googleapis.com/Service"},
+	"google_cloudfunctions2_function":     {Desc: "cloud function", Cmd: "functions list", AssetType: "cloudfunctions.googleapis.com/Function"},
+	"google_secret_manager_secret":        {Desc: "secret", Cmd: "secrets list", AssetType: "secretmanager.googleapis.com/Secret"},
+	"google_artifact_registry_repository": {Desc: "artifact registry repository", Cmd: "artifacts repositories list", AssetType: "artifactregistry.googleapis.com/Repository"},
+}
+
+// testResourceCheck is a resourceCheck for a resource type detected in a test config.
+type testResourceCheck struct {
+	resourceCheck
+	ResourceType string
+	VarName      string
+}
+
+// getTestResourceChecks returns verification checks for known resource types
+// managed by the TF config in dir or any local modules it references.
+func getTestResourceChecks(dir string) ([]testResourceCheck, error) {
+	deps, err := getModuleDeps(dir, make(map[string][]string))
+	if err != nil {
+		return nil, err
+	}
+	resourceTypes := make(map[string]bool)
+	for _, d := range deps {
+		mod, diags := tfconfig.LoadModule(d)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("error parsing resources in %s: %w", d, diags)
+		}
+		for _, r := range mod.ManagedResources {
+			resourceTypes[r.Type] = true
+		}
+	}
+
+	checks := []testResourceCheck{}
+	for rt := range resourceTypes {
+		c, ok := knownResourceChecks[rt]
+		if !ok {
+			continue
+		}
+		checks = append(checks, testResourceCheck{
+			resourceCheck: c,
+			ResourceType:  rt,
+			VarName:       strcase.ToLowerCamel(fmt.Sprintf("%s_list", c.Desc)),
+		})
+	}
+	sort.SliceStable(checks, func(i, j int) bool { return checks[i].ResourceType < checks[j].ResourceType })
+	return checks, nil
+}
@@ -0,0 +1,3 @@
+module "baz" {
+  source = "../../modules/baz"
+}
@@ -0,0 +1,4 @@
+resource "google_storage_bucket" "bucket" {
+  name     = "foo"
+  location = "US"
+}
@@ -0,0 +1,6 @@
+module "bucket" {
+  source = "../../modules/bucket"
+
+  project_id = var.project_id
+  name       = "typed"
+}
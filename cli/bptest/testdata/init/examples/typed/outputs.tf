@@ -0,0 +1,34 @@
+output "project_id" {
+  value = var.project_id
+}
+
+output "bucket_name" {
+  value = module.bucket.name
+}
+
+output "bucket_labels" {
+  value = module.bucket.labels
+}
+
+output "bucket_urls" {
+  value = module.bucket.urls
+}
+
+output "zones" {
+  value = ["us-central1-a", "us-central1-b"]
+}
+
+output "config" {
+  value = {
+    name    = module.bucket.name
+    enabled = true
+  }
+}
+
+output "zone_count" {
+  value = length(var.zones)
+}
+
+output "type" {
+  value = local.type
+}
@@ -0,0 +1,7 @@
+variable "project_id" {
+  type = string
+}
+
+variable "zones" {
+  type = list(string)
+}
@@ -0,0 +1,11 @@
+resource "google_storage_bucket" "bucket" {
+  name     = var.name
+  project  = var.project_id
+  location = "US"
+  labels   = var.labels
+}
+
+resource "google_pubsub_topic" "topic" {
+  name    = var.name
+  project = var.project_id
+}
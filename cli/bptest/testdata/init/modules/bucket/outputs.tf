@@ -0,0 +1,11 @@
+output "name" {
+  value = google_storage_bucket.bucket.name
+}
+
+output "labels" {
+  value = google_storage_bucket.bucket.labels
+}
+
+output "urls" {
+  value = [for b in [google_storage_bucket.bucket] : b.url]
+}
@@ -0,0 +1,12 @@
+variable "project_id" {
+  type = string
+}
+
+variable "name" {
+  type = string
+}
+
+variable "labels" {
+  type    = map(string)
+  default = {}
+}
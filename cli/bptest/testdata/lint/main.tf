@@ -0,0 +1,11 @@
+variable "project_id" {
+  type = string
+}
+
+variable "network_id" {
+  type = string
+}
+
+variable "name" {
+  type = string
+}
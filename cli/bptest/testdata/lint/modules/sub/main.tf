@@ -0,0 +1,7 @@
+variable "network_id" {
+  type = string
+}
+
+output "network_name" {
+  value = var.network_id
+}
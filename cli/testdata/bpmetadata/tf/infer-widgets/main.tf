@@ -0,0 +1,86 @@
+variable "vm_zone" {
+  description = "Zone to deploy the instance in"
+  type        = string
+}
+
+variable "instance_size" {
+  description = "Size of the instance"
+  type        = string
+}
+
+variable "boot_disk_type" {
+  description = "Boot disk type"
+  type        = string
+}
+
+variable "boot_disk_size" {
+  description = "Boot disk size in GB"
+  type        = number
+}
+
+variable "network" {
+  description = "Network self link"
+  type        = string
+}
+
+variable "subnet" {
+  description = "Subnet self link"
+  type        = string
+}
+
+variable "gpus" {
+  description = "Number of GPUs to attach"
+  type        = number
+  default     = 0
+}
+
+variable "region" {
+  description = "Region for regional resources"
+  type        = string
+}
+
+variable "sa" {
+  description = "Service account email used by the instance"
+  type        = string
+}
+
+variable "state_bucket" {
+  description = "Bucket for state"
+  type        = string
+}
+
+variable "labels" {
+  description = "Labels applied to resources"
+  type        = map(string)
+  default     = {}
+}
+
+resource "google_compute_instance" "vm" {
+  name         = "vm"
+  zone         = var.vm_zone
+  machine_type = var.instance_size
+
+  boot_disk {
+    initialize_params {
+      type = var.boot_disk_type
+      size = var.boot_disk_size
+    }
+  }
+
+  network_interface {
+    network    = var.network
+    subnetwork = var.subnet
+  }
+
+  guest_accelerator {
+    type  = "nvidia-tesla-t4"
+    count = var.gpus
+  }
+
+  service_account {
+    email  = var.sa
+    scopes = ["cloud-platform"]
+  }
+
+  labels = var.labels
+}
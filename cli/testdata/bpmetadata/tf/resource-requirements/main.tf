@@ -0,0 +1,43 @@
+data "google_compute_network" "network" {
+  name    = "default"
+  project = var.project_id
+}
+
+resource "random_id" "suffix" {
+  byte_length = 4
+}
+
+resource "google_compute_instance" "vm" {
+  name         = "vm-${random_id.suffix.hex}"
+  project      = var.project_id
+  zone         = "us-central1-a"
+  machine_type = "e2-medium"
+
+  boot_disk {
+    initialize_params {
+      image = "debian-cloud/debian-12"
+    }
+  }
+
+  network_interface {
+    network = data.google_compute_network.network.self_link
+  }
+}
+
+resource "google_unmapped_resource" "unmapped" {
+  project = var.project_id
+}
+
+module "bucket" {
+  source     = "./modules/bucket"
+  project_id = var.project_id
+}
+
+module "remote" {
+  source  = "terraform-google-modules/network/google"
+  version = "~> 9.0"
+}
+
+variable "project_id" {
+  type = string
+}
@@ -0,0 +1,9 @@
+variable "project_id" {
+  type = string
+}
+
+resource "google_storage_bucket" "bucket" {
+  name     = "${var.project_id}-bucket"
+  project  = var.project_id
+  location = "US"
+}
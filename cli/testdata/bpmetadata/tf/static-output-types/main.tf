@@ -0,0 +1,48 @@
+variable "project_id" {
+  type = string
+}
+
+variable "names" {
+  type = list(string)
+}
+
+variable "settings" {
+  type = object({
+    size    = number
+    enabled = bool
+  })
+}
+
+locals {
+  prefix = "${var.project_id}-bucket"
+  labels = {
+    env  = "dev"
+    team = "infra"
+  }
+}
+
+resource "google_storage_bucket" "bucket" {
+  name     = local.prefix
+  location = "US"
+  project  = var.project_id
+}
+
+resource "google_storage_bucket" "buckets" {
+  count    = length(var.names)
+  name     = var.names[count.index]
+  location = "US"
+}
+
+resource "google_compute_network" "network" {
+  name = "network"
+}
+
+module "child" {
+  source = "./modules/child"
+  name   = var.project_id
+}
+
+module "remote" {
+  source  = "terraform-google-modules/network/google"
+  version = "~> 9.0"
+}
@@ -0,0 +1,11 @@
+variable "name" {
+  type = string
+}
+
+output "id" {
+  value = "projects/${var.name}"
+}
+
+output "ids" {
+  value = [for n in [var.name] : upper(n)]
+}
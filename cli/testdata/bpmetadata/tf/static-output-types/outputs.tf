@@ -0,0 +1,63 @@
+output "bucket_name" {
+  value = google_storage_bucket.bucket.name
+}
+
+output "bucket_url" {
+  value = google_storage_bucket.bucket.url
+}
+
+output "bucket_names" {
+  value = google_storage_bucket.buckets[*].name
+}
+
+output "bucket_versioning" {
+  value = google_storage_bucket.bucket.versioning
+}
+
+output "network_id" {
+  value = google_compute_network.network.id
+}
+
+output "network_mtu" {
+  value = google_compute_network.network.mtu
+}
+
+output "prefix" {
+  value = local.prefix
+}
+
+output "labels" {
+  value = tomap(local.labels)
+}
+
+output "names" {
+  value = toset(var.names)
+}
+
+output "size" {
+  value = var.settings.size
+}
+
+output "summary" {
+  value = {
+    project = var.project_id
+    count   = length(var.names)
+    enabled = var.settings.enabled
+  }
+}
+
+output "child_id" {
+  value = module.child.id
+}
+
+output "child" {
+  value = module.child
+}
+
+output "remote_network" {
+  value = module.remote.network_name
+}
+
+output "conditional" {
+  value = var.settings.enabled ? google_storage_bucket.bucket.name : null
+}
@@ -0,0 +1,24 @@
+variable "name" {
+  type     = string
+  nullable = false
+  validation {
+    condition     = length(var.name) <= 30
+    error_message = "Name must be at most 30 characters."
+  }
+}
+
+variable "password" {
+  type      = string
+  sensitive = true
+  nullable  = true
+}
+
+variable "labels" {
+  type    = map(string)
+  default = {}
+  validation {
+    condition = alltrue([
+      for k, v in var.labels : can(regex("^[a-z]", k))
+    ])
+  }
+}
@@ -0,0 +1,52 @@
+variable "name" {
+  type = string
+  validation {
+    condition     = can(regex("^[a-z][a-z0-9-]+$", var.name))
+    error_message = "Name must start with a letter."
+  }
+  validation {
+    condition     = length(var.name) >= 4 && length(var.name) <= 30
+    error_message = "Name must be 4 to 30 characters."
+  }
+}
+
+variable "size" {
+  type = number
+  validation {
+    condition     = var.size >= 10 && 100 >= var.size
+    error_message = "Size must be between 10 and 100."
+  }
+}
+
+variable "tier" {
+  type    = string
+  default = "STANDARD"
+  validation {
+    condition     = contains(["STANDARD", "PREMIUM"], var.tier)
+    error_message = "Tier must be STANDARD or PREMIUM."
+  }
+}
+
+variable "zones" {
+  type = list(string)
+  validation {
+    condition     = var.zones == null || (length(var.zones) > 0 && length(var.zones) < 4)
+    error_message = "Between 1 and 3 zones are allowed."
+  }
+}
+
+variable "labels" {
+  type = map(string)
+  validation {
+    condition     = alltrue([for k, v in var.labels : length(k) < 64])
+    error_message = "Label keys must be less than 64 characters."
+  }
+}
+
+variable "description" {
+  type = string
+  validation {
+    condition     = length(var.description) <= 256
+    error_message = "Description must be at most 256 characters."
+  }
+}
@@ -73,6 +73,12 @@ func TestFindFilesWithPattern(t *testing.T) {
 				"../testdata/bpmetadata/content/examples/acm/metadata.display.yaml",
 				"../testdata/bpmetadata/content/examples/acm/metadata.yaml",
 				"../testdata/bpmetadata/content/examples/simple_regional/metadata.yaml",
+				"../testdata/bpmetadata/graph/app/.terraform/modules/ignored/metadata.yaml",
+				"../testdata/bpmetadata/graph/app/metadata.yaml",
+				"../testdata/bpmetadata/graph/app/modules/db/metadata.yaml",
+				"../testdata/bpmetadata/graph/network/metadata.yaml",
+				"../testdata/bpmetadata/vars-schema/metadata.display.yaml",
+				"../testdata/bpmetadata/vars-schema/metadata.yaml",
 			},
 		},
 		{
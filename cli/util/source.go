@@ -0,0 +1,49 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeModuleSource converts module sources and repo URLs into a comparable
+// host/namespace/name form while preserving any // separated sub directory.
+// Registry sources of form namespace/name/provider are mapped to their conventional
+// github.com/namespace/terraform-provider-name repo.
+func NormalizeModuleSource(source string) string {
+	s := strings.ToLower(strings.TrimSpace(source))
+	s = strings.TrimPrefix(s, "git::")
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git@"} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	s, _, _ = strings.Cut(s, "?")
+	s = strings.Replace(s, ":", "/", 1)
+	s = strings.TrimPrefix(s, "registry.terraform.io/")
+
+	base, subDir, hasSubDir := strings.Cut(s, "//")
+	base = strings.TrimSuffix(strings.TrimSuffix(base, "/"), ".git")
+	if parts := strings.Split(base, "/"); len(parts) == 3 && !strings.Contains(parts[0], ".") {
+		base = fmt.Sprintf("github.com/%s/terraform-%s-%s", parts[0], parts[2], parts[1])
+	}
+	if hasSubDir {
+		return base + "//" + strings.Trim(subDir, "/")
+	}
+	return base
+}
+
+// localModuleSourcePrefixes are the prefixes terraform uses to tell local module sources
+// apart from registry and remote sources.
+var localModuleSourcePrefixes = []string{"./", "../", ".\\", "..\\"}
+
+// IsLocalModuleSource returns whether a module source refers to a local directory
+// using terraform's definition of what a local module source looks like.
+func IsLocalModuleSource(source string) bool {
+	if source == "." || source == ".." {
+		return true
+	}
+	for _, prefix := range localModuleSourcePrefixes {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+	return false
+}
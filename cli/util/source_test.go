@@ -0,0 +1,47 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeModuleSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: "https://github.com/GoogleCloudPlatform/terraform-google-lint.git", want: "github.com/googlecloudplatform/terraform-google-lint"},
+		{source: "git@github.com:GoogleCloudPlatform/terraform-google-lint.git", want: "github.com/googlecloudplatform/terraform-google-lint"},
+		{source: "git::https://github.com/foo/bar.git//modules/baz?ref=v1.0.0", want: "github.com/foo/bar//modules/baz"},
+		{source: "terraform-google-modules/network/google//modules/vpc", want: "github.com/terraform-google-modules/terraform-google-network//modules/vpc"},
+		{source: "registry.terraform.io/terraform-google-modules/network/google", want: "github.com/terraform-google-modules/terraform-google-network"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeModuleSource(tt.source))
+		})
+	}
+}
+
+func TestIsLocalModuleSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{source: "./modules/vpc", want: true},
+		{source: "../..", want: true},
+		{source: "..", want: true},
+		{source: ".", want: true},
+		{source: `..\modules\vpc`, want: true},
+		{source: "terraform-google-modules/network/google"},
+		{source: "git::https://github.com/foo/bar.git//modules/baz"},
+		{source: ".terraform/modules/vpc"},
+		{source: "modules/vpc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsLocalModuleSource(tt.source))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var fieldPathIndexRegex = regexp.MustCompile(`\[(\d+)\]`)
+
+// FindYAMLPosition returns the line and column of the node addressed by fieldPath within YAML content.
+// fieldPath is a dot separated path where list elements are addressed either with an index suffix
+// e.g. spec.interfaces.variables[0].name or as a separate segment e.g. spec.interfaces.variables.0.name.
+// If the complete path can not be found, the position of the closest existing parent is returned.
+func FindYAMLPosition(content []byte, fieldPath string) (int, int, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return 0, 0, fmt.Errorf("error parsing yaml: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return 0, 0, fmt.Errorf("empty yaml document")
+	}
+
+	node := doc.Content[0]
+	for _, segment := range splitFieldPath(fieldPath) {
+		next := childNode(node, segment)
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return node.Line, node.Column, nil
+}
+
+// splitFieldPath splits a field path into its individual segments
+// while converting index suffixes into separate segments.
+func splitFieldPath(fieldPath string) []string {
+	fieldPath = fieldPathIndexRegex.ReplaceAllString(fieldPath, ".$1")
+	segments := []string{}
+	for _, s := range strings.Split(fieldPath, ".") {
+		if s != "" && s != "(root)" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// childNode returns the child of node addressed by segment or nil if it does not exist.
+// For mappings, the key node is returned for scalar values so that the position
+// points to the field name.
+func childNode(node *yaml.Node, segment string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != segment {
+				continue
+			}
+			if node.Content[i+1].Kind == yaml.ScalarNode {
+				return node.Content[i]
+			}
+			return node.Content[i+1]
+		}
+	case yaml.SequenceNode:
+		i, err := strconv.Atoi(segment)
+		if err == nil && i >= 0 && i < len(node.Content) {
+			return node.Content[i]
+		}
+	case yaml.AliasNode:
+		return childNode(node.Alias, segment)
+	}
+	return nil
+}
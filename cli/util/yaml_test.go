@@ -0,0 +1,73 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testYAML = `apiVersion: blueprints.cloud.google.com/v1alpha1
+spec:
+  interfaces:
+    variables:
+      - name: foo
+        connections:
+          - source:
+              source: github.com/foo/bar
+              version: invalid
+      - name: bar
+`
+
+func TestFindYAMLPosition(t *testing.T) {
+	tests := []struct {
+		name       string
+		fieldPath  string
+		wantLine   int
+		wantColumn int
+	}{
+		{
+			name:       "scalar field",
+			fieldPath:  "apiVersion",
+			wantLine:   1,
+			wantColumn: 1,
+		},
+		{
+			name:       "index suffix",
+			fieldPath:  "spec.interfaces.variables[1].name",
+			wantLine:   10,
+			wantColumn: 9,
+		},
+		{
+			name:       "nested index suffix",
+			fieldPath:  "spec.interfaces.variables[0].connections[0].source.version",
+			wantLine:   9,
+			wantColumn: 15,
+		},
+		{
+			name:       "index segment",
+			fieldPath:  "spec.interfaces.variables.0.connections",
+			wantLine:   7,
+			wantColumn: 11,
+		},
+		{
+			name:       "missing field falls back to parent",
+			fieldPath:  "spec.interfaces.outputs",
+			wantLine:   4,
+			wantColumn: 5,
+		},
+		{
+			name:       "root",
+			fieldPath:  "(root)",
+			wantLine:   1,
+			wantColumn: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, column, err := FindYAMLPosition([]byte(testYAML), tt.fieldPath)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantLine, line)
+			assert.Equal(t, tt.wantColumn, column)
+		})
+	}
+}